@@ -17,19 +17,43 @@ limitations under the License.
 package main
 
 import (
+	"bufio"
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"gopkg.in/yaml.v2"
 	"k8s.io/klog/v2"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
 	"sigs.k8s.io/etcd-manager/pkg/backup"
+	"sigs.k8s.io/etcd-manager/pkg/backupcontroller"
 )
 
 func main() {
 	klog.InitFlags(nil)
 
 	backupStorePath := ""
-	flag.StringVar(&backupStorePath, "backup-store", backupStorePath, "backup store location")
+	flag.StringVar(&backupStorePath, "backup-store", backupStorePath, "backup store location; comma-separate several locations for a replicated store")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [<args>] [<command>]\n", os.Args[0])
+		fmt.Print("\n\nThese are the supported args:\n\n")
+		flag.PrintDefaults()
+		fmt.Print("\n\nThese are the supported commands: (If no command is specified 'list' will be called.)\n\n")
+		fmt.Print(`list				List backups available in the -backup-store
+info [-o json|yaml] <backup>	Print the information recorded for a backup
+download [-o <file>] <backup>	Download a backup and decompress it to a local snapshot file (default snapshot.db)
+delete [-yes] <backup>...	Remove backups, after asking for confirmation unless -yes is set
+copy -to <store> [-all] [<backup>...]	Copy backups (or with -all, every backup) to another backup store, completing backups already copied
+verify				Verify the data of every backup in the -backup-store against its recorded checksum
+prune [-dry-run]		Remove the backups that the retention policy does not keep; with -dry-run, print them instead
+rebuild-catalog			Rebuild the catalog of the -backup-store from a full listing of the store
+`)
+	}
 
 	flag.Parse()
 
@@ -40,19 +64,49 @@ func main() {
 		os.Exit(1)
 	}
 
-	err := runBackupCtl(backupStorePath)
+	command := "list"
+	args := flag.Args()
+	if len(args) != 0 {
+		command = args[0]
+		args = args[1:]
+	}
+
+	err := runBackupCtl(backupStorePath, command, args)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
 }
 
-func runBackupCtl(backupStorePath string) error {
+func runBackupCtl(backupStorePath string, command string, args []string) error {
 	backupStore, err := backup.NewStore(backupStorePath)
 	if err != nil {
 		return err
 	}
 
+	switch command {
+	case "list":
+		return runList(backupStore)
+	case "info":
+		return runInfo(backupStore, args)
+	case "download":
+		return runDownload(backupStore, args)
+	case "delete":
+		return runDelete(backupStore, args, os.Stdin)
+	case "copy":
+		return runCopy(backupStore, args)
+	case "verify":
+		return runVerify(backupStore)
+	case "prune":
+		return runPrune(backupStore, args)
+	case "rebuild-catalog":
+		return runRebuildCatalog(backupStore)
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+func runList(backupStore backup.Store) error {
 	backups, err := backupStore.ListBackups()
 	if err != nil {
 		return err
@@ -64,3 +118,312 @@ func runBackupCtl(backupStorePath string) error {
 
 	return nil
 }
+
+func runInfo(backupStore backup.Store, args []string) error {
+	flags := flag.NewFlagSet("info", flag.ContinueOnError)
+	output := flags.String("o", "json", "output format: json or yaml")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("syntax: info [-o json|yaml] <backup>")
+	}
+	name := flags.Arg(0)
+
+	info, err := backupStore.LoadInfo(name)
+	if err != nil {
+		return err
+	}
+
+	s, err := protoetcd.ToJson(info)
+	if err != nil {
+		return fmt.Errorf("error serializing backup info: %v", err)
+	}
+
+	switch *output {
+	case "json":
+		fmt.Printf("%s\n", s)
+	case "yaml":
+		// JSON is valid YAML; we go through a MapSlice to keep the field order
+		var fields yaml.MapSlice
+		if err := yaml.Unmarshal([]byte(s), &fields); err != nil {
+			return fmt.Errorf("error converting backup info to yaml: %v", err)
+		}
+		b, err := yaml.Marshal(fields)
+		if err != nil {
+			return fmt.Errorf("error converting backup info to yaml: %v", err)
+		}
+		fmt.Printf("%s", b)
+	default:
+		return fmt.Errorf("unknown output format %q, expected json or yaml", *output)
+	}
+	return nil
+}
+
+func runDownload(backupStore backup.Store, args []string) error {
+	flags := flag.NewFlagSet("download", flag.ContinueOnError)
+	output := flags.String("o", "snapshot.db", "file to write the decompressed snapshot to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("syntax: download [-o <file>] <backup>")
+	}
+	name := flags.Arg(0)
+
+	info, err := backupStore.LoadInfo(name)
+	if err != nil {
+		return err
+	}
+	codec, err := backup.CodecFor(info.Compression)
+	if err != nil {
+		return err
+	}
+
+	tempDir, err := os.MkdirTemp(filepath.Dir(*output), ".etcd-backup-download")
+	if err != nil {
+		return fmt.Errorf("error creating temp directory: %v", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			klog.Warningf("error removing temp directory %q: %v", tempDir, err)
+		}
+	}()
+
+	// DownloadBackup decrypts the data and verifies it against the recorded checksum
+	compressedFile := filepath.Join(tempDir, backup.DataFilenameFor(codec))
+	if err := backupStore.DownloadBackup(name, compressedFile); err != nil {
+		return err
+	}
+
+	snapshotFile := filepath.Join(tempDir, "snapshot.db")
+	if err := decompressFile(codec, compressedFile, snapshotFile); err != nil {
+		return err
+	}
+	if err := os.Rename(snapshotFile, *output); err != nil {
+		return fmt.Errorf("error writing %q: %v", *output, err)
+	}
+
+	fmt.Printf("downloaded %s to %s\n", name, *output)
+	return nil
+}
+
+func decompressFile(codec backup.Codec, srcFile string, destFile string) error {
+	in, err := os.Open(srcFile)
+	if err != nil {
+		return fmt.Errorf("error opening %q: %v", srcFile, err)
+	}
+	defer in.Close()
+
+	r, err := codec.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("error decompressing %q: %v", srcFile, err)
+	}
+	defer r.Close()
+
+	out, err := os.OpenFile(destFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating %q: %v", destFile, err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("error decompressing %q: %v", srcFile, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("error writing %q: %v", destFile, err)
+	}
+	return nil
+}
+
+func runDelete(backupStore backup.Store, args []string, stdin io.Reader) error {
+	flags := flag.NewFlagSet("delete", flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "delete without asking for confirmation")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("syntax: delete [-yes] <backup>...")
+	}
+	names := flags.Args()
+
+	// We check every backup before deleting any, so a typo doesn't leave a partial delete
+	now := time.Now()
+	pins, _ := backupStore.(backup.PinStore)
+	for _, name := range names {
+		if _, err := backupStore.LoadInfo(name); err != nil {
+			return err
+		}
+		if pins == nil {
+			continue
+		}
+		pin, err := pins.LoadPin(name)
+		if err != nil {
+			return err
+		}
+		if backup.IsPinned(pin, now) {
+			return fmt.Errorf("backup %q is pinned (%s); unpin it with etcd-manager-ctl unpin-backup before deleting it", name, pin.Reason)
+		}
+	}
+
+	if !*yes {
+		fmt.Printf("Delete %d backup(s) from %s?\n", len(names), backupStore.Spec())
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+		}
+		fmt.Printf("Type 'yes' to confirm: ")
+		answer, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("error reading confirmation: %v", err)
+		}
+		if strings.TrimSpace(answer) != "yes" {
+			return fmt.Errorf("delete cancelled")
+		}
+	}
+
+	for _, name := range names {
+		if err := backupStore.RemoveBackup(name); err != nil {
+			return fmt.Errorf("error removing backup %q: %v", name, err)
+		}
+		fmt.Printf("removed %s\n", name)
+	}
+	return nil
+}
+
+func runCopy(backupStore backup.Store, args []string) error {
+	flags := flag.NewFlagSet("copy", flag.ContinueOnError)
+	to := flags.String("to", "", "backup store to copy the backups to")
+	all := flags.Bool("all", false, "copy every backup")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *to == "" {
+		return fmt.Errorf("-to is required")
+	}
+	names := flags.Args()
+	if *all == (len(names) != 0) {
+		return fmt.Errorf("syntax: copy -to <store> [-all] [<backup>...]; specify either -all or the backups to copy")
+	}
+
+	destStore, err := backup.NewStore(*to)
+	if err != nil {
+		return err
+	}
+
+	if *all {
+		names, err = backupStore.ListBackups()
+		if err != nil {
+			return err
+		}
+	}
+
+	existing, err := destStore.ListBackups()
+	if err != nil {
+		return fmt.Errorf("error listing backups in %s: %v", destStore.Spec(), err)
+	}
+	present := make(map[string]bool)
+	for _, name := range existing {
+		present[name] = true
+	}
+
+	stateKey, err := backup.LoadStateKeyFromEnv()
+	if err != nil {
+		return err
+	}
+	if stateKey == nil {
+		klog.Warningf("%s is not set; archived cluster state will not be copied", backup.StateKeyFileEnv)
+	}
+
+	for _, name := range names {
+		if present[name] && backup.CopiedBackup(backupStore, destStore, name) {
+			// The data was copied by an earlier run; the pin and other extras may not have been
+			if err := backup.CopyBackupExtras(backupStore, destStore, name, stateKey); err != nil {
+				return err
+			}
+			fmt.Printf("already copied %s\n", name)
+			continue
+		}
+		if err := backup.CopyBackup(backupStore, destStore, name, stateKey); err != nil {
+			return err
+		}
+		fmt.Printf("copied %s\n", name)
+	}
+	return nil
+}
+
+func runVerify(backupStore backup.Store) error {
+	backups, err := backupStore.ListBackups()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, name := range backups {
+		info, err := backupStore.LoadInfo(name)
+		if err != nil {
+			fmt.Printf("%s\tFAILED\t%v\n", name, err)
+			failed++
+			continue
+		}
+
+		if err := backupStore.VerifyBackup(name); err != nil {
+			fmt.Printf("%s\tFAILED\t%v\n", name, err)
+			failed++
+			continue
+		}
+
+		if info.DataSha256 == "" {
+			fmt.Printf("%s\tUNVERIFIED\tno checksum recorded\n", name)
+		} else {
+			fmt.Printf("%s\tOK\tsha256=%s size=%d\n", name, info.DataSha256, info.DataSize)
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d backups failed verification", failed, len(backups))
+	}
+	return nil
+}
+
+func runRebuildCatalog(backupStore backup.Store) error {
+	catalog, ok := backupStore.(backup.Catalog)
+	if !ok {
+		return fmt.Errorf("backup store %s does not keep a catalog", backupStore.Spec())
+	}
+	if err := catalog.RebuildCatalog(); err != nil {
+		return err
+	}
+
+	backups, err := backupStore.ListBackups()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("rebuilt catalog with %d backups\n", len(backups))
+	return nil
+}
+
+func runPrune(backupStore backup.Store, args []string) error {
+	flags := flag.NewFlagSet("prune", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the backups that would be removed, without removing them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments to prune: %v", flags.Args())
+	}
+
+	ctx := context.TODO()
+	cleanup := backupcontroller.NewBackupCleanup(backupStore)
+	removed, err := cleanup.Prune(ctx, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range removed {
+		if *dryRun {
+			fmt.Printf("would remove %s\n", name)
+		} else {
+			fmt.Printf("removed %s\n", name)
+		}
+	}
+	return nil
+}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/etcd-manager/pkg/backup"
 	"sigs.k8s.io/etcd-manager/pkg/backupcontroller"
+	"sigs.k8s.io/etcd-manager/pkg/metrics"
 )
 
 func main() {
@@ -36,13 +37,25 @@ func main() {
 	clusterName := ""
 	flag.StringVar(&clusterName, "cluster-name", clusterName, "name of cluster")
 	backupStorePath := "/backups"
-	flag.StringVar(&backupStorePath, "backup-store", backupStorePath, "backup store location")
+	flag.StringVar(&backupStorePath, "backup-store", backupStorePath, "backup store location; comma-separate several locations to replicate backups")
 	dataDir := "/data"
 	flag.StringVar(&dataDir, "data-dir", dataDir, "directory for storing etcd data")
 	clientURL := "http://127.0.0.1:4001"
 	flag.StringVar(&clientURL, "client-url", clientURL, "URL on which to connect to etcd")
 	interval := "15m"
 	flag.StringVar(&interval, "interval", interval, "backup frequency")
+	schedule := ""
+	flag.StringVar(&schedule, "schedule", schedule, "cron expression (in UTC) for backups; replaces interval if set")
+	blackoutWindows := ""
+	flag.StringVar(&blackoutWindows, "blackout-windows", blackoutWindows, "comma-separated daily windows (HH:MM-HH:MM, in UTC) during which scheduled backups are deferred")
+	var jitter time.Duration
+	flag.DurationVar(&jitter, "jitter", jitter, "maximum delay added to each scheduled backup, to spread load")
+	archiveInterval := ""
+	flag.StringVar(&archiveInterval, "archive-interval", archiveInterval, "if set, continuously archive etcd revisions for point-in-time recovery, writing them at this interval")
+	drillInterval := ""
+	flag.StringVar(&drillInterval, "drill-interval", drillInterval, "if set, restore the newest backup into a sandbox etcd at this interval, to check that it can be restored")
+	metricsPort := 0
+	flag.IntVar(&metricsPort, "metrics-port", metricsPort, "if set, serve prometheus metrics on this port")
 	clientCAFile := ""
 	flag.StringVar(&clientCAFile, "client-ca-file", clientCAFile, "path to the ca certificate")
 	clientCertFile := ""
@@ -70,6 +83,36 @@ func main() {
 		os.Exit(1)
 	}
 
+	var backupSchedule *backupcontroller.BackupSchedule
+	if schedule != "" {
+		backupSchedule, err = backupcontroller.NewBackupSchedule(schedule, blackoutWindows, jitter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid schedule: %v\n", err)
+			os.Exit(1)
+		}
+	} else if blackoutWindows != "" || jitter != 0 {
+		fmt.Fprintf(os.Stderr, "blackout-windows and jitter require schedule\n")
+		os.Exit(1)
+	}
+
+	var segmentInterval time.Duration
+	if archiveInterval != "" {
+		segmentInterval, err = time.ParseDuration(archiveInterval)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot parse archive-interval %q", archiveInterval)
+			os.Exit(1)
+		}
+	}
+
+	var restoreDrillInterval time.Duration
+	if drillInterval != "" {
+		restoreDrillInterval, err = time.ParseDuration(drillInterval)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot parse drill-interval %q", drillInterval)
+			os.Exit(1)
+		}
+	}
+
 	ctx := context.TODO()
 
 	var etcdClientTLSConfig *tls.Config
@@ -104,6 +147,27 @@ func main() {
 	if err != nil {
 		klog.Fatalf("error building backup controller: %v", err)
 	}
+	c.Schedule = backupSchedule
+
+	if segmentInterval != 0 {
+		archiver, err := backupcontroller.NewRevisionArchiver(backupStore, clientURLs, etcdClientTLSConfig, segmentInterval)
+		if err != nil {
+			klog.Fatalf("error building revision archiver: %v", err)
+		}
+		go archiver.Run(ctx)
+	}
+
+	if restoreDrillInterval != 0 {
+		drill, err := backupcontroller.NewRestoreDrill(backupStore, clientURLs, etcdClientTLSConfig, restoreDrillInterval)
+		if err != nil {
+			klog.Fatalf("error building restore drill: %v", err)
+		}
+		go drill.Run(ctx)
+	}
+
+	if metricsPort != 0 {
+		go metrics.RegisterMetrics(metricsPort, "")
+	}
 
 	c.Run(ctx)
 
@@ -18,12 +18,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -40,24 +43,307 @@ func main() {
 	flag.StringVar(&datadir, "data-dir", datadir, "data dir location")
 	out := ""
 	flag.StringVar(&out, "out", out, "output file")
+	var dumpOptions DumpOptions
+	flag.StringVar(&dumpOptions.Format, "format", dumpOptions.Format, "output format: stream, tar, jsonl or etcdctl; defaults to tar with -out, and stream otherwise")
+	flag.StringVar(&dumpOptions.Prefixes, "prefix", dumpOptions.Prefixes, "comma-separated list of key prefixes to dump; all keys are dumped if not set")
+	flag.StringVar(&dumpOptions.ExcludePrefixes, "exclude-prefix", dumpOptions.ExcludePrefixes, "comma-separated list of key prefixes to leave out of the dump")
+	flag.BoolVar(&dumpOptions.KeysOnly, "keys-only", dumpOptions.KeysOnly, "dump only the keys, without their values")
+	flag.BoolVar(&dumpOptions.Base64, "base64", dumpOptions.Base64, "with -format=jsonl, base64 encode every value, not only those that are not valid UTF-8")
+	flag.StringVar(&dumpOptions.Decode, "decode", dumpOptions.Decode, "decode kubernetes objects under /registry/ to json or yaml; other values are dumped unchanged")
+	flag.BoolVar(&dumpOptions.ShowSecrets, "show-secrets", dumpOptions.ShowSecrets, "with -decode, include the data of Secrets, which is redacted by default")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [<args>] [<command>]\n", os.Args[0])
+		fmt.Print("\n\nThese are the supported args:\n\n")
+		flag.PrintDefaults()
+		fmt.Print("\n\nThese are the supported commands: (If no command is specified, the -data-dir backup is dumped, as set by -format, -prefix, -exclude-prefix, -keys-only and -decode.)\n\n")
+		fmt.Print(`diff [-prefix=<prefixes>] [-summary-only] <backupA> <backupB>
+				Lists the keys added, removed and modified between two backups, and a summary by top-level prefix.
+				Backups are named as for -data-dir, eg. s3://mybackupstore/backups/2019-05-07T18:28:01Z-000977
+				-prefix limits the comparison to keys under a comma-separated list of prefixes.
+`)
+	}
 
 	flag.Parse()
 
-	fmt.Printf("etcd-dump\n")
+	// We print to stderr, so that dumps written to stdout can be parsed
+	fmt.Fprintf(os.Stderr, "etcd-dump\n")
 
-	if datadir == "" {
-		fmt.Fprintf(os.Stderr, "data-dir is required\n")
-		os.Exit(1)
+	var err error
+	if args := flag.Args(); len(args) != 0 {
+		switch args[0] {
+		case "diff":
+			err = runDiff(args[1:])
+		case "analyze":
+			err = runAnalyze(args[1:])
+		default:
+			err = fmt.Errorf("unknown command %q", args[0])
+		}
+	} else {
+		if datadir == "" {
+			fmt.Fprintf(os.Stderr, "data-dir is required\n")
+			os.Exit(1)
+		}
+		err = runDump(datadir, out, &dumpOptions)
 	}
-
-	err := runDump(datadir, out)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
 }
 
-func runDump(backupFile string, out string) error {
+// DumpOptions controls the output of a dump
+type DumpOptions struct {
+	Format          string
+	Prefixes        string
+	ExcludePrefixes string
+	KeysOnly        bool
+	Base64          bool
+	Decode          string
+	ShowSecrets     bool
+}
+
+func runDump(backupFile string, out string, options *DumpOptions) error {
+	format := options.Format
+	if format == "" {
+		format = "stream"
+		if out != "" {
+			format = "tar"
+		}
+	}
+
+	var w io.WriteCloser = os.Stdout
+	var outFile *os.File
+	if out != "" && format != "tar" {
+		f, err := os.Create(out)
+		if err != nil {
+			return fmt.Errorf("unable to create file %q: %v", out, err)
+		}
+		defer func() {
+			if outFile != nil {
+				_ = outFile.Close()
+			}
+		}()
+		outFile = f
+		w = f
+	}
+
+	var nodeSink etcdclient.NodeSink
+	switch format {
+	case "stream":
+		sink, err := dump.NewStreamDumpSink(w)
+		if err != nil {
+			return fmt.Errorf("unable to create stream: %v", err)
+		}
+		sink.KeysOnly = options.KeysOnly
+		nodeSink = sink
+	case "tar":
+		if out == "" {
+			return fmt.Errorf("-out is required for tar output")
+		}
+		sink, err := dump.NewTarDumpSink(out)
+		if err != nil {
+			return fmt.Errorf("unable to create file %q: %v", out, err)
+		}
+		sink.KeysOnly = options.KeysOnly
+		sink.Decode = options.Decode
+		nodeSink = sink
+	case "jsonl":
+		sink := dump.NewJSONLinesSink(w)
+		sink.KeysOnly = options.KeysOnly
+		sink.Decode = options.Decode
+		sink.Base64 = options.Base64
+		nodeSink = sink
+	case "etcdctl":
+		sink := dump.NewEtcdctlSink(w)
+		sink.KeysOnly = options.KeysOnly
+		nodeSink = sink
+	default:
+		return fmt.Errorf("unknown format %q, expected stream, tar, jsonl or etcdctl", format)
+	}
+
+	if options.Decode != "" {
+		sink, err := dump.NewKubernetesDecodingSink(nodeSink, options.Decode)
+		if err != nil {
+			return err
+		}
+		sink.ShowSecrets = options.ShowSecrets
+		nodeSink = sink
+	}
+
+	if excludePrefixes := splitPrefixes(options.ExcludePrefixes); len(excludePrefixes) != 0 {
+		nodeSink = dump.NewFilterSink(nodeSink, excludePrefixes)
+	}
+
+	err := withBackup(backupFile, func(ctx context.Context, sourceClient *etcdclient.EtcdClient) error {
+		n, err := copyPrefixes(ctx, sourceClient, splitPrefixes(options.Prefixes), nodeSink)
+		if err != nil {
+			return fmt.Errorf("error copying keys to sink: %v", err)
+		}
+		klog.Infof("read %d keys", n)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := nodeSink.Close(); err != nil {
+		return err
+	}
+
+	if outFile != nil {
+		err := outFile.Close()
+		outFile = nil
+		if err != nil {
+			return fmt.Errorf("error writing %q: %v", out, err)
+		}
+	}
+
+	return nil
+}
+
+// splitPrefixes parses a comma-separated list of key prefixes
+func splitPrefixes(s string) []string {
+	var prefixes []string
+	for _, prefix := range strings.Split(s, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// copyPrefixes copies the keys under each of the prefixes to dest, or every key if there are no prefixes
+func copyPrefixes(ctx context.Context, sourceClient *etcdclient.EtcdClient, prefixes []string, dest etcdclient.NodeSink) (int, error) {
+	if len(prefixes) == 0 {
+		return sourceClient.CopyTo(ctx, dest)
+	}
+	total := 0
+	for _, prefix := range prefixes {
+		n, err := sourceClient.CopyPrefixTo(ctx, prefix, dest)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	prefixes := flags.String("prefix", "", "comma-separated list of key prefixes to compare; all keys are compared if not set")
+	summaryOnly := flags.Bool("summary-only", false, "print only the summary by top-level prefix")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("syntax: diff [-prefix=<prefixes>] [-summary-only] <backupA> <backupB>")
+	}
+
+	keyPrefixes := splitPrefixes(*prefixes)
+
+	// We read one backup at a time, so we only run one etcd at a time
+	var sinks []*dump.DigestSink
+	for _, backupFile := range flags.Args() {
+		sink := dump.NewDigestSink()
+		err := withBackup(backupFile, func(ctx context.Context, sourceClient *etcdclient.EtcdClient) error {
+			if _, err := copyPrefixes(ctx, sourceClient, keyPrefixes, sink); err != nil {
+				return fmt.Errorf("error reading keys from %s: %v", backupFile, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	diffs := dump.Diff(sinks[0], sinks[1])
+	if !*summaryOnly {
+		for _, diff := range diffs {
+			switch diff.Change {
+			case dump.KeyAdded:
+				fmt.Printf("+ %s (%d bytes)\n", diff.Key, diff.SizeAfter)
+			case dump.KeyRemoved:
+				fmt.Printf("- %s (%d bytes)\n", diff.Key, diff.SizeBefore)
+			case dump.KeyModified:
+				fmt.Printf("~ %s (%d bytes -> %d bytes)\n", diff.Key, diff.SizeBefore, diff.SizeAfter)
+			}
+		}
+		fmt.Printf("\n")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "PREFIX\tADDED\tREMOVED\tMODIFIED\n")
+	for _, summary := range dump.SummarizeDiff(diffs) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", summary.Prefix, summary.Added, summary.Removed, summary.Modified)
+	}
+	return w.Flush()
+}
+
+func runAnalyze(args []string) error {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	depth := flags.Int("depth", 2, "number of path segments used to group keys into prefixes")
+	top := flags.Int("top", 20, "number of largest and most modified keys to list")
+	prefixes := flags.String("prefix", "", "comma-separated list of key prefixes to analyze; all keys are analyzed if not set")
+	output := flags.String("o", "table", "output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("syntax: analyze [-depth=<n>] [-top=<n>] [-prefix=<prefixes>] [-o table|json] <backup>")
+	}
+	if *depth < 1 {
+		return fmt.Errorf("-depth must be at least 1")
+	}
+	if *top < 0 {
+		return fmt.Errorf("-top must not be negative")
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unknown output format %q, expected table or json", *output)
+	}
+
+	backupFile := flags.Arg(0)
+	sink := dump.NewAnalyzeSink(*depth, *top)
+	err := withBackup(backupFile, func(ctx context.Context, sourceClient *etcdclient.EtcdClient) error {
+		if _, err := copyPrefixes(ctx, sourceClient, splitPrefixes(*prefixes), sink); err != nil {
+			return fmt.Errorf("error reading keys from %s: %v", backupFile, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	analysis := sink.Analysis()
+
+	if *output == "json" {
+		b, err := json.MarshalIndent(analysis, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error serializing analysis: %v", err)
+		}
+		fmt.Printf("%s\n", b)
+		return nil
+	}
+
+	fmt.Printf("%d keys, %d bytes, %d versions, revision %d\n\n", analysis.Keys, analysis.Bytes, analysis.Versions, analysis.Revision)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "PREFIX\tKEYS\tBYTES\tVERSIONS\n")
+	for _, p := range analysis.Prefixes {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", p.Prefix, p.Keys, p.Bytes, p.Versions)
+	}
+	fmt.Fprintf(w, "\nLARGEST KEYS\tBYTES\t\t\n")
+	for _, k := range analysis.LargestKeys {
+		fmt.Fprintf(w, "%s\t%d\t\t\n", k.Key, k.Bytes)
+	}
+	fmt.Fprintf(w, "\nMOST MODIFIED KEYS\tVERSION\tCREATED\tMODIFIED\n")
+	for _, k := range analysis.MostModifiedKeys {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", k.Key, k.Version, k.CreateRevision, k.ModRevision)
+	}
+	return w.Flush()
+}
+
+// withBackup starts etcd from a backup, named as <store>/<backup>, and calls fn with a client for it
+func withBackup(backupFile string, fn func(ctx context.Context, sourceClient *etcdclient.EtcdClient) error) error {
 	backupFile = strings.TrimSuffix(backupFile, "/")
 	lastSlash := strings.LastIndex(backupFile, "/")
 	if lastSlash == -1 {
@@ -83,7 +369,7 @@ func runDump(backupFile string, out string) error {
 		}
 	}()
 
-	process, err := etcd.RunEtcdFromBackup(backupStore, backupName, tempDir)
+	process, err := etcd.RunEtcdFromBackup(backupStore, backupName, tempDir, nil)
 	if err != nil {
 		return err
 	}
@@ -96,19 +382,6 @@ func runDump(backupFile string, out string) error {
 		}
 	}()
 
-	var nodeSink etcdclient.NodeSink
-	if out == "" {
-		nodeSink, err = dump.NewStreamDumpSink(os.Stdout)
-		if err != nil {
-			return fmt.Errorf("unable to create stream: %v", err)
-		}
-	} else {
-		nodeSink, err = dump.NewTarDumpSink(out)
-		if err != nil {
-			return fmt.Errorf("unable to create file %q: %v", out, err)
-		}
-	}
-
 	sourceClient, err := process.NewClient()
 	if err != nil {
 		return fmt.Errorf("error building etcd client: %v", err)
@@ -131,16 +404,5 @@ func runDump(backupFile string, out string) error {
 		time.Sleep(time.Second)
 	}
 
-	ctx := context.TODO()
-	if n, err := sourceClient.CopyTo(ctx, nodeSink); err != nil {
-		return fmt.Errorf("error copying keys to sink: %v", err)
-	} else {
-		klog.Infof("read %d keys", n)
-	}
-
-	if err := nodeSink.Close(); err != nil {
-		return err
-	}
-
-	return nil
+	return fn(context.TODO(), sourceClient)
 }
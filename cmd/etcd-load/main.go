@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/etcd-manager/pkg/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/etcd/dump"
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+func main() {
+	klog.InitFlags(nil)
+
+	var o LoadOptions
+	o.ClientURL = "http://127.0.0.1:4001"
+	flag.StringVar(&o.Format, "format", o.Format, "format of the dump: tar or jsonl; defaults to tar for .tar.gz and .tgz files, and jsonl otherwise")
+	flag.StringVar(&o.ClientURL, "client-url", o.ClientURL, "URL on which to connect to the etcd cluster to load the keys into")
+	flag.StringVar(&o.ClientCAFile, "client-ca-file", o.ClientCAFile, "path to the ca certificate")
+	flag.StringVar(&o.ClientCertFile, "client-cert-file", o.ClientCertFile, "path to the client tls certificate")
+	flag.StringVar(&o.ClientKeyFile, "client-key-file", o.ClientKeyFile, "path to the client tls cert key")
+	flag.StringVar(&o.Prefix, "prefix", o.Prefix, "load only this key and the keys below it; all keys are loaded if not set")
+	flag.BoolVar(&o.DryRun, "dry-run", o.DryRun, "print the changes that would be made, without making them")
+	flag.BoolVar(&o.CreateOnly, "create-only", o.CreateOnly, "only load keys that do not exist in the cluster")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [<args>] <dump>\n", os.Args[0])
+		fmt.Print("\nLoads the keys in a dump written by etcd-dump -format=tar or -format=jsonl into an etcd cluster.\n")
+		fmt.Print("<dump> is a file, or - to read from stdin.\n")
+		fmt.Print("\n\nThese are the supported args:\n\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	fmt.Fprintf(os.Stderr, "etcd-load\n")
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if err := runLoad(context.Background(), flag.Arg(0), &o); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// LoadOptions controls how a dump is loaded
+type LoadOptions struct {
+	Format         string
+	ClientURL      string
+	ClientCAFile   string
+	ClientCertFile string
+	ClientKeyFile  string
+	Prefix         string
+	DryRun         bool
+	CreateOnly     bool
+}
+
+func runLoad(ctx context.Context, dumpFile string, o *LoadOptions) error {
+	format := o.Format
+	if format == "" {
+		format = "jsonl"
+		if strings.HasSuffix(dumpFile, ".tar.gz") || strings.HasSuffix(dumpFile, ".tgz") {
+			format = "tar"
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if dumpFile != "-" {
+		f, err := os.Open(dumpFile)
+		if err != nil {
+			return fmt.Errorf("unable to open dump %q: %v", dumpFile, err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var reader dump.DumpReader
+	switch format {
+	case "tar":
+		reader = dump.NewTarDumpReader(in)
+	case "jsonl":
+		reader = dump.NewJSONLinesReader(in)
+	default:
+		return fmt.Errorf("unknown format %q, expected tar or jsonl", format)
+	}
+
+	tlsConfig, err := etcdclient.LoadClientTLSConfig(o.ClientCAFile, o.ClientCertFile, o.ClientKeyFile)
+	if err != nil {
+		return err
+	}
+
+	destClient, err := etcdclient.NewClient([]string{o.ClientURL}, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("unable to reach etcd on %s: %v", o.ClientURL, err)
+	}
+	defer etcdclient.LoggedClose(destClient)
+
+	options := etcd.RestoreKeysOptions{
+		Prefix:     o.Prefix,
+		CreateOnly: o.CreateOnly,
+		DryRun:     o.DryRun,
+	}
+	changes, err := etcd.LoadKeys(ctx, reader, destClient, options)
+
+	counts := etcd.PrintKeyChanges(os.Stdout, changes, "loaded")
+	if err != nil {
+		return err
+	}
+
+	verb := "loaded"
+	if o.DryRun {
+		verb = "would load"
+	}
+	fmt.Printf("%s %d keys from %s into %s: %v\n", verb, counts.Written(), dumpFile, o.ClientURL, counts)
+	return nil
+}
@@ -17,16 +17,26 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
 	"k8s.io/klog/v2"
 	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
 	"sigs.k8s.io/etcd-manager/pkg/backup"
+	"sigs.k8s.io/etcd-manager/pkg/backupcontroller"
 	"sigs.k8s.io/etcd-manager/pkg/commands"
+	"sigs.k8s.io/etcd-manager/pkg/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+	"sigs.k8s.io/etcd-manager/pkg/pki"
 )
 
 const DefaultEtcdVersion = "3.2.24"
@@ -48,7 +58,7 @@ func main() {
 	o.SetDefaults()
 
 	flag.IntVar(&o.MemberCount, "member-count", o.MemberCount, "initial cluster size; cluster won't start until we have a quorum of this size")
-	flag.StringVar(&o.BackupStorePath, "backup-store", o.BackupStorePath, "backup store location")
+	flag.StringVar(&o.BackupStorePath, "backup-store", o.BackupStorePath, "backup store location; comma-separate several locations for a replicated store")
 	flag.StringVar(&o.EtcdVersion, "etcd-version", o.EtcdVersion, "etcd version")
 	flag.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [<args>] [<command>]\n", os.Args[0])
@@ -58,10 +68,33 @@ func main() {
 		fmt.Print(`get				Shows Cluster Spec
 configure-cluster		Sets cluster spec based on -member-count and -etcd-version args specified.
 list-backups			List backups available in the -backup-store
+pin-backup			Pins a backup so that retention cleanup never removes it.
+				eg. etcd-ctl -backup-store=s3://mybackupstore/ pin-backup 2019-05-07T18:28:01Z-000977 -reason="before upgrade"
+				Add -for=<duration> (eg 90d) or -until=<RFC3339 time> to let the pin expire.
+unpin-backup			Removes the pin from a backup.
+clear-anomaly			Clears the anomaly flag from backups, once a change in size or key count is known to be expected,
+				so that they are used as the baseline for later backups.
+				eg. etcd-ctl -backup-store=s3://mybackupstore/ clear-anomaly 2019-05-07T18:28:01Z-000977
+backup-now			Asks the leader to take a backup now (within about a minute).
+				eg. etcd-ctl -backup-store=s3://mybackupstore/ backup-now -label="before upgrade"
+				Add -wait=<duration> to wait for the backup, and print its name.
 list-commands			List commands in queue for cluster to execute.
 delete-command			Deletes a command from the clusters queue
 restore-backup			Restores the backup specified. Pass the backup timestamp shown by list-backup as parameter.
 				eg. etcd-ctl -backup-store=s3://mybackupstore/ restore-backup 2019-05-07T18:28:01Z-000977
+				To roll the backup forward using archived revisions, add -to-revision=<revision>,
+				-to-time=<RFC3339 time> or -to-latest after the backup name.
+restore-state			Re-seeds the CA keypairs in -pki-dir, and the cluster spec if none is set, from the cluster state
+				archived with a backup.  Requires ETCD_MANAGER_BACKUP_STATE_KEY_FILE.
+				eg. etcd-ctl -backup-store=s3://mybackupstore/ restore-state 2019-05-07T18:28:01Z-000977 -pki-dir=/etc/kubernetes/pki/etcd-manager
+				Add -overwrite to replace CA keypairs and a cluster spec that differ from the archived state.
+migrate-store			Copies every backup, the cluster spec and the cluster-creation marker to another backup store,
+				verifying each copy.  It can be re-run to resume, and refuses a destination holding another cluster.
+				eg. etcd-ctl migrate-store -from=gs://oldbackups/cluster -to=s3://newbackups/cluster
+restore-keys			Restores the keys under a prefix from a backup into the live cluster, leaving other keys alone.
+				eg. etcd-ctl -backup-store=s3://mybackupstore/ restore-keys -backup=2019-05-07T18:28:01Z-000977 -prefix=/registry/namespaces/foo -client-url=https://127.0.0.1:4001
+				Add -dry-run to print the changes without making them, and -create-only to leave keys that exist alone.
+				Pass -client-ca-file, -client-cert-file and -client-key-file to connect to etcd with TLS.
 `)
 	}
 	flag.Parse()
@@ -94,12 +127,26 @@ func run(ctx context.Context, o *Options, command string, args []string) error {
 		return runInitCluster(ctx, o)
 	case "list-backups":
 		return runListBackups(ctx, o)
+	case "pin-backup":
+		return runPinBackup(ctx, o, args)
+	case "unpin-backup":
+		return runUnpinBackup(ctx, o, args)
+	case "clear-anomaly":
+		return runClearAnomaly(ctx, o, args)
+	case "backup-now":
+		return runBackupNow(ctx, o, args)
 	case "list-commands":
 		return runListCommands(ctx, o)
 	case "delete-command":
 		return runDeleteCommand(ctx, o, args)
 	case "restore-backup":
 		return runRestoreBackup(ctx, o, args)
+	case "restore-state":
+		return runRestoreState(ctx, o, args)
+	case "migrate-store":
+		return runMigrateStore(ctx, o, args)
+	case "restore-keys":
+		return runRestoreKeys(ctx, o, args)
 	default:
 		return fmt.Errorf("unknown command %q", command)
 	}
@@ -135,15 +182,198 @@ func runListBackups(ctx context.Context, o *Options) error {
 		return err
 	}
 
-	backups, err := backupStore.ListBackups()
+	entries, err := backup.ListEntries(backupStore)
 	if err != nil {
 		return fmt.Errorf("error listing backups: %v", err)
 	}
 
-	for _, b := range backups {
-		fmt.Fprintf(os.Stdout, "%v\n", b)
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "NAME\tLABEL\tREVISION\tTERM\tDB SIZE\tKEYS\tSOURCE\tMEMBERS\tPINNED\tANOMALY\n")
+	for _, entry := range entries {
+		info := entry.Info
+		if info == nil {
+			info = &protoetcd.BackupInfo{}
+		}
+		source := info.SourceMemberName
+		if source == "" {
+			source = info.SourceMemberId
+		}
+		fmt.Fprintf(w, "%v\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Name,
+			valueOrDash(info.Label),
+			formatBackupValue(info.Revision),
+			formatBackupValue(int64(info.RaftTerm)),
+			formatBackupValue(info.DbSize),
+			formatBackupValue(info.KeyCount),
+			valueOrDash(source),
+			describeMembers(info.Members),
+			describePin(entry.Pin, now),
+			valueOrDash(entry.Anomaly.GetReason()))
+	}
+	return w.Flush()
+}
+
+// formatBackupValue formats a numeric field of BackupInfo, which older backups do not record
+func formatBackupValue(v int64) string {
+	if v == 0 {
+		return "-"
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// describeMembers returns the members column for list-backups
+func describeMembers(members []*protoetcd.BackupMember) string {
+	var names []string
+	for _, member := range members {
+		names = append(names, member.Name)
+	}
+	return valueOrDash(strings.Join(names, ","))
+}
+
+// describePin returns the pinned column for list-backups
+func describePin(pin *protoetcd.BackupPin, now time.Time) string {
+	if pin == nil {
+		return "-"
+	}
+
+	s := "pinned"
+	if pin.Expiry != 0 {
+		expiry := time.Unix(pin.Expiry, 0).UTC().Format(time.RFC3339)
+		if backup.IsPinned(pin, now) {
+			s += " until " + expiry
+		} else {
+			s = "pin expired " + expiry
+		}
+	}
+	if pin.Reason != "" {
+		s += fmt.Sprintf(" (%s)", pin.Reason)
+	}
+	return s
+}
+
+func runPinBackup(ctx context.Context, o *Options, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("syntax: pin-backup <backupname> [-reason=<reason>] [-for=<duration>|-until=<time>]")
+	}
+	backupName := args[0]
+
+	pin, err := parsePin(args[1:], time.Now())
+	if err != nil {
+		return err
+	}
+
+	backupStore, err := GetBackupStore(o)
+	if err != nil {
+		return err
+	}
+
+	pins, ok := backupStore.(backup.PinStore)
+	if !ok {
+		return fmt.Errorf("backup store %s does not support pinning backups", backupStore.Spec())
+	}
+	if err := pins.PinBackup(backupName, pin); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "pinned backup %s: %s\n", backupName, describePin(pin, time.Now()))
+	return nil
+}
+
+// parsePin parses the flags for pin-backup
+func parsePin(args []string, now time.Time) (*protoetcd.BackupPin, error) {
+	flags := flag.NewFlagSet("pin-backup", flag.ContinueOnError)
+	reason := flags.String("reason", "", "why the backup must be kept")
+	pinFor := flags.String("for", "", "keep the backup for this long (eg 72h, 90d, 1y)")
+	until := flags.String("until", "", "keep the backup until this time (RFC3339)")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != 0 {
+		return nil, fmt.Errorf("unexpected arguments to pin-backup: %v", flags.Args())
+	}
+
+	pin := &protoetcd.BackupPin{
+		Timestamp: now.Unix(),
+		Reason:    *reason,
+	}
+
+	if *pinFor != "" && *until != "" {
+		return nil, fmt.Errorf("only one of -for and -until may be specified")
+	}
+	if *pinFor != "" {
+		d, err := backupcontroller.ParseHumanDuration(*pinFor)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse -for %q: %v", *pinFor, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("-for must be positive")
+		}
+		pin.Expiry = now.Add(d).Unix()
+	}
+	if *until != "" {
+		t, err := time.Parse(time.RFC3339, *until)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse -until %q (expected RFC3339, e.g. 2019-05-07T18:28:01Z): %v", *until, err)
+		}
+		if !t.After(now) {
+			return nil, fmt.Errorf("-until must be in the future")
+		}
+		pin.Expiry = t.Unix()
+	}
+	return pin, nil
+}
+
+func runUnpinBackup(ctx context.Context, o *Options, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("syntax: unpin-backup <backupname>")
+	}
+	backupName := args[0]
+
+	backupStore, err := GetBackupStore(o)
+	if err != nil {
+		return err
+	}
+
+	pins, ok := backupStore.(backup.PinStore)
+	if !ok {
+		return fmt.Errorf("backup store %s does not support pinning backups", backupStore.Spec())
+	}
+	if err := pins.UnpinBackup(backupName); err != nil {
+		return err
 	}
 
+	fmt.Fprintf(os.Stdout, "unpinned backup %s\n", backupName)
+	return nil
+}
+
+func runClearAnomaly(ctx context.Context, o *Options, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("syntax: clear-anomaly <backupname>...")
+	}
+
+	backupStore, err := GetBackupStore(o)
+	if err != nil {
+		return err
+	}
+
+	anomalies, ok := backupStore.(backup.AnomalyStore)
+	if !ok {
+		return fmt.Errorf("backup store %s does not support flagging anomalies", backupStore.Spec())
+	}
+	for _, backupName := range args {
+		if err := anomalies.ClearAnomaly(backupName); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "cleared anomaly for backup %s\n", backupName)
+	}
 	return nil
 }
 
@@ -196,11 +426,16 @@ func runDeleteCommand(ctx context.Context, o *Options, args []string) error {
 }
 
 func runRestoreBackup(ctx context.Context, o *Options, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("syntax: restore-backup <backupname>")
+	if len(args) < 1 {
+		return fmt.Errorf("syntax: restore-backup <backupname> [-to-revision=<revision>|-to-time=<time>|-to-latest]")
 	}
 	backupName := args[0]
 
+	target, err := parseRecoveryTarget(args[1:])
+	if err != nil {
+		return err
+	}
+
 	commandStore, err := GetCommandStore(o)
 	if err != nil {
 		return err
@@ -220,6 +455,7 @@ func runRestoreBackup(ctx context.Context, o *Options, args []string) error {
 		RestoreBackup: &protoetcd.RestoreBackupCommand{
 			Backup:      backupName,
 			ClusterSpec: clusterSpec,
+			Target:      target,
 		},
 	}
 
@@ -231,6 +467,165 @@ func runRestoreBackup(ctx context.Context, o *Options, args []string) error {
 	return nil
 }
 
+func runBackupNow(ctx context.Context, o *Options, args []string) error {
+	flags := flag.NewFlagSet("backup-now", flag.ContinueOnError)
+	label := flags.String("label", "", "label to record with the backup")
+	wait := flags.Duration("wait", 0, "if set, wait up to this long for the backup to be taken")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments to backup-now: %v", flags.Args())
+	}
+
+	commandStore, err := GetCommandStore(o)
+	if err != nil {
+		return err
+	}
+
+	cmd := &protoetcd.Command{
+		BackupNow: &protoetcd.BackupNowCommand{
+			Label: *label,
+		},
+	}
+	if err := commandStore.AddCommand(cmd); err != nil {
+		return fmt.Errorf("error writing command to store: %v", err)
+	}
+	fmt.Fprintf(os.Stdout, "added backup-now command: %v\n", cmd)
+
+	if *wait == 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(*wait)
+	for {
+		result, err := commandStore.GetCommandResult(cmd)
+		if err != nil {
+			return err
+		}
+		if result != nil {
+			if result.Error != "" {
+				return fmt.Errorf("backup failed: %s", result.Error)
+			}
+			fmt.Fprintf(os.Stdout, "took backup %s\n", result.BackupName)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("backup was not taken within %v; the command is still queued", *wait)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func runRestoreState(ctx context.Context, o *Options, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("syntax: restore-state <backupname> -pki-dir=<dir> [-overwrite]")
+	}
+	backupName := args[0]
+
+	flags := flag.NewFlagSet("restore-state", flag.ContinueOnError)
+	pkiDir := flags.String("pki-dir", "", "directory to write the CA keypairs to (the etcd-manager -pki-dir)")
+	overwrite := flags.Bool("overwrite", false, "replace CA keypairs and the cluster spec if they differ from the archived state")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments to restore-state: %v", flags.Args())
+	}
+	if *pkiDir == "" {
+		return fmt.Errorf("-pki-dir is required")
+	}
+
+	key, err := backup.LoadStateKeyFromEnv()
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return fmt.Errorf("%s must be set to decrypt the cluster state", backup.StateKeyFileEnv)
+	}
+
+	backupStore, err := GetBackupStore(o)
+	if err != nil {
+		return err
+	}
+	states, ok := backupStore.(backup.StateStore)
+	if !ok {
+		return fmt.Errorf("backup store %s does not support archiving the cluster state", backupStore.Spec())
+	}
+	state, err := states.LoadState(backupName, key)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return fmt.Errorf("no cluster state was archived with backup %q", backupName)
+	}
+
+	restored, err := backupcontroller.RestoreState(state, pki.NewFSStore(*pkiDir), *overwrite)
+	for _, name := range restored {
+		fmt.Fprintf(os.Stdout, "restored CA keypair %s to %s\n", name, *pkiDir)
+	}
+	if err != nil {
+		return err
+	}
+
+	if state.ClusterSpec == nil {
+		return nil
+	}
+	commandStore, err := GetCommandStore(o)
+	if err != nil {
+		return err
+	}
+	clusterSpec, err := commandStore.GetExpectedClusterSpec()
+	if err != nil {
+		return fmt.Errorf("error reading etcd cluster spec: %v", err)
+	}
+	if clusterSpec != nil && !*overwrite {
+		if !proto.Equal(clusterSpec, state.ClusterSpec) {
+			fmt.Fprintf(os.Stdout, "leaving existing cluster spec %v (archived spec was %v)\n", clusterSpec, state.ClusterSpec)
+		}
+		return nil
+	}
+	if err := commandStore.SetExpectedClusterSpec(state.ClusterSpec); err != nil {
+		return fmt.Errorf("error setting expected cluster spec: %v", err)
+	}
+	fmt.Fprintf(os.Stdout, "set cluster spec to %v\n", state.ClusterSpec)
+	return nil
+}
+
+// parseRecoveryTarget parses the point-in-time recovery flags for restore-backup, returning nil if none are set
+func parseRecoveryTarget(args []string) (*protoetcd.RecoveryTarget, error) {
+	flags := flag.NewFlagSet("restore-backup", flag.ContinueOnError)
+	toRevision := flags.Int64("to-revision", 0, "replay archived revisions up to and including this etcd revision")
+	toTime := flags.String("to-time", "", "replay archived revisions up to this time (RFC3339)")
+	toLatest := flags.Bool("to-latest", false, "replay all archived revisions")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != 0 {
+		return nil, fmt.Errorf("unexpected arguments to restore-backup: %v", flags.Args())
+	}
+
+	if *toRevision == 0 && *toTime == "" && !*toLatest {
+		return nil, nil
+	}
+
+	target := &protoetcd.RecoveryTarget{
+		Revision: *toRevision,
+		Latest:   *toLatest,
+	}
+	if *toRevision < 0 {
+		return nil, fmt.Errorf("-to-revision must be positive")
+	}
+	if *toTime != "" {
+		t, err := time.Parse(time.RFC3339, *toTime)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse -to-time %q (expected RFC3339, e.g. 2019-05-07T18:28:01Z): %v", *toTime, err)
+		}
+		target.Timestamp = t.Unix()
+	}
+	return target, nil
+}
+
 func runInitCluster(ctx context.Context, o *Options) error {
 	commandStore, err := GetCommandStore(o)
 	if err != nil {
@@ -290,3 +685,194 @@ func runGet(ctx context.Context, o *Options) error {
 
 	return nil
 }
+
+func runMigrateStore(ctx context.Context, o *Options, args []string) error {
+	flags := flag.NewFlagSet("migrate-store", flag.ContinueOnError)
+	from := flags.String("from", "", "backup store to migrate from")
+	to := flags.String("to", "", "backup store to migrate to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments to migrate-store: %v", flags.Args())
+	}
+	if *from == "" || *to == "" {
+		return fmt.Errorf("syntax: migrate-store -from=<store> -to=<store>")
+	}
+	if *from == *to {
+		return fmt.Errorf("-from and -to must be different stores")
+	}
+
+	srcStore, err := backup.NewStore(*from)
+	if err != nil {
+		return fmt.Errorf("error initializing backup store %s: %v", *from, err)
+	}
+	destStore, err := backup.NewStore(*to)
+	if err != nil {
+		return fmt.Errorf("error initializing backup store %s: %v", *to, err)
+	}
+	srcControl, err := commands.NewStore(*from)
+	if err != nil {
+		return fmt.Errorf("error initializing commands store %s: %v", *from, err)
+	}
+	destControl, err := commands.NewStore(*to)
+	if err != nil {
+		return fmt.Errorf("error initializing commands store %s: %v", *to, err)
+	}
+
+	// Check that the destination is empty, a partial migration, or this cluster, before we write anything
+	srcMarker, err := commands.ReadClusterCreatedMarker(*from)
+	if err != nil {
+		return err
+	}
+	destMarker, err := commands.ReadClusterCreatedMarker(*to)
+	if err != nil {
+		return err
+	}
+	if destMarker != nil && !bytes.Equal(srcMarker, destMarker) {
+		return fmt.Errorf("%s holds a different cluster (its cluster-creation marker differs from %s)", *to, *from)
+	}
+	if destMarker == nil {
+		if err := backup.CheckSameCluster(srcStore, destStore); err != nil {
+			return fmt.Errorf("%s appears to hold a different cluster: %v", *to, err)
+		}
+	}
+
+	stateKey, err := backup.LoadStateKeyFromEnv()
+	if err != nil {
+		return err
+	}
+	if stateKey == nil {
+		klog.Warningf("%s is not set; archived cluster state will not be migrated", backup.StateKeyFileEnv)
+	}
+
+	names, err := srcStore.ListBackups()
+	if err != nil {
+		return fmt.Errorf("error listing backups in %s: %v", *from, err)
+	}
+	existing, err := destStore.ListBackups()
+	if err != nil {
+		return fmt.Errorf("error listing backups in %s: %v", *to, err)
+	}
+	present := make(map[string]bool)
+	for _, name := range existing {
+		present[name] = true
+	}
+
+	for _, name := range names {
+		if present[name] && backup.CopiedBackup(srcStore, destStore, name) {
+			// The data was copied by an earlier run; the pin and other extras may not have been
+			if err := backup.CopyBackupExtras(srcStore, destStore, name, stateKey); err != nil {
+				return err
+			}
+			fmt.Printf("already migrated %s\n", name)
+			continue
+		}
+
+		if err := backup.CopyBackup(srcStore, destStore, name, stateKey); err != nil {
+			return err
+		}
+		if err := destStore.VerifyBackup(name); err != nil {
+			return fmt.Errorf("copy of backup %q failed verification: %w", name, err)
+		}
+		fmt.Printf("migrated %s\n", name)
+	}
+
+	// The revision archive used for point-in-time restores is grouped by etcd cluster, so we copy the
+	// segments of every etcd cluster that has a backup
+	entries, err := backup.ListEntries(srcStore)
+	if err != nil {
+		return err
+	}
+	clusterIDs := make(map[string]bool)
+	for _, entry := range entries {
+		if clusterID := entry.Info.GetEtcdClusterId(); clusterID != "" && !clusterIDs[clusterID] {
+			clusterIDs[clusterID] = true
+			copied, err := backup.CopyRevisionSegments(srcStore, destStore, clusterID)
+			if err != nil {
+				return err
+			}
+			if copied != 0 {
+				fmt.Printf("migrated %d revision segments for etcd cluster %s\n", copied, clusterID)
+			}
+		}
+	}
+
+	spec, err := srcControl.GetExpectedClusterSpec()
+	if err != nil {
+		return err
+	}
+	if spec != nil {
+		if err := destControl.SetExpectedClusterSpec(spec); err != nil {
+			return err
+		}
+		fmt.Printf("migrated cluster spec: %v\n", spec)
+	}
+
+	// We write the marker last, so that a migration is only marked as holding the cluster once it is complete
+	if srcMarker != nil && destMarker == nil {
+		if err := commands.WriteClusterCreatedMarker(*to, srcMarker); err != nil {
+			return err
+		}
+		fmt.Printf("migrated cluster-creation marker\n")
+	}
+
+	fmt.Printf("migrated %d backups from %s to %s\n", len(names), *from, *to)
+	return nil
+}
+
+func runRestoreKeys(ctx context.Context, o *Options, args []string) error {
+	flags := flag.NewFlagSet("restore-keys", flag.ContinueOnError)
+	backupName := flags.String("backup", "", "backup to restore keys from")
+	prefix := flags.String("prefix", "", "restore this key and the keys below it (eg /registry/namespaces/foo, which does not include /registry/namespaces/foobar)")
+	clientURL := flags.String("client-url", "http://127.0.0.1:4001", "URL on which to connect to the live etcd cluster")
+	clientCAFile := flags.String("client-ca-file", "", "path to the ca certificate")
+	clientCertFile := flags.String("client-cert-file", "", "path to the client tls certificate")
+	clientKeyFile := flags.String("client-key-file", "", "path to the client tls cert key")
+	dryRun := flags.Bool("dry-run", false, "print the changes that would be made, without making them")
+	createOnly := flags.Bool("create-only", false, "only restore keys that do not exist in the live cluster")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments to restore-keys: %v", flags.Args())
+	}
+	if *backupName == "" || *prefix == "" {
+		return fmt.Errorf("syntax: restore-keys -backup=<backup> -prefix=<prefix> [-client-url=<url>] [-dry-run] [-create-only]")
+	}
+
+	tlsConfig, err := etcdclient.LoadClientTLSConfig(*clientCAFile, *clientCertFile, *clientKeyFile)
+	if err != nil {
+		return err
+	}
+
+	backupStore, err := GetBackupStore(o)
+	if err != nil {
+		return err
+	}
+
+	destClient, err := etcdclient.NewClient([]string{*clientURL}, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("unable to reach etcd on %s: %v", *clientURL, err)
+	}
+	defer etcdclient.LoggedClose(destClient)
+
+	options := etcd.RestoreKeysOptions{
+		Prefix:     *prefix,
+		CreateOnly: *createOnly,
+		DryRun:     *dryRun,
+	}
+	changes, err := etcd.RestoreKeys(ctx, backupStore, *backupName, destClient, options)
+
+	counts := etcd.PrintKeyChanges(os.Stdout, changes, "restored")
+	if err != nil {
+		return err
+	}
+
+	verb := "restored"
+	if *dryRun {
+		verb = "would restore"
+	}
+	fmt.Printf("%s %d keys under %q from %s: %v\n", verb, counts.Written(), *prefix, *backupName, counts)
+	return nil
+}
@@ -32,6 +32,7 @@ import (
 	"k8s.io/kops/util/pkg/vfs"
 	apis_etcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
 	"sigs.k8s.io/etcd-manager/pkg/backup"
+	"sigs.k8s.io/etcd-manager/pkg/backupcontroller"
 	"sigs.k8s.io/etcd-manager/pkg/commands"
 	"sigs.k8s.io/etcd-manager/pkg/controller"
 	"sigs.k8s.io/etcd-manager/pkg/etcd"
@@ -90,8 +91,12 @@ func main() {
 	flag.StringVar(&o.ClientUrls, "client-urls", o.ClientUrls, "client-urls to use for normal operation")
 	flag.StringVar(&o.QuarantineClientUrls, "quarantine-client-urls", o.QuarantineClientUrls, "client-urls to use when etcd should be quarantined e.g. when offline")
 	flag.StringVar(&o.ClusterName, "cluster-name", o.ClusterName, "name of cluster")
-	flag.StringVar(&o.BackupStorePath, "backup-store", o.BackupStorePath, "backup store location")
+	flag.StringVar(&o.BackupStorePath, "backup-store", o.BackupStorePath, "backup store location; comma-separate several locations to replicate backups")
 	flag.StringVar(&o.BackupInterval, "backup-interval", o.BackupInterval, "interval for periodic backups")
+	flag.StringVar(&o.BackupSchedule, "backup-schedule", o.BackupSchedule, "cron expression (in UTC) for periodic backups; replaces backup-interval if set")
+	flag.StringVar(&o.BackupBlackoutWindows, "backup-blackout-windows", o.BackupBlackoutWindows, "comma-separated daily windows (HH:MM-HH:MM, in UTC) during which scheduled backups are deferred")
+	flag.DurationVar(&o.BackupJitter, "backup-jitter", o.BackupJitter, "maximum delay added to each scheduled backup, to spread load")
+	flag.DurationVar(&o.ArchiveInterval, "archive-interval", o.ArchiveInterval, "if set, continuously archive etcd revisions for point-in-time recovery, writing them at this interval")
 	flag.StringVar(&o.DiscoveryPollInterval, "discovery-poll-interval", o.DiscoveryPollInterval, "interval for discovery poll")
 	flag.StringVar(&o.DataDir, "data-dir", o.DataDir, "directory for storing etcd data")
 	flag.StringVar(&o.StaticConfig, "static-config", o.StaticConfig, "options for static cluster config")
@@ -219,6 +224,15 @@ type EtcdManagerOptions struct {
 	BackupInterval        string
 	DiscoveryPollInterval string
 
+	// BackupSchedule is a cron expression for periodic backups, used instead of BackupInterval if set
+	BackupSchedule string
+	// BackupBlackoutWindows are daily windows during which scheduled backups are deferred
+	BackupBlackoutWindows string
+	// BackupJitter is the maximum delay added to each scheduled backup
+	BackupJitter time.Duration
+	// ArchiveInterval, if set, is the interval at which the revisions archived for point-in-time recovery are written
+	ArchiveInterval time.Duration
+
 	// StaticConfig can be provided to run with a static cluster configuration.
 	// Reconfiguration requires restarting etcd-manager externally.
 	StaticConfig string
@@ -311,6 +325,16 @@ func RunEtcdManager(o *EtcdManagerOptions) error {
 		return fmt.Errorf("invalid backup-interval duration %q", o.BackupInterval)
 	}
 
+	var backupSchedule *backupcontroller.BackupSchedule
+	if o.BackupSchedule != "" {
+		backupSchedule, err = backupcontroller.NewBackupSchedule(o.BackupSchedule, o.BackupBlackoutWindows, o.BackupJitter)
+		if err != nil {
+			return fmt.Errorf("invalid backup-schedule: %w", err)
+		}
+	} else if o.BackupBlackoutWindows != "" || o.BackupJitter != 0 {
+		return fmt.Errorf("backup-blackout-windows and backup-jitter require backup-schedule")
+	}
+
 	dnsProvider := &hosts.Provider{
 		Key: "etcd-manager[" + o.ClusterName + "]",
 	}
@@ -614,6 +638,23 @@ func RunEtcdManager(o *EtcdManagerOptions) error {
 	if err != nil {
 		return fmt.Errorf("error building etcd controller: %v", err)
 	}
+	c.BackupSchedule = backupSchedule
+
+	stateKey, err := backup.LoadStateKeyFromEnv()
+	if err != nil {
+		return err
+	}
+	if stateKey != nil {
+		if o.PKIDir == "" {
+			return fmt.Errorf("pki-dir is required to archive the cluster state (%s)", backup.StateKeyFileEnv)
+		}
+		c.StateArchiver = backupcontroller.NewStateArchiver(backupStore, o.PKIDir, stateKey)
+	}
+	if o.ArchiveInterval != 0 {
+		if err := c.StartRevisionArchiver(ctx, etcdNodeInfo.ClientUrls, o.ArchiveInterval); err != nil {
+			return fmt.Errorf("error building revision archiver: %v", err)
+		}
+	}
 	// Self is seeded into the peer set at construction (NewServer), so the controller finds itself on
 	// its first run; no need to wait for discovery.
 	go c.Run(ctx)
@@ -152,6 +152,8 @@ type Command struct {
 	// but either the administrator can set this in a DR scenario,
 	// or we set it ourselves immediately after having performed a quarantined backup
 	RestoreBackup *RestoreBackupCommand `protobuf:"bytes,10,opt,name=restore_backup,json=restoreBackup,proto3" json:"restore_backup,omitempty"`
+	// If backup now is set, this requests that the leader take a backup immediately
+	BackupNow     *BackupNowCommand `protobuf:"bytes,11,opt,name=backup_now,json=backupNow,proto3" json:"backup_now,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -200,18 +202,135 @@ func (x *Command) GetRestoreBackup() *RestoreBackupCommand {
 	return nil
 }
 
+func (x *Command) GetBackupNow() *BackupNowCommand {
+	if x != nil {
+		return x.BackupNow
+	}
+	return nil
+}
+
+type BackupNowCommand struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// label is recorded in the backup info, to identify the backup
+	Label         string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupNowCommand) Reset() {
+	*x = BackupNowCommand{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupNowCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupNowCommand) ProtoMessage() {}
+
+func (x *BackupNowCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupNowCommand.ProtoReflect.Descriptor instead.
+func (*BackupNowCommand) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BackupNowCommand) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+// CommandResult is written back to the control store when a command has been executed
+type CommandResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// backup_name is the name of the backup taken by a backup-now command
+	BackupName string `protobuf:"bytes,2,opt,name=backup_name,json=backupName,proto3" json:"backup_name,omitempty"`
+	// error is set if the command failed
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandResult) Reset() {
+	*x = CommandResult{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResult) ProtoMessage() {}
+
+func (x *CommandResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResult.ProtoReflect.Descriptor instead.
+func (*CommandResult) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CommandResult) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *CommandResult) GetBackupName() string {
+	if x != nil {
+		return x.BackupName
+	}
+	return ""
+}
+
+func (x *CommandResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type RestoreBackupCommand struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The new cluster spec we should restore into
-	ClusterSpec   *ClusterSpec `protobuf:"bytes,1,opt,name=cluster_spec,json=clusterSpec,proto3" json:"cluster_spec,omitempty"`
-	Backup        string       `protobuf:"bytes,3,opt,name=backup,proto3" json:"backup,omitempty"`
+	ClusterSpec *ClusterSpec `protobuf:"bytes,1,opt,name=cluster_spec,json=clusterSpec,proto3" json:"cluster_spec,omitempty"`
+	Backup      string       `protobuf:"bytes,3,opt,name=backup,proto3" json:"backup,omitempty"`
+	// If target is set, archived revisions are replayed on top of the backup, for point-in-time recovery
+	Target        *RecoveryTarget `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RestoreBackupCommand) Reset() {
 	*x = RestoreBackupCommand{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[2]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -223,7 +342,7 @@ func (x *RestoreBackupCommand) String() string {
 func (*RestoreBackupCommand) ProtoMessage() {}
 
 func (x *RestoreBackupCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[2]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -236,7 +355,7 @@ func (x *RestoreBackupCommand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RestoreBackupCommand.ProtoReflect.Descriptor instead.
 func (*RestoreBackupCommand) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{2}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *RestoreBackupCommand) GetClusterSpec() *ClusterSpec {
@@ -253,6 +372,78 @@ func (x *RestoreBackupCommand) GetBackup() string {
 	return ""
 }
 
+func (x *RestoreBackupCommand) GetTarget() *RecoveryTarget {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+// RecoveryTarget is the point to which archived revisions are replayed, on top of a restored backup.
+// Replay stops at whichever limit is reached first.
+type RecoveryTarget struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// revision is the last etcd revision to replay, or 0 for no revision limit
+	Revision int64 `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	// timestamp (unix seconds) stops replay at the last revision archived at or before this time, or 0 for no time limit
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// latest replays every archived revision; it must be set if neither revision nor timestamp is set
+	Latest        bool `protobuf:"varint,3,opt,name=latest,proto3" json:"latest,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecoveryTarget) Reset() {
+	*x = RecoveryTarget{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecoveryTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecoveryTarget) ProtoMessage() {}
+
+func (x *RecoveryTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecoveryTarget.ProtoReflect.Descriptor instead.
+func (*RecoveryTarget) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RecoveryTarget) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *RecoveryTarget) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *RecoveryTarget) GetLatest() bool {
+	if x != nil {
+		return x.Latest
+	}
+	return false
+}
+
 type CreateNewClusterCommand struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ClusterSpec   *ClusterSpec           `protobuf:"bytes,1,opt,name=cluster_spec,json=clusterSpec,proto3" json:"cluster_spec,omitempty"`
@@ -262,7 +453,7 @@ type CreateNewClusterCommand struct {
 
 func (x *CreateNewClusterCommand) Reset() {
 	*x = CreateNewClusterCommand{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[3]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -274,7 +465,7 @@ func (x *CreateNewClusterCommand) String() string {
 func (*CreateNewClusterCommand) ProtoMessage() {}
 
 func (x *CreateNewClusterCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[3]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -287,7 +478,7 @@ func (x *CreateNewClusterCommand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateNewClusterCommand.ProtoReflect.Descriptor instead.
 func (*CreateNewClusterCommand) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{3}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateNewClusterCommand) GetClusterSpec() *ClusterSpec {
@@ -305,7 +496,7 @@ type GetInfoRequest struct {
 
 func (x *GetInfoRequest) Reset() {
 	*x = GetInfoRequest{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[4]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -317,7 +508,7 @@ func (x *GetInfoRequest) String() string {
 func (*GetInfoRequest) ProtoMessage() {}
 
 func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[4]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -330,34 +521,937 @@ func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
 func (*GetInfoRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{4}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{7}
+}
+
+type GetInfoResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ClusterName       string                 `protobuf:"bytes,2,opt,name=cluster_name,json=clusterName,proto3" json:"cluster_name,omitempty"`
+	NodeConfiguration *EtcdNode              `protobuf:"bytes,5,opt,name=node_configuration,json=nodeConfiguration,proto3" json:"node_configuration,omitempty"`
+	EtcdState         *EtcdState             `protobuf:"bytes,6,opt,name=etcd_state,json=etcdState,proto3" json:"etcd_state,omitempty"`
+	DiskEmpty         bool                   `protobuf:"varint,7,opt,name=disk_empty,json=diskEmpty,proto3" json:"disk_empty,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetInfoResponse) Reset() {
+	*x = GetInfoResponse{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoResponse) ProtoMessage() {}
+
+func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetInfoResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetInfoResponse) GetClusterName() string {
+	if x != nil {
+		return x.ClusterName
+	}
+	return ""
+}
+
+func (x *GetInfoResponse) GetNodeConfiguration() *EtcdNode {
+	if x != nil {
+		return x.NodeConfiguration
+	}
+	return nil
+}
+
+func (x *GetInfoResponse) GetEtcdState() *EtcdState {
+	if x != nil {
+		return x.EtcdState
+	}
+	return nil
+}
+
+func (x *GetInfoResponse) GetDiskEmpty() bool {
+	if x != nil {
+		return x.DiskEmpty
+	}
+	return false
+}
+
+type UpdateEndpointsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Contains information about the current nodes
+	// It can be used for nodes to update /etc/hosts
+	MemberMap     *MemberMap `protobuf:"bytes,1,opt,name=member_map,json=memberMap,proto3" json:"member_map,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEndpointsRequest) Reset() {
+	*x = UpdateEndpointsRequest{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEndpointsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEndpointsRequest) ProtoMessage() {}
+
+func (x *UpdateEndpointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEndpointsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEndpointsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateEndpointsRequest) GetMemberMap() *MemberMap {
+	if x != nil {
+		return x.MemberMap
+	}
+	return nil
+}
+
+type MemberMap struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*MemberMapInfo       `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MemberMap) Reset() {
+	*x = MemberMap{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemberMap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemberMap) ProtoMessage() {}
+
+func (x *MemberMap) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemberMap.ProtoReflect.Descriptor instead.
+func (*MemberMap) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MemberMap) GetMembers() []*MemberMapInfo {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type MemberMapInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Dns           string                 `protobuf:"bytes,2,opt,name=dns,proto3" json:"dns,omitempty"`
+	Addresses     []string               `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MemberMapInfo) Reset() {
+	*x = MemberMapInfo{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemberMapInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemberMapInfo) ProtoMessage() {}
+
+func (x *MemberMapInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemberMapInfo.ProtoReflect.Descriptor instead.
+func (*MemberMapInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MemberMapInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MemberMapInfo) GetDns() string {
+	if x != nil {
+		return x.Dns
+	}
+	return ""
+}
+
+func (x *MemberMapInfo) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type UpdateEndpointsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateEndpointsResponse) Reset() {
+	*x = UpdateEndpointsResponse{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEndpointsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEndpointsResponse) ProtoMessage() {}
+
+func (x *UpdateEndpointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEndpointsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateEndpointsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{12}
+}
+
+type BackupInfo struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	EtcdVersion string                 `protobuf:"bytes,1,opt,name=etcd_version,json=etcdVersion,proto3" json:"etcd_version,omitempty"`
+	Timestamp   int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ClusterSpec *ClusterSpec           `protobuf:"bytes,3,opt,name=cluster_spec,json=clusterSpec,proto3" json:"cluster_spec,omitempty"`
+	// data_sha256 is the hex-encoded SHA-256 of the backup data file, as stored in the backup store
+	DataSha256 string `protobuf:"bytes,4,opt,name=data_sha256,json=dataSha256,proto3" json:"data_sha256,omitempty"`
+	// data_size is the size in bytes of the backup data file, as stored in the backup store
+	DataSize int64 `protobuf:"varint,5,opt,name=data_size,json=dataSize,proto3" json:"data_size,omitempty"`
+	// compression is the codec used to compress the backup data; empty means gzip
+	Compression string `protobuf:"bytes,6,opt,name=compression,proto3" json:"compression,omitempty"`
+	// etcd_cluster_id is the (hex) ID of the etcd cluster that was backed up.
+	// Revisions are only meaningful within a cluster, so archived revisions are stored per cluster.
+	EtcdClusterId string `protobuf:"bytes,7,opt,name=etcd_cluster_id,json=etcdClusterId,proto3" json:"etcd_cluster_id,omitempty"`
+	// revision is the etcd revision of the source member when the backup started; the snapshot holds at least this revision
+	Revision int64 `protobuf:"varint,8,opt,name=revision,proto3" json:"revision,omitempty"`
+	// raft_term is the raft term of the source member when the backup started
+	RaftTerm uint64 `protobuf:"varint,9,opt,name=raft_term,json=raftTerm,proto3" json:"raft_term,omitempty"`
+	// db_size is the size in bytes of the etcd database of the source member, before compression
+	DbSize int64 `protobuf:"varint,10,opt,name=db_size,json=dbSize,proto3" json:"db_size,omitempty"`
+	// key_count is the number of keys in etcd when the backup started
+	KeyCount int64 `protobuf:"varint,11,opt,name=key_count,json=keyCount,proto3" json:"key_count,omitempty"`
+	// source_member_id is the (decimal) ID of the etcd member that the snapshot was taken from
+	SourceMemberId string `protobuf:"bytes,12,opt,name=source_member_id,json=sourceMemberId,proto3" json:"source_member_id,omitempty"`
+	// source_member_name is the name of the etcd member that the snapshot was taken from
+	SourceMemberName string `protobuf:"bytes,13,opt,name=source_member_name,json=sourceMemberName,proto3" json:"source_member_name,omitempty"`
+	// members is the etcd cluster membership when the backup was taken
+	Members []*BackupMember `protobuf:"bytes,14,rep,name=members,proto3" json:"members,omitempty"`
+	// label identifies a backup that was requested explicitly, for example with backup-now
+	Label         string `protobuf:"bytes,15,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupInfo) Reset() {
+	*x = BackupInfo{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupInfo) ProtoMessage() {}
+
+func (x *BackupInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupInfo.ProtoReflect.Descriptor instead.
+func (*BackupInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BackupInfo) GetEtcdVersion() string {
+	if x != nil {
+		return x.EtcdVersion
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetClusterSpec() *ClusterSpec {
+	if x != nil {
+		return x.ClusterSpec
+	}
+	return nil
+}
+
+func (x *BackupInfo) GetDataSha256() string {
+	if x != nil {
+		return x.DataSha256
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetDataSize() int64 {
+	if x != nil {
+		return x.DataSize
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetEtcdClusterId() string {
+	if x != nil {
+		return x.EtcdClusterId
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetRaftTerm() uint64 {
+	if x != nil {
+		return x.RaftTerm
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetDbSize() int64 {
+	if x != nil {
+		return x.DbSize
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetKeyCount() int64 {
+	if x != nil {
+		return x.KeyCount
+	}
+	return 0
+}
+
+func (x *BackupInfo) GetSourceMemberId() string {
+	if x != nil {
+		return x.SourceMemberId
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetSourceMemberName() string {
+	if x != nil {
+		return x.SourceMemberName
+	}
+	return ""
+}
+
+func (x *BackupInfo) GetMembers() []*BackupMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *BackupInfo) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+// BackupMember is a member of the etcd cluster at the time of a backup
+type BackupMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	PeerUrls      []string               `protobuf:"bytes,3,rep,name=peer_urls,json=peerUrls,proto3" json:"peer_urls,omitempty"`
+	ClientUrls    []string               `protobuf:"bytes,4,rep,name=client_urls,json=clientUrls,proto3" json:"client_urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupMember) Reset() {
+	*x = BackupMember{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupMember) ProtoMessage() {}
+
+func (x *BackupMember) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupMember.ProtoReflect.Descriptor instead.
+func (*BackupMember) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BackupMember) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BackupMember) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BackupMember) GetPeerUrls() []string {
+	if x != nil {
+		return x.PeerUrls
+	}
+	return nil
+}
+
+func (x *BackupMember) GetClientUrls() []string {
+	if x != nil {
+		return x.ClientUrls
+	}
+	return nil
+}
+
+// BackupState is the cluster state archived (encrypted) alongside a backup, so that a cluster can be rebuilt after total loss
+type BackupState struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// cluster_spec is the expected cluster spec from the control store
+	ClusterSpec *ClusterSpec `protobuf:"bytes,2,opt,name=cluster_spec,json=clusterSpec,proto3" json:"cluster_spec,omitempty"`
+	// keypairs holds the CA keypairs from the pki directory
+	Keypairs      []*BackupStateKeypair `protobuf:"bytes,3,rep,name=keypairs,proto3" json:"keypairs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupState) Reset() {
+	*x = BackupState{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupState) ProtoMessage() {}
+
+func (x *BackupState) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupState.ProtoReflect.Descriptor instead.
+func (*BackupState) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BackupState) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BackupState) GetClusterSpec() *ClusterSpec {
+	if x != nil {
+		return x.ClusterSpec
+	}
+	return nil
+}
+
+func (x *BackupState) GetKeypairs() []*BackupStateKeypair {
+	if x != nil {
+		return x.Keypairs
+	}
+	return nil
+}
+
+// BackupStateKeypair is a keypair in the pki directory, as stored on disk
+type BackupStateKeypair struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// certificates is the PEM encoded certificate bundle
+	Certificates []byte `protobuf:"bytes,2,opt,name=certificates,proto3" json:"certificates,omitempty"`
+	// private_key is the PEM encoded private key
+	PrivateKey    []byte `protobuf:"bytes,3,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupStateKeypair) Reset() {
+	*x = BackupStateKeypair{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupStateKeypair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupStateKeypair) ProtoMessage() {}
+
+func (x *BackupStateKeypair) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupStateKeypair.ProtoReflect.Descriptor instead.
+func (*BackupStateKeypair) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BackupStateKeypair) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BackupStateKeypair) GetCertificates() []byte {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+func (x *BackupStateKeypair) GetPrivateKey() []byte {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return nil
+}
+
+// BackupCatalog is an index of the backups in a store, so that they can be listed without walking the whole store
+type BackupCatalog struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// rebuilt_timestamp is when the catalog was last rebuilt from a full listing of the store
+	RebuiltTimestamp int64                 `protobuf:"varint,1,opt,name=rebuilt_timestamp,json=rebuiltTimestamp,proto3" json:"rebuilt_timestamp,omitempty"`
+	Backups          []*BackupCatalogEntry `protobuf:"bytes,2,rep,name=backups,proto3" json:"backups,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BackupCatalog) Reset() {
+	*x = BackupCatalog{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupCatalog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupCatalog) ProtoMessage() {}
+
+func (x *BackupCatalog) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupCatalog.ProtoReflect.Descriptor instead.
+func (*BackupCatalog) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BackupCatalog) GetRebuiltTimestamp() int64 {
+	if x != nil {
+		return x.RebuiltTimestamp
+	}
+	return 0
+}
+
+func (x *BackupCatalog) GetBackups() []*BackupCatalogEntry {
+	if x != nil {
+		return x.Backups
+	}
+	return nil
+}
+
+// BackupCatalogEntry is the catalog entry for a single backup
+type BackupCatalogEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// info is a copy of the backup info saved alongside the backup
+	Info *BackupInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	// pin is a copy of the pin saved alongside the backup, if it is pinned
+	Pin *BackupPin `protobuf:"bytes,3,opt,name=pin,proto3" json:"pin,omitempty"`
+	// anomaly is a copy of the anomaly saved alongside the backup, if it was flagged
+	Anomaly       *BackupAnomaly `protobuf:"bytes,4,opt,name=anomaly,proto3" json:"anomaly,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupCatalogEntry) Reset() {
+	*x = BackupCatalogEntry{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupCatalogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupCatalogEntry) ProtoMessage() {}
+
+func (x *BackupCatalogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupCatalogEntry.ProtoReflect.Descriptor instead.
+func (*BackupCatalogEntry) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BackupCatalogEntry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BackupCatalogEntry) GetInfo() *BackupInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *BackupCatalogEntry) GetPin() *BackupPin {
+	if x != nil {
+		return x.Pin
+	}
+	return nil
+}
+
+func (x *BackupCatalogEntry) GetAnomaly() *BackupAnomaly {
+	if x != nil {
+		return x.Anomaly
+	}
+	return nil
+}
+
+// BackupPin marks a backup that retention cleanup must not remove
+type BackupPin struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// timestamp is when the backup was pinned
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// reason records why the backup must be kept
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// expiry is when the pin lapses, after which retention applies to the backup again; zero means never
+	Expiry        int64 `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackupPin) Reset() {
+	*x = BackupPin{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupPin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupPin) ProtoMessage() {}
+
+func (x *BackupPin) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupPin.ProtoReflect.Descriptor instead.
+func (*BackupPin) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BackupPin) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BackupPin) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BackupPin) GetExpiry() int64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
+// BackupAnomaly flags a backup whose size or key count differs sharply from the preceding backups
+type BackupAnomaly struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// timestamp is when the backup was flagged
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// reason describes how the backup differs from the baseline
+	Reason           string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	DataSize         int64  `protobuf:"varint,3,opt,name=data_size,json=dataSize,proto3" json:"data_size,omitempty"`
+	BaselineDataSize int64  `protobuf:"varint,4,opt,name=baseline_data_size,json=baselineDataSize,proto3" json:"baseline_data_size,omitempty"`
+	KeyCount         int64  `protobuf:"varint,5,opt,name=key_count,json=keyCount,proto3" json:"key_count,omitempty"`
+	BaselineKeyCount int64  `protobuf:"varint,6,opt,name=baseline_key_count,json=baselineKeyCount,proto3" json:"baseline_key_count,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BackupAnomaly) Reset() {
+	*x = BackupAnomaly{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackupAnomaly) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackupAnomaly) ProtoMessage() {}
+
+func (x *BackupAnomaly) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackupAnomaly.ProtoReflect.Descriptor instead.
+func (*BackupAnomaly) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *BackupAnomaly) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BackupAnomaly) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BackupAnomaly) GetDataSize() int64 {
+	if x != nil {
+		return x.DataSize
+	}
+	return 0
+}
+
+func (x *BackupAnomaly) GetBaselineDataSize() int64 {
+	if x != nil {
+		return x.BaselineDataSize
+	}
+	return 0
+}
+
+func (x *BackupAnomaly) GetKeyCount() int64 {
+	if x != nil {
+		return x.KeyCount
+	}
+	return 0
+}
+
+func (x *BackupAnomaly) GetBaselineKeyCount() int64 {
+	if x != nil {
+		return x.BaselineKeyCount
+	}
+	return 0
 }
 
-type GetInfoResponse struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	ClusterName       string                 `protobuf:"bytes,2,opt,name=cluster_name,json=clusterName,proto3" json:"cluster_name,omitempty"`
-	NodeConfiguration *EtcdNode              `protobuf:"bytes,5,opt,name=node_configuration,json=nodeConfiguration,proto3" json:"node_configuration,omitempty"`
-	EtcdState         *EtcdState             `protobuf:"bytes,6,opt,name=etcd_state,json=etcdState,proto3" json:"etcd_state,omitempty"`
-	DiskEmpty         bool                   `protobuf:"varint,7,opt,name=disk_empty,json=diskEmpty,proto3" json:"disk_empty,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+// BackupVerification records the result of a restore drill, which restores a backup into a sandbox etcd to check it
+type BackupVerification struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// timestamp is when the drill finished
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Success   bool  `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	// error describes why the drill failed
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// revision is the etcd revision of the restored backup
+	Revision int64 `protobuf:"varint,4,opt,name=revision,proto3" json:"revision,omitempty"`
+	// key_count is the number of keys in the restored backup
+	KeyCount int64 `protobuf:"varint,5,opt,name=key_count,json=keyCount,proto3" json:"key_count,omitempty"`
+	// hash is the etcd KV hash of the restored backup at revision
+	Hash          uint32 `protobuf:"varint,6,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetInfoResponse) Reset() {
-	*x = GetInfoResponse{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[5]
+func (x *BackupVerification) Reset() {
+	*x = BackupVerification{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetInfoResponse) String() string {
+func (x *BackupVerification) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInfoResponse) ProtoMessage() {}
+func (*BackupVerification) ProtoMessage() {}
 
-func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[5]
+func (x *BackupVerification) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -368,63 +1462,90 @@ func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
-func (*GetInfoResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use BackupVerification.ProtoReflect.Descriptor instead.
+func (*BackupVerification) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *GetInfoResponse) GetClusterName() string {
+func (x *BackupVerification) GetTimestamp() int64 {
 	if x != nil {
-		return x.ClusterName
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BackupVerification) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *BackupVerification) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-func (x *GetInfoResponse) GetNodeConfiguration() *EtcdNode {
+func (x *BackupVerification) GetRevision() int64 {
 	if x != nil {
-		return x.NodeConfiguration
+		return x.Revision
 	}
-	return nil
+	return 0
 }
 
-func (x *GetInfoResponse) GetEtcdState() *EtcdState {
+func (x *BackupVerification) GetKeyCount() int64 {
 	if x != nil {
-		return x.EtcdState
+		return x.KeyCount
 	}
-	return nil
+	return 0
 }
 
-func (x *GetInfoResponse) GetDiskEmpty() bool {
+func (x *BackupVerification) GetHash() uint32 {
 	if x != nil {
-		return x.DiskEmpty
+		return x.Hash
 	}
-	return false
+	return 0
 }
 
-type UpdateEndpointsRequest struct {
+// RevisionSegmentInfo is stored alongside a segment of archived etcd revisions
+type RevisionSegmentInfo struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Contains information about the current nodes
-	// It can be used for nodes to update /etc/hosts
-	MemberMap     *MemberMap `protobuf:"bytes,1,opt,name=member_map,json=memberMap,proto3" json:"member_map,omitempty"`
+	// start_revision is the first revision covered by the segment; every revision from here to end_revision is included
+	StartRevision int64 `protobuf:"varint,1,opt,name=start_revision,json=startRevision,proto3" json:"start_revision,omitempty"`
+	// end_revision is the last revision covered by the segment
+	EndRevision int64 `protobuf:"varint,2,opt,name=end_revision,json=endRevision,proto3" json:"end_revision,omitempty"`
+	// start_timestamp and end_timestamp (unix seconds) are when the archiver received the first and last revisions from etcd
+	StartTimestamp int64 `protobuf:"varint,3,opt,name=start_timestamp,json=startTimestamp,proto3" json:"start_timestamp,omitempty"`
+	EndTimestamp   int64 `protobuf:"varint,4,opt,name=end_timestamp,json=endTimestamp,proto3" json:"end_timestamp,omitempty"`
+	// compression is the codec used to compress the segment data; empty means gzip
+	Compression string `protobuf:"bytes,5,opt,name=compression,proto3" json:"compression,omitempty"`
+	// data_sha256 is the hex-encoded SHA-256 of the segment data, as stored in the backup store
+	DataSha256 string `protobuf:"bytes,6,opt,name=data_sha256,json=dataSha256,proto3" json:"data_sha256,omitempty"`
+	// data_size is the size in bytes of the segment data, as stored in the backup store
+	DataSize int64 `protobuf:"varint,7,opt,name=data_size,json=dataSize,proto3" json:"data_size,omitempty"`
+	// etcd_cluster_id is the (hex) ID of the etcd cluster the revisions were archived from
+	EtcdClusterId string `protobuf:"bytes,8,opt,name=etcd_cluster_id,json=etcdClusterId,proto3" json:"etcd_cluster_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateEndpointsRequest) Reset() {
-	*x = UpdateEndpointsRequest{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[6]
+func (x *RevisionSegmentInfo) Reset() {
+	*x = RevisionSegmentInfo{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateEndpointsRequest) String() string {
+func (x *RevisionSegmentInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateEndpointsRequest) ProtoMessage() {}
+func (*RevisionSegmentInfo) ProtoMessage() {}
 
-func (x *UpdateEndpointsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[6]
+func (x *RevisionSegmentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,86 +1556,93 @@ func (x *UpdateEndpointsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateEndpointsRequest.ProtoReflect.Descriptor instead.
-func (*UpdateEndpointsRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RevisionSegmentInfo.ProtoReflect.Descriptor instead.
+func (*RevisionSegmentInfo) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *UpdateEndpointsRequest) GetMemberMap() *MemberMap {
+func (x *RevisionSegmentInfo) GetStartRevision() int64 {
 	if x != nil {
-		return x.MemberMap
+		return x.StartRevision
 	}
-	return nil
+	return 0
 }
 
-type MemberMap struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Members       []*MemberMapInfo       `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *RevisionSegmentInfo) GetEndRevision() int64 {
+	if x != nil {
+		return x.EndRevision
+	}
+	return 0
 }
 
-func (x *MemberMap) Reset() {
-	*x = MemberMap{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[7]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *RevisionSegmentInfo) GetStartTimestamp() int64 {
+	if x != nil {
+		return x.StartTimestamp
+	}
+	return 0
 }
 
-func (x *MemberMap) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *RevisionSegmentInfo) GetEndTimestamp() int64 {
+	if x != nil {
+		return x.EndTimestamp
+	}
+	return 0
 }
 
-func (*MemberMap) ProtoMessage() {}
+func (x *RevisionSegmentInfo) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
 
-func (x *MemberMap) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[7]
+func (x *RevisionSegmentInfo) GetDataSha256() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.DataSha256
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use MemberMap.ProtoReflect.Descriptor instead.
-func (*MemberMap) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{7}
+func (x *RevisionSegmentInfo) GetDataSize() int64 {
+	if x != nil {
+		return x.DataSize
+	}
+	return 0
 }
 
-func (x *MemberMap) GetMembers() []*MemberMapInfo {
+func (x *RevisionSegmentInfo) GetEtcdClusterId() string {
 	if x != nil {
-		return x.Members
+		return x.EtcdClusterId
 	}
-	return nil
+	return ""
 }
 
-type MemberMapInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Dns           string                 `protobuf:"bytes,2,opt,name=dns,proto3" json:"dns,omitempty"`
-	Addresses     []string               `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+// ArchivedRevision holds the changes made to etcd in a single revision
+type ArchivedRevision struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Revision int64                  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	// timestamp (unix seconds) is when the archiver received the revision from etcd, as etcd does not record when it was written
+	Timestamp     int64            `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Events        []*ArchivedEvent `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MemberMapInfo) Reset() {
-	*x = MemberMapInfo{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[8]
+func (x *ArchivedRevision) Reset() {
+	*x = ArchivedRevision{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MemberMapInfo) String() string {
+func (x *ArchivedRevision) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MemberMapInfo) ProtoMessage() {}
+func (*ArchivedRevision) ProtoMessage() {}
 
-func (x *MemberMapInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[8]
+func (x *ArchivedRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -525,53 +1653,61 @@ func (x *MemberMapInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MemberMapInfo.ProtoReflect.Descriptor instead.
-func (*MemberMapInfo) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ArchivedRevision.ProtoReflect.Descriptor instead.
+func (*ArchivedRevision) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *MemberMapInfo) GetName() string {
+func (x *ArchivedRevision) GetRevision() int64 {
 	if x != nil {
-		return x.Name
+		return x.Revision
 	}
-	return ""
+	return 0
 }
 
-func (x *MemberMapInfo) GetDns() string {
+func (x *ArchivedRevision) GetTimestamp() int64 {
 	if x != nil {
-		return x.Dns
+		return x.Timestamp
 	}
-	return ""
+	return 0
 }
 
-func (x *MemberMapInfo) GetAddresses() []string {
+func (x *ArchivedRevision) GetEvents() []*ArchivedEvent {
 	if x != nil {
-		return x.Addresses
+		return x.Events
 	}
 	return nil
 }
 
-type UpdateEndpointsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type ArchivedEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// delete is true if the key was deleted, rather than put
+	Delete bool `protobuf:"varint,3,opt,name=delete,proto3" json:"delete,omitempty"`
+	// lease is the ID of the lease the key was attached to, or 0 if it had none
+	Lease int64 `protobuf:"varint,4,opt,name=lease,proto3" json:"lease,omitempty"`
+	// lease_ttl is the TTL (in seconds) the lease was granted with, or 0 if the lease had expired before it was archived
+	LeaseTtl      int64 `protobuf:"varint,5,opt,name=lease_ttl,json=leaseTtl,proto3" json:"lease_ttl,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateEndpointsResponse) Reset() {
-	*x = UpdateEndpointsResponse{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[9]
+func (x *ArchivedEvent) Reset() {
+	*x = ArchivedEvent{}
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateEndpointsResponse) String() string {
+func (x *ArchivedEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateEndpointsResponse) ProtoMessage() {}
+func (*ArchivedEvent) ProtoMessage() {}
 
-func (x *UpdateEndpointsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[9]
+func (x *ArchivedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -582,69 +1718,44 @@ func (x *UpdateEndpointsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateEndpointsResponse.ProtoReflect.Descriptor instead.
-func (*UpdateEndpointsResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{9}
-}
-
-type BackupInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	EtcdVersion   string                 `protobuf:"bytes,1,opt,name=etcd_version,json=etcdVersion,proto3" json:"etcd_version,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	ClusterSpec   *ClusterSpec           `protobuf:"bytes,3,opt,name=cluster_spec,json=clusterSpec,proto3" json:"cluster_spec,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *BackupInfo) Reset() {
-	*x = BackupInfo{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[10]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *BackupInfo) String() string {
-	return protoimpl.X.MessageStringOf(x)
+// Deprecated: Use ArchivedEvent.ProtoReflect.Descriptor instead.
+func (*ArchivedEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{24}
 }
 
-func (*BackupInfo) ProtoMessage() {}
-
-func (x *BackupInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[10]
+func (x *ArchivedEvent) GetKey() []byte {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Key
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use BackupInfo.ProtoReflect.Descriptor instead.
-func (*BackupInfo) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{10}
+func (x *ArchivedEvent) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
 }
 
-func (x *BackupInfo) GetEtcdVersion() string {
+func (x *ArchivedEvent) GetDelete() bool {
 	if x != nil {
-		return x.EtcdVersion
+		return x.Delete
 	}
-	return ""
+	return false
 }
 
-func (x *BackupInfo) GetTimestamp() int64 {
+func (x *ArchivedEvent) GetLease() int64 {
 	if x != nil {
-		return x.Timestamp
+		return x.Lease
 	}
 	return 0
 }
 
-func (x *BackupInfo) GetClusterSpec() *ClusterSpec {
+func (x *ArchivedEvent) GetLeaseTtl() int64 {
 	if x != nil {
-		return x.ClusterSpec
+		return x.LeaseTtl
 	}
-	return nil
+	return 0
 }
 
 type CommonRequestHeader struct {
@@ -657,7 +1768,7 @@ type CommonRequestHeader struct {
 
 func (x *CommonRequestHeader) Reset() {
 	*x = CommonRequestHeader{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[11]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -669,7 +1780,7 @@ func (x *CommonRequestHeader) String() string {
 func (*CommonRequestHeader) ProtoMessage() {}
 
 func (x *CommonRequestHeader) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[11]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -682,7 +1793,7 @@ func (x *CommonRequestHeader) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommonRequestHeader.ProtoReflect.Descriptor instead.
 func (*CommonRequestHeader) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{11}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *CommonRequestHeader) GetLeadershipToken() string {
@@ -712,7 +1823,7 @@ type DoBackupRequest struct {
 
 func (x *DoBackupRequest) Reset() {
 	*x = DoBackupRequest{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[12]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -724,7 +1835,7 @@ func (x *DoBackupRequest) String() string {
 func (*DoBackupRequest) ProtoMessage() {}
 
 func (x *DoBackupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[12]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -737,7 +1848,7 @@ func (x *DoBackupRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoBackupRequest.ProtoReflect.Descriptor instead.
 func (*DoBackupRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{12}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *DoBackupRequest) GetHeader() *CommonRequestHeader {
@@ -777,7 +1888,7 @@ type DoBackupResponse struct {
 
 func (x *DoBackupResponse) Reset() {
 	*x = DoBackupResponse{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[13]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -789,7 +1900,7 @@ func (x *DoBackupResponse) String() string {
 func (*DoBackupResponse) ProtoMessage() {}
 
 func (x *DoBackupResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[13]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -802,7 +1913,7 @@ func (x *DoBackupResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoBackupResponse.ProtoReflect.Descriptor instead.
 func (*DoBackupResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{13}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *DoBackupResponse) GetName() string {
@@ -813,17 +1924,19 @@ func (x *DoBackupResponse) GetName() string {
 }
 
 type DoRestoreRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Header        *CommonRequestHeader   `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	Storage       string                 `protobuf:"bytes,2,opt,name=storage,proto3" json:"storage,omitempty"`
-	BackupName    string                 `protobuf:"bytes,4,opt,name=backup_name,json=backupName,proto3" json:"backup_name,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Header     *CommonRequestHeader   `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Storage    string                 `protobuf:"bytes,2,opt,name=storage,proto3" json:"storage,omitempty"`
+	BackupName string                 `protobuf:"bytes,4,opt,name=backup_name,json=backupName,proto3" json:"backup_name,omitempty"`
+	// If target is set, archived revisions are replayed on top of the backup, for point-in-time recovery
+	Target        *RecoveryTarget `protobuf:"bytes,5,opt,name=target,proto3" json:"target,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DoRestoreRequest) Reset() {
 	*x = DoRestoreRequest{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[14]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -835,7 +1948,7 @@ func (x *DoRestoreRequest) String() string {
 func (*DoRestoreRequest) ProtoMessage() {}
 
 func (x *DoRestoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[14]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -848,7 +1961,7 @@ func (x *DoRestoreRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoRestoreRequest.ProtoReflect.Descriptor instead.
 func (*DoRestoreRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{14}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *DoRestoreRequest) GetHeader() *CommonRequestHeader {
@@ -872,6 +1985,13 @@ func (x *DoRestoreRequest) GetBackupName() string {
 	return ""
 }
 
+func (x *DoRestoreRequest) GetTarget() *RecoveryTarget {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
 type DoRestoreResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -880,7 +2000,7 @@ type DoRestoreResponse struct {
 
 func (x *DoRestoreResponse) Reset() {
 	*x = DoRestoreResponse{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[15]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -892,7 +2012,7 @@ func (x *DoRestoreResponse) String() string {
 func (*DoRestoreResponse) ProtoMessage() {}
 
 func (x *DoRestoreResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[15]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -905,7 +2025,7 @@ func (x *DoRestoreResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DoRestoreResponse.ProtoReflect.Descriptor instead.
 func (*DoRestoreResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{15}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{29}
 }
 
 type StopEtcdRequest struct {
@@ -917,7 +2037,7 @@ type StopEtcdRequest struct {
 
 func (x *StopEtcdRequest) Reset() {
 	*x = StopEtcdRequest{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[16]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -929,7 +2049,7 @@ func (x *StopEtcdRequest) String() string {
 func (*StopEtcdRequest) ProtoMessage() {}
 
 func (x *StopEtcdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[16]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -942,7 +2062,7 @@ func (x *StopEtcdRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StopEtcdRequest.ProtoReflect.Descriptor instead.
 func (*StopEtcdRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{16}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *StopEtcdRequest) GetHeader() *CommonRequestHeader {
@@ -960,7 +2080,7 @@ type StopEtcdResponse struct {
 
 func (x *StopEtcdResponse) Reset() {
 	*x = StopEtcdResponse{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[17]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -972,7 +2092,7 @@ func (x *StopEtcdResponse) String() string {
 func (*StopEtcdResponse) ProtoMessage() {}
 
 func (x *StopEtcdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[17]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -985,7 +2105,7 @@ func (x *StopEtcdResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StopEtcdResponse.ProtoReflect.Descriptor instead.
 func (*StopEtcdResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{17}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{31}
 }
 
 type JoinClusterRequest struct {
@@ -1002,7 +2122,7 @@ type JoinClusterRequest struct {
 
 func (x *JoinClusterRequest) Reset() {
 	*x = JoinClusterRequest{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[18]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1014,7 +2134,7 @@ func (x *JoinClusterRequest) String() string {
 func (*JoinClusterRequest) ProtoMessage() {}
 
 func (x *JoinClusterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[18]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1027,7 +2147,7 @@ func (x *JoinClusterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinClusterRequest.ProtoReflect.Descriptor instead.
 func (*JoinClusterRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{18}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *JoinClusterRequest) GetHeader() *CommonRequestHeader {
@@ -1080,7 +2200,7 @@ type JoinClusterResponse struct {
 
 func (x *JoinClusterResponse) Reset() {
 	*x = JoinClusterResponse{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[19]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1092,7 +2212,7 @@ func (x *JoinClusterResponse) String() string {
 func (*JoinClusterResponse) ProtoMessage() {}
 
 func (x *JoinClusterResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[19]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1105,7 +2225,7 @@ func (x *JoinClusterResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinClusterResponse.ProtoReflect.Descriptor instead.
 func (*JoinClusterResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{19}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{33}
 }
 
 type ReconfigureRequest struct {
@@ -1122,7 +2242,7 @@ type ReconfigureRequest struct {
 
 func (x *ReconfigureRequest) Reset() {
 	*x = ReconfigureRequest{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[20]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1134,7 +2254,7 @@ func (x *ReconfigureRequest) String() string {
 func (*ReconfigureRequest) ProtoMessage() {}
 
 func (x *ReconfigureRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[20]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1147,7 +2267,7 @@ func (x *ReconfigureRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReconfigureRequest.ProtoReflect.Descriptor instead.
 func (*ReconfigureRequest) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{20}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *ReconfigureRequest) GetHeader() *CommonRequestHeader {
@@ -1186,7 +2306,7 @@ type ReconfigureResponse struct {
 
 func (x *ReconfigureResponse) Reset() {
 	*x = ReconfigureResponse{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[21]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1198,7 +2318,7 @@ func (x *ReconfigureResponse) String() string {
 func (*ReconfigureResponse) ProtoMessage() {}
 
 func (x *ReconfigureResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[21]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1211,7 +2331,7 @@ func (x *ReconfigureResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReconfigureResponse.ProtoReflect.Descriptor instead.
 func (*ReconfigureResponse) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{21}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{35}
 }
 
 type EtcdCluster struct {
@@ -1229,7 +2349,7 @@ type EtcdCluster struct {
 
 func (x *EtcdCluster) Reset() {
 	*x = EtcdCluster{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[22]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1241,7 +2361,7 @@ func (x *EtcdCluster) String() string {
 func (*EtcdCluster) ProtoMessage() {}
 
 func (x *EtcdCluster) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[22]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1254,7 +2374,7 @@ func (x *EtcdCluster) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EtcdCluster.ProtoReflect.Descriptor instead.
 func (*EtcdCluster) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{22}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *EtcdCluster) GetDesiredClusterSize() int32 {
@@ -1291,7 +2411,7 @@ type EtcdNode struct {
 
 func (x *EtcdNode) Reset() {
 	*x = EtcdNode{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[23]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1303,7 +2423,7 @@ func (x *EtcdNode) String() string {
 func (*EtcdNode) ProtoMessage() {}
 
 func (x *EtcdNode) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[23]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1316,7 +2436,7 @@ func (x *EtcdNode) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EtcdNode.ProtoReflect.Descriptor instead.
 func (*EtcdNode) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{23}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *EtcdNode) GetName() string {
@@ -1366,7 +2486,7 @@ type EtcdState struct {
 
 func (x *EtcdState) Reset() {
 	*x = EtcdState{}
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[24]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1378,7 +2498,7 @@ func (x *EtcdState) String() string {
 func (*EtcdState) ProtoMessage() {}
 
 func (x *EtcdState) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[24]
+	mi := &file_pkg_apis_etcd_etcdapi_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1391,7 +2511,7 @@ func (x *EtcdState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EtcdState.ProtoReflect.Descriptor instead.
 func (*EtcdState) Descriptor() ([]byte, []int) {
-	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{24}
+	return file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *EtcdState) GetNewCluster() bool {
@@ -1429,14 +2549,28 @@ const file_pkg_apis_etcd_etcdapi_proto_rawDesc = "" +
 	"\x1bpkg/apis/etcd/etcdapi.proto\x12\x04etcd\"S\n" +
 	"\vClusterSpec\x12!\n" +
 	"\fmember_count\x18\x01 \x01(\x05R\vmemberCount\x12!\n" +
-	"\fetcd_version\x18\x02 \x01(\tR\vetcdVersion\"j\n" +
+	"\fetcd_version\x18\x02 \x01(\tR\vetcdVersion\"\xa1\x01\n" +
 	"\aCommand\x12\x1c\n" +
 	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12A\n" +
 	"\x0erestore_backup\x18\n" +
-	" \x01(\v2\x1a.etcd.RestoreBackupCommandR\rrestoreBackup\"d\n" +
+	" \x01(\v2\x1a.etcd.RestoreBackupCommandR\rrestoreBackup\x125\n" +
+	"\n" +
+	"backup_now\x18\v \x01(\v2\x16.etcd.BackupNowCommandR\tbackupNow\"(\n" +
+	"\x10BackupNowCommand\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\"d\n" +
+	"\rCommandResult\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x1f\n" +
+	"\vbackup_name\x18\x02 \x01(\tR\n" +
+	"backupName\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x92\x01\n" +
 	"\x14RestoreBackupCommand\x124\n" +
 	"\fcluster_spec\x18\x01 \x01(\v2\x11.etcd.ClusterSpecR\vclusterSpec\x12\x16\n" +
-	"\x06backup\x18\x03 \x01(\tR\x06backup\"O\n" +
+	"\x06backup\x18\x03 \x01(\tR\x06backup\x12,\n" +
+	"\x06target\x18\x04 \x01(\v2\x14.etcd.RecoveryTargetR\x06target\"b\n" +
+	"\x0eRecoveryTarget\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x16\n" +
+	"\x06latest\x18\x03 \x01(\bR\x06latest\"O\n" +
 	"\x17CreateNewClusterCommand\x124\n" +
 	"\fcluster_spec\x18\x01 \x01(\v2\x11.etcd.ClusterSpecR\vclusterSpec\"\x10\n" +
 	"\x0eGetInfoRequest\"\xc2\x01\n" +
@@ -1456,12 +2590,87 @@ const file_pkg_apis_etcd_etcdapi_proto_rawDesc = "" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
 	"\x03dns\x18\x02 \x01(\tR\x03dns\x12\x1c\n" +
 	"\taddresses\x18\x03 \x03(\tR\taddresses\"\x19\n" +
-	"\x17UpdateEndpointsResponse\"\x83\x01\n" +
+	"\x17UpdateEndpointsResponse\"\x96\x04\n" +
 	"\n" +
 	"BackupInfo\x12!\n" +
 	"\fetcd_version\x18\x01 \x01(\tR\vetcdVersion\x12\x1c\n" +
 	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x124\n" +
-	"\fcluster_spec\x18\x03 \x01(\v2\x11.etcd.ClusterSpecR\vclusterSpec\"c\n" +
+	"\fcluster_spec\x18\x03 \x01(\v2\x11.etcd.ClusterSpecR\vclusterSpec\x12\x1f\n" +
+	"\vdata_sha256\x18\x04 \x01(\tR\n" +
+	"dataSha256\x12\x1b\n" +
+	"\tdata_size\x18\x05 \x01(\x03R\bdataSize\x12 \n" +
+	"\vcompression\x18\x06 \x01(\tR\vcompression\x12&\n" +
+	"\x0fetcd_cluster_id\x18\a \x01(\tR\retcdClusterId\x12\x1a\n" +
+	"\brevision\x18\b \x01(\x03R\brevision\x12\x1b\n" +
+	"\traft_term\x18\t \x01(\x04R\braftTerm\x12\x17\n" +
+	"\adb_size\x18\n" +
+	" \x01(\x03R\x06dbSize\x12\x1b\n" +
+	"\tkey_count\x18\v \x01(\x03R\bkeyCount\x12(\n" +
+	"\x10source_member_id\x18\f \x01(\tR\x0esourceMemberId\x12,\n" +
+	"\x12source_member_name\x18\r \x01(\tR\x10sourceMemberName\x12,\n" +
+	"\amembers\x18\x0e \x03(\v2\x12.etcd.BackupMemberR\amembers\x12\x14\n" +
+	"\x05label\x18\x0f \x01(\tR\x05label\"p\n" +
+	"\fBackupMember\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tpeer_urls\x18\x03 \x03(\tR\bpeerUrls\x12\x1f\n" +
+	"\vclient_urls\x18\x04 \x03(\tR\n" +
+	"clientUrls\"\x97\x01\n" +
+	"\vBackupState\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x124\n" +
+	"\fcluster_spec\x18\x02 \x01(\v2\x11.etcd.ClusterSpecR\vclusterSpec\x124\n" +
+	"\bkeypairs\x18\x03 \x03(\v2\x18.etcd.BackupStateKeypairR\bkeypairs\"m\n" +
+	"\x12BackupStateKeypair\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\"\n" +
+	"\fcertificates\x18\x02 \x01(\fR\fcertificates\x12\x1f\n" +
+	"\vprivate_key\x18\x03 \x01(\fR\n" +
+	"privateKey\"p\n" +
+	"\rBackupCatalog\x12+\n" +
+	"\x11rebuilt_timestamp\x18\x01 \x01(\x03R\x10rebuiltTimestamp\x122\n" +
+	"\abackups\x18\x02 \x03(\v2\x18.etcd.BackupCatalogEntryR\abackups\"\xa0\x01\n" +
+	"\x12BackupCatalogEntry\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12$\n" +
+	"\x04info\x18\x02 \x01(\v2\x10.etcd.BackupInfoR\x04info\x12!\n" +
+	"\x03pin\x18\x03 \x01(\v2\x0f.etcd.BackupPinR\x03pin\x12-\n" +
+	"\aanomaly\x18\x04 \x01(\v2\x13.etcd.BackupAnomalyR\aanomaly\"Y\n" +
+	"\tBackupPin\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06expiry\x18\x03 \x01(\x03R\x06expiry\"\xdb\x01\n" +
+	"\rBackupAnomaly\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1b\n" +
+	"\tdata_size\x18\x03 \x01(\x03R\bdataSize\x12,\n" +
+	"\x12baseline_data_size\x18\x04 \x01(\x03R\x10baselineDataSize\x12\x1b\n" +
+	"\tkey_count\x18\x05 \x01(\x03R\bkeyCount\x12,\n" +
+	"\x12baseline_key_count\x18\x06 \x01(\x03R\x10baselineKeyCount\"\xaf\x01\n" +
+	"\x12BackupVerification\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1a\n" +
+	"\brevision\x18\x04 \x01(\x03R\brevision\x12\x1b\n" +
+	"\tkey_count\x18\x05 \x01(\x03R\bkeyCount\x12\x12\n" +
+	"\x04hash\x18\x06 \x01(\rR\x04hash\"\xb5\x02\n" +
+	"\x13RevisionSegmentInfo\x12%\n" +
+	"\x0estart_revision\x18\x01 \x01(\x03R\rstartRevision\x12!\n" +
+	"\fend_revision\x18\x02 \x01(\x03R\vendRevision\x12'\n" +
+	"\x0fstart_timestamp\x18\x03 \x01(\x03R\x0estartTimestamp\x12#\n" +
+	"\rend_timestamp\x18\x04 \x01(\x03R\fendTimestamp\x12 \n" +
+	"\vcompression\x18\x05 \x01(\tR\vcompression\x12\x1f\n" +
+	"\vdata_sha256\x18\x06 \x01(\tR\n" +
+	"dataSha256\x12\x1b\n" +
+	"\tdata_size\x18\a \x01(\x03R\bdataSize\x12&\n" +
+	"\x0fetcd_cluster_id\x18\b \x01(\tR\retcdClusterId\"y\n" +
+	"\x10ArchivedRevision\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12+\n" +
+	"\x06events\x18\x03 \x03(\v2\x13.etcd.ArchivedEventR\x06events\"\x82\x01\n" +
+	"\rArchivedEvent\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x12\x16\n" +
+	"\x06delete\x18\x03 \x01(\bR\x06delete\x12\x14\n" +
+	"\x05lease\x18\x04 \x01(\x03R\x05lease\x12\x1b\n" +
+	"\tlease_ttl\x18\x05 \x01(\x03R\bleaseTtl\"c\n" +
 	"\x13CommonRequestHeader\x12)\n" +
 	"\x10leadership_token\x18\x01 \x01(\tR\x0fleadershipToken\x12!\n" +
 	"\fcluster_name\x18\x02 \x01(\tR\vclusterName\"\xb6\x01\n" +
@@ -1472,12 +2681,13 @@ const file_pkg_apis_etcd_etcdapi_proto_rawDesc = "" +
 	"\x04info\x18\n" +
 	" \x01(\v2\x10.etcd.BackupInfoR\x04info\"&\n" +
 	"\x10DoBackupResponse\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"\x80\x01\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xae\x01\n" +
 	"\x10DoRestoreRequest\x121\n" +
 	"\x06header\x18\x01 \x01(\v2\x19.etcd.CommonRequestHeaderR\x06header\x12\x18\n" +
 	"\astorage\x18\x02 \x01(\tR\astorage\x12\x1f\n" +
 	"\vbackup_name\x18\x04 \x01(\tR\n" +
-	"backupName\"\x13\n" +
+	"backupName\x12,\n" +
+	"\x06target\x18\x05 \x01(\v2\x14.etcd.RecoveryTargetR\x06target\"\x13\n" +
 	"\x11DoRestoreResponse\"D\n" +
 	"\x0fStopEtcdRequest\x121\n" +
 	"\x06header\x18\x01 \x01(\v2\x19.etcd.CommonRequestHeaderR\x06header\"\x12\n" +
@@ -1544,74 +2754,99 @@ func file_pkg_apis_etcd_etcdapi_proto_rawDescGZIP() []byte {
 }
 
 var file_pkg_apis_etcd_etcdapi_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pkg_apis_etcd_etcdapi_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_pkg_apis_etcd_etcdapi_proto_msgTypes = make([]protoimpl.MessageInfo, 39)
 var file_pkg_apis_etcd_etcdapi_proto_goTypes = []any{
 	(Phase)(0),                      // 0: etcd.Phase
 	(*ClusterSpec)(nil),             // 1: etcd.ClusterSpec
 	(*Command)(nil),                 // 2: etcd.Command
-	(*RestoreBackupCommand)(nil),    // 3: etcd.RestoreBackupCommand
-	(*CreateNewClusterCommand)(nil), // 4: etcd.CreateNewClusterCommand
-	(*GetInfoRequest)(nil),          // 5: etcd.GetInfoRequest
-	(*GetInfoResponse)(nil),         // 6: etcd.GetInfoResponse
-	(*UpdateEndpointsRequest)(nil),  // 7: etcd.UpdateEndpointsRequest
-	(*MemberMap)(nil),               // 8: etcd.MemberMap
-	(*MemberMapInfo)(nil),           // 9: etcd.MemberMapInfo
-	(*UpdateEndpointsResponse)(nil), // 10: etcd.UpdateEndpointsResponse
-	(*BackupInfo)(nil),              // 11: etcd.BackupInfo
-	(*CommonRequestHeader)(nil),     // 12: etcd.CommonRequestHeader
-	(*DoBackupRequest)(nil),         // 13: etcd.DoBackupRequest
-	(*DoBackupResponse)(nil),        // 14: etcd.DoBackupResponse
-	(*DoRestoreRequest)(nil),        // 15: etcd.DoRestoreRequest
-	(*DoRestoreResponse)(nil),       // 16: etcd.DoRestoreResponse
-	(*StopEtcdRequest)(nil),         // 17: etcd.StopEtcdRequest
-	(*StopEtcdResponse)(nil),        // 18: etcd.StopEtcdResponse
-	(*JoinClusterRequest)(nil),      // 19: etcd.JoinClusterRequest
-	(*JoinClusterResponse)(nil),     // 20: etcd.JoinClusterResponse
-	(*ReconfigureRequest)(nil),      // 21: etcd.ReconfigureRequest
-	(*ReconfigureResponse)(nil),     // 22: etcd.ReconfigureResponse
-	(*EtcdCluster)(nil),             // 23: etcd.EtcdCluster
-	(*EtcdNode)(nil),                // 24: etcd.EtcdNode
-	(*EtcdState)(nil),               // 25: etcd.EtcdState
+	(*BackupNowCommand)(nil),        // 3: etcd.BackupNowCommand
+	(*CommandResult)(nil),           // 4: etcd.CommandResult
+	(*RestoreBackupCommand)(nil),    // 5: etcd.RestoreBackupCommand
+	(*RecoveryTarget)(nil),          // 6: etcd.RecoveryTarget
+	(*CreateNewClusterCommand)(nil), // 7: etcd.CreateNewClusterCommand
+	(*GetInfoRequest)(nil),          // 8: etcd.GetInfoRequest
+	(*GetInfoResponse)(nil),         // 9: etcd.GetInfoResponse
+	(*UpdateEndpointsRequest)(nil),  // 10: etcd.UpdateEndpointsRequest
+	(*MemberMap)(nil),               // 11: etcd.MemberMap
+	(*MemberMapInfo)(nil),           // 12: etcd.MemberMapInfo
+	(*UpdateEndpointsResponse)(nil), // 13: etcd.UpdateEndpointsResponse
+	(*BackupInfo)(nil),              // 14: etcd.BackupInfo
+	(*BackupMember)(nil),            // 15: etcd.BackupMember
+	(*BackupState)(nil),             // 16: etcd.BackupState
+	(*BackupStateKeypair)(nil),      // 17: etcd.BackupStateKeypair
+	(*BackupCatalog)(nil),           // 18: etcd.BackupCatalog
+	(*BackupCatalogEntry)(nil),      // 19: etcd.BackupCatalogEntry
+	(*BackupPin)(nil),               // 20: etcd.BackupPin
+	(*BackupAnomaly)(nil),           // 21: etcd.BackupAnomaly
+	(*BackupVerification)(nil),      // 22: etcd.BackupVerification
+	(*RevisionSegmentInfo)(nil),     // 23: etcd.RevisionSegmentInfo
+	(*ArchivedRevision)(nil),        // 24: etcd.ArchivedRevision
+	(*ArchivedEvent)(nil),           // 25: etcd.ArchivedEvent
+	(*CommonRequestHeader)(nil),     // 26: etcd.CommonRequestHeader
+	(*DoBackupRequest)(nil),         // 27: etcd.DoBackupRequest
+	(*DoBackupResponse)(nil),        // 28: etcd.DoBackupResponse
+	(*DoRestoreRequest)(nil),        // 29: etcd.DoRestoreRequest
+	(*DoRestoreResponse)(nil),       // 30: etcd.DoRestoreResponse
+	(*StopEtcdRequest)(nil),         // 31: etcd.StopEtcdRequest
+	(*StopEtcdResponse)(nil),        // 32: etcd.StopEtcdResponse
+	(*JoinClusterRequest)(nil),      // 33: etcd.JoinClusterRequest
+	(*JoinClusterResponse)(nil),     // 34: etcd.JoinClusterResponse
+	(*ReconfigureRequest)(nil),      // 35: etcd.ReconfigureRequest
+	(*ReconfigureResponse)(nil),     // 36: etcd.ReconfigureResponse
+	(*EtcdCluster)(nil),             // 37: etcd.EtcdCluster
+	(*EtcdNode)(nil),                // 38: etcd.EtcdNode
+	(*EtcdState)(nil),               // 39: etcd.EtcdState
 }
 var file_pkg_apis_etcd_etcdapi_proto_depIdxs = []int32{
-	3,  // 0: etcd.Command.restore_backup:type_name -> etcd.RestoreBackupCommand
-	1,  // 1: etcd.RestoreBackupCommand.cluster_spec:type_name -> etcd.ClusterSpec
-	1,  // 2: etcd.CreateNewClusterCommand.cluster_spec:type_name -> etcd.ClusterSpec
-	24, // 3: etcd.GetInfoResponse.node_configuration:type_name -> etcd.EtcdNode
-	25, // 4: etcd.GetInfoResponse.etcd_state:type_name -> etcd.EtcdState
-	8,  // 5: etcd.UpdateEndpointsRequest.member_map:type_name -> etcd.MemberMap
-	9,  // 6: etcd.MemberMap.members:type_name -> etcd.MemberMapInfo
-	1,  // 7: etcd.BackupInfo.cluster_spec:type_name -> etcd.ClusterSpec
-	12, // 8: etcd.DoBackupRequest.header:type_name -> etcd.CommonRequestHeader
-	11, // 9: etcd.DoBackupRequest.info:type_name -> etcd.BackupInfo
-	12, // 10: etcd.DoRestoreRequest.header:type_name -> etcd.CommonRequestHeader
-	12, // 11: etcd.StopEtcdRequest.header:type_name -> etcd.CommonRequestHeader
-	12, // 12: etcd.JoinClusterRequest.header:type_name -> etcd.CommonRequestHeader
-	0,  // 13: etcd.JoinClusterRequest.phase:type_name -> etcd.Phase
-	24, // 14: etcd.JoinClusterRequest.nodes:type_name -> etcd.EtcdNode
-	24, // 15: etcd.JoinClusterRequest.add_node:type_name -> etcd.EtcdNode
-	12, // 16: etcd.ReconfigureRequest.header:type_name -> etcd.CommonRequestHeader
-	24, // 17: etcd.EtcdCluster.nodes:type_name -> etcd.EtcdNode
-	23, // 18: etcd.EtcdState.cluster:type_name -> etcd.EtcdCluster
-	5,  // 19: etcd.EtcdManagerService.GetInfo:input_type -> etcd.GetInfoRequest
-	7,  // 20: etcd.EtcdManagerService.UpdateEndpoints:input_type -> etcd.UpdateEndpointsRequest
-	19, // 21: etcd.EtcdManagerService.JoinCluster:input_type -> etcd.JoinClusterRequest
-	21, // 22: etcd.EtcdManagerService.Reconfigure:input_type -> etcd.ReconfigureRequest
-	13, // 23: etcd.EtcdManagerService.DoBackup:input_type -> etcd.DoBackupRequest
-	15, // 24: etcd.EtcdManagerService.DoRestore:input_type -> etcd.DoRestoreRequest
-	17, // 25: etcd.EtcdManagerService.StopEtcd:input_type -> etcd.StopEtcdRequest
-	6,  // 26: etcd.EtcdManagerService.GetInfo:output_type -> etcd.GetInfoResponse
-	10, // 27: etcd.EtcdManagerService.UpdateEndpoints:output_type -> etcd.UpdateEndpointsResponse
-	20, // 28: etcd.EtcdManagerService.JoinCluster:output_type -> etcd.JoinClusterResponse
-	22, // 29: etcd.EtcdManagerService.Reconfigure:output_type -> etcd.ReconfigureResponse
-	14, // 30: etcd.EtcdManagerService.DoBackup:output_type -> etcd.DoBackupResponse
-	16, // 31: etcd.EtcdManagerService.DoRestore:output_type -> etcd.DoRestoreResponse
-	18, // 32: etcd.EtcdManagerService.StopEtcd:output_type -> etcd.StopEtcdResponse
-	26, // [26:33] is the sub-list for method output_type
-	19, // [19:26] is the sub-list for method input_type
-	19, // [19:19] is the sub-list for extension type_name
-	19, // [19:19] is the sub-list for extension extendee
-	0,  // [0:19] is the sub-list for field type_name
+	5,  // 0: etcd.Command.restore_backup:type_name -> etcd.RestoreBackupCommand
+	3,  // 1: etcd.Command.backup_now:type_name -> etcd.BackupNowCommand
+	1,  // 2: etcd.RestoreBackupCommand.cluster_spec:type_name -> etcd.ClusterSpec
+	6,  // 3: etcd.RestoreBackupCommand.target:type_name -> etcd.RecoveryTarget
+	1,  // 4: etcd.CreateNewClusterCommand.cluster_spec:type_name -> etcd.ClusterSpec
+	38, // 5: etcd.GetInfoResponse.node_configuration:type_name -> etcd.EtcdNode
+	39, // 6: etcd.GetInfoResponse.etcd_state:type_name -> etcd.EtcdState
+	11, // 7: etcd.UpdateEndpointsRequest.member_map:type_name -> etcd.MemberMap
+	12, // 8: etcd.MemberMap.members:type_name -> etcd.MemberMapInfo
+	1,  // 9: etcd.BackupInfo.cluster_spec:type_name -> etcd.ClusterSpec
+	15, // 10: etcd.BackupInfo.members:type_name -> etcd.BackupMember
+	1,  // 11: etcd.BackupState.cluster_spec:type_name -> etcd.ClusterSpec
+	17, // 12: etcd.BackupState.keypairs:type_name -> etcd.BackupStateKeypair
+	19, // 13: etcd.BackupCatalog.backups:type_name -> etcd.BackupCatalogEntry
+	14, // 14: etcd.BackupCatalogEntry.info:type_name -> etcd.BackupInfo
+	20, // 15: etcd.BackupCatalogEntry.pin:type_name -> etcd.BackupPin
+	21, // 16: etcd.BackupCatalogEntry.anomaly:type_name -> etcd.BackupAnomaly
+	25, // 17: etcd.ArchivedRevision.events:type_name -> etcd.ArchivedEvent
+	26, // 18: etcd.DoBackupRequest.header:type_name -> etcd.CommonRequestHeader
+	14, // 19: etcd.DoBackupRequest.info:type_name -> etcd.BackupInfo
+	26, // 20: etcd.DoRestoreRequest.header:type_name -> etcd.CommonRequestHeader
+	6,  // 21: etcd.DoRestoreRequest.target:type_name -> etcd.RecoveryTarget
+	26, // 22: etcd.StopEtcdRequest.header:type_name -> etcd.CommonRequestHeader
+	26, // 23: etcd.JoinClusterRequest.header:type_name -> etcd.CommonRequestHeader
+	0,  // 24: etcd.JoinClusterRequest.phase:type_name -> etcd.Phase
+	38, // 25: etcd.JoinClusterRequest.nodes:type_name -> etcd.EtcdNode
+	38, // 26: etcd.JoinClusterRequest.add_node:type_name -> etcd.EtcdNode
+	26, // 27: etcd.ReconfigureRequest.header:type_name -> etcd.CommonRequestHeader
+	38, // 28: etcd.EtcdCluster.nodes:type_name -> etcd.EtcdNode
+	37, // 29: etcd.EtcdState.cluster:type_name -> etcd.EtcdCluster
+	8,  // 30: etcd.EtcdManagerService.GetInfo:input_type -> etcd.GetInfoRequest
+	10, // 31: etcd.EtcdManagerService.UpdateEndpoints:input_type -> etcd.UpdateEndpointsRequest
+	33, // 32: etcd.EtcdManagerService.JoinCluster:input_type -> etcd.JoinClusterRequest
+	35, // 33: etcd.EtcdManagerService.Reconfigure:input_type -> etcd.ReconfigureRequest
+	27, // 34: etcd.EtcdManagerService.DoBackup:input_type -> etcd.DoBackupRequest
+	29, // 35: etcd.EtcdManagerService.DoRestore:input_type -> etcd.DoRestoreRequest
+	31, // 36: etcd.EtcdManagerService.StopEtcd:input_type -> etcd.StopEtcdRequest
+	9,  // 37: etcd.EtcdManagerService.GetInfo:output_type -> etcd.GetInfoResponse
+	13, // 38: etcd.EtcdManagerService.UpdateEndpoints:output_type -> etcd.UpdateEndpointsResponse
+	34, // 39: etcd.EtcdManagerService.JoinCluster:output_type -> etcd.JoinClusterResponse
+	36, // 40: etcd.EtcdManagerService.Reconfigure:output_type -> etcd.ReconfigureResponse
+	28, // 41: etcd.EtcdManagerService.DoBackup:output_type -> etcd.DoBackupResponse
+	30, // 42: etcd.EtcdManagerService.DoRestore:output_type -> etcd.DoRestoreResponse
+	32, // 43: etcd.EtcdManagerService.StopEtcd:output_type -> etcd.StopEtcdResponse
+	37, // [37:44] is the sub-list for method output_type
+	30, // [30:37] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_pkg_apis_etcd_etcdapi_proto_init() }
@@ -1625,7 +2860,7 @@ func file_pkg_apis_etcd_etcdapi_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pkg_apis_etcd_etcdapi_proto_rawDesc), len(file_pkg_apis_etcd_etcdapi_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   25,
+			NumMessages:   39,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,6 +26,8 @@ func ToJson(o proto.Message) (string, error) {
 	return string(b), err
 }
 
+// FromJson parses a message written by ToJson.  Unknown fields are ignored, so that we can read
+// messages written by newer versions, which may have added fields.
 func FromJson(s string, o proto.Message) error {
-	return protojson.Unmarshal([]byte(s), o)
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal([]byte(s), o)
 }
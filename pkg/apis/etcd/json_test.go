@@ -42,6 +42,9 @@ func TestRoundTrip(t *testing.T) {
 				EtcdVersion: "3.4.13",
 				Timestamp:   1704067200,
 				ClusterSpec: &ClusterSpec{MemberCount: 3, EtcdVersion: "3.4.13"},
+				DataSha256:  "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				DataSize:    4096,
+				Compression: "zstd",
 			},
 			newEmpty: func() proto.Message { return &BackupInfo{} },
 		},
@@ -155,3 +158,23 @@ func TestParsesLegacyJsonpbFormat(t *testing.T) {
 		})
 	}
 }
+
+// TestIgnoresUnknownFields verifies that we can read messages written by a newer version,
+// so that rolling back does not leave us unable to read newer backups and commands.
+func TestIgnoresUnknownFields(t *testing.T) {
+	input := `{
+  "etcdVersion": "3.4.13",
+  "timestamp": "1704067200",
+  "someFutureField": {
+    "nested": true
+  }
+}`
+	got := &BackupInfo{}
+	if err := FromJson(input, got); err != nil {
+		t.Fatalf("FromJson: %v", err)
+	}
+	want := &BackupInfo{EtcdVersion: "3.4.13", Timestamp: 1704067200}
+	if !proto.Equal(want, got) {
+		t.Fatalf("mismatch\n  got:  %v\n  want: %v", got, want)
+	}
+}
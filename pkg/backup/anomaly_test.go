@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func TestVFSStoreAnomaly(t *testing.T) {
+	s, dir := newTestVFSStore(t, nil)
+	store := s.(*vfsStore)
+	catalog := Catalog(store)
+	name := addTestBackup(t, store, []byte("backup data"))
+
+	if err := store.FlagAnomaly("2000-01-01T00:00:00Z-000001", &etcd.BackupAnomaly{}); err == nil {
+		t.Errorf("expected an error flagging a backup that does not exist")
+	}
+
+	if _, err := catalog.ListCatalog(); err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+
+	anomaly := &etcd.BackupAnomaly{Timestamp: 1700000000, Reason: "data size 10 is 1% of the baseline 1000", DataSize: 10, BaselineDataSize: 1000}
+	if err := store.FlagAnomaly(name, anomaly); err != nil {
+		t.Fatalf("FlagAnomaly failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, name, AnomalyFilename)); err != nil {
+		t.Errorf("expected anomaly alongside the backup: %v", err)
+	}
+
+	loaded, err := store.LoadAnomaly(name)
+	if err != nil {
+		t.Fatalf("LoadAnomaly failed: %v", err)
+	}
+	if !proto.Equal(loaded, anomaly) {
+		t.Errorf("LoadAnomaly returned %v, expected %v", loaded, anomaly)
+	}
+
+	entries, err := catalog.ListCatalog()
+	if err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+	if len(entries) != 1 || !proto.Equal(entries[0].Anomaly, anomaly) {
+		t.Errorf("catalog did not record the anomaly: %v", entries)
+	}
+
+	// A rebuilt catalog picks up the anomaly
+	if err := catalog.RebuildCatalog(); err != nil {
+		t.Fatalf("RebuildCatalog failed: %v", err)
+	}
+	entries, err = catalog.ListCatalog()
+	if err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+	if len(entries) != 1 || !proto.Equal(entries[0].Anomaly, anomaly) {
+		t.Errorf("rebuilt catalog did not record the anomaly: %v", entries)
+	}
+
+	if err := store.ClearAnomaly(name); err != nil {
+		t.Fatalf("ClearAnomaly failed: %v", err)
+	}
+	if loaded, err := store.LoadAnomaly(name); err != nil || loaded != nil {
+		t.Errorf("expected no anomaly after clearing, got %v (err=%v)", loaded, err)
+	}
+	entries, err = catalog.ListCatalog()
+	if err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Anomaly != nil {
+		t.Errorf("catalog did not record the cleared anomaly: %v", entries)
+	}
+
+	// Clearing an unflagged backup is not an error
+	if err := store.ClearAnomaly(name); err != nil {
+		t.Errorf("ClearAnomaly of unflagged backup failed: %v", err)
+	}
+}
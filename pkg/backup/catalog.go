@@ -0,0 +1,288 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// CatalogFilename is the index of the backups in a store, kept at the root of the store
+const CatalogFilename = "_etcd_backups.catalog"
+
+// CatalogMaxAge is how long we trust the catalog before rebuilding it from a full listing of the store.
+// Listings are served from the catalog alone, so this bounds how long changes made without updating it
+// (by tools that do not maintain it, or by concurrent writers overwriting each other's updates) go unnoticed.
+var CatalogMaxAge = 24 * time.Hour
+
+func init() {
+	if s := os.Getenv("ETCD_MANAGER_BACKUP_CATALOG_MAX_AGE"); s != "" {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			klog.Fatalf("failed to parse ETCD_MANAGER_BACKUP_CATALOG_MAX_AGE=%q", s)
+		}
+		CatalogMaxAge = v
+	}
+}
+
+// Catalog is implemented by stores that keep an index of their backups, so that listing them is cheap
+type Catalog interface {
+	// ListCatalog returns the catalog entry for every backup, in chronological order
+	ListCatalog() ([]*etcd.BackupCatalogEntry, error)
+
+	// RebuildCatalog rebuilds the catalog from a full listing of the store
+	RebuildCatalog() error
+}
+
+var _ Catalog = &vfsStore{}
+
+// ListEntries returns the catalog entry for every backup in the store, in chronological order.
+// Stores without a catalog are listed directly, loading the info, pin and anomaly flag of each backup.
+func ListEntries(store Store) ([]*etcd.BackupCatalogEntry, error) {
+	if catalog, ok := store.(Catalog); ok {
+		entries, err := catalog.ListCatalog()
+		if err != nil {
+			return nil, fmt.Errorf("error listing backups: %w", err)
+		}
+		return entries, nil
+	}
+
+	names, err := store.ListBackups()
+	if err != nil {
+		return nil, fmt.Errorf("error listing backups: %w", err)
+	}
+	pins, _ := store.(PinStore)
+	anomalies, _ := store.(AnomalyStore)
+
+	var entries []*etcd.BackupCatalogEntry
+	for _, name := range names {
+		entry := &etcd.BackupCatalogEntry{Name: name}
+		entry.Info, err = store.LoadInfo(name)
+		if err != nil {
+			return nil, fmt.Errorf("error loading info for backup %q: %w", name, err)
+		}
+		if pins != nil {
+			entry.Pin, err = pins.LoadPin(name)
+			if err != nil {
+				return nil, fmt.Errorf("error loading pin for backup %q: %w", name, err)
+			}
+		}
+		if anomalies != nil {
+			entry.Anomaly, err = anomalies.LoadAnomaly(name)
+			if err != nil {
+				return nil, fmt.Errorf("error loading anomaly for backup %q: %w", name, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (s *vfsStore) ListCatalog() ([]*etcd.BackupCatalogEntry, error) {
+	ctx := context.TODO()
+
+	s.catalogMutex.Lock()
+	defer s.catalogMutex.Unlock()
+
+	catalog, err := s.loadCatalog(ctx)
+	if err != nil {
+		klog.Warningf("ignoring unreadable backup catalog: %v", err)
+		catalog = nil
+	}
+	if catalog != nil && time.Since(time.Unix(catalog.RebuiltTimestamp, 0)) > CatalogMaxAge {
+		klog.Infof("backup catalog in %s was last rebuilt at %s, rebuilding", s.backupsBase, time.Unix(catalog.RebuiltTimestamp, 0).UTC().Format(time.RFC3339))
+		catalog = nil
+	}
+	if catalog == nil {
+		catalog, err = s.rebuildCatalog(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return catalog.Backups, nil
+}
+
+func (s *vfsStore) RebuildCatalog() error {
+	s.catalogMutex.Lock()
+	defer s.catalogMutex.Unlock()
+
+	_, err := s.rebuildCatalog(context.TODO())
+	return err
+}
+
+// rebuildCatalog lists the backups in the store, and writes a new catalog of them
+func (s *vfsStore) rebuildCatalog(ctx context.Context) (*etcd.BackupCatalog, error) {
+	names, err := s.listBackupsFromTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := &etcd.BackupCatalog{
+		RebuiltTimestamp: time.Now().Unix(),
+	}
+	for _, name := range names {
+		entry, err := s.loadCatalogEntry(name)
+		if err != nil {
+			return nil, err
+		}
+		catalog.Backups = append(catalog.Backups, entry)
+	}
+
+	// An empty store is cheap to list, so we don't create a catalog until there is something in it
+	if len(catalog.Backups) != 0 {
+		if err := s.writeCatalog(ctx, catalog); err != nil {
+			klog.Warningf("error writing backup catalog: %v", err)
+		}
+	}
+
+	klog.Infof("rebuilt backup catalog in %s with %d backups", s.backupsBase, len(catalog.Backups))
+	return catalog, nil
+}
+
+// loadCatalogEntry reads the catalog entry for a backup from the store
+func (s *vfsStore) loadCatalogEntry(name string) (*etcd.BackupCatalogEntry, error) {
+	info, err := s.LoadInfo(name)
+	if err != nil {
+		klog.Warningf("error loading info for backup %q, cataloging it without info: %v", name, err)
+		info = nil
+	}
+	pin, err := s.LoadPin(name)
+	if err != nil {
+		// Failing here is safer than cataloging a pinned backup as unpinned, where retention could remove it
+		return nil, fmt.Errorf("error loading pin for backup %q: %w", name, err)
+	}
+	anomaly, err := s.LoadAnomaly(name)
+	if err != nil {
+		// Similarly, cleanup must know which backups are flagged to keep the last known-good backup
+		return nil, fmt.Errorf("error loading anomaly for backup %q: %w", name, err)
+	}
+	return &etcd.BackupCatalogEntry{Name: name, Info: info, Pin: pin, Anomaly: anomaly}, nil
+}
+
+// updateCatalog applies fn to the catalog, if there is one.
+// Failures are logged rather than returned, because the backups themselves have already been changed;
+// we remove a catalog we could not update, so that it will be rebuilt on the next listing.
+// Concurrent writers in other processes can overwrite each other's updates; the catalog is corrected when it is next rebuilt.
+func (s *vfsStore) updateCatalog(ctx context.Context, fn func(catalog *etcd.BackupCatalog)) {
+	s.catalogMutex.Lock()
+	defer s.catalogMutex.Unlock()
+
+	catalog, err := s.loadCatalog(ctx)
+	if err == nil && catalog == nil {
+		// The next listing will build the catalog
+		return
+	}
+	if err == nil {
+		fn(catalog)
+		err = s.writeCatalog(ctx, catalog)
+	}
+	if err != nil {
+		klog.Warningf("error updating backup catalog, removing it so that it will be rebuilt: %v", err)
+		p := s.backupsBase.Join(CatalogFilename)
+		if err := p.Remove(ctx); err != nil && !os.IsNotExist(err) {
+			klog.Warningf("error removing backup catalog %q: %v", p, err)
+		}
+	}
+}
+
+// addToCatalog records a new backup in the catalog
+func (s *vfsStore) addToCatalog(ctx context.Context, name string, info *etcd.BackupInfo) {
+	s.updateCatalog(ctx, func(catalog *etcd.BackupCatalog) {
+		removeCatalogEntry(catalog, name)
+		catalog.Backups = append(catalog.Backups, &etcd.BackupCatalogEntry{Name: name, Info: info})
+		sort.Slice(catalog.Backups, func(i, j int) bool {
+			return catalog.Backups[i].Name < catalog.Backups[j].Name
+		})
+	})
+}
+
+// removeFromCatalog removes a deleted backup from the catalog
+func (s *vfsStore) removeFromCatalog(ctx context.Context, name string) {
+	s.updateCatalog(ctx, func(catalog *etcd.BackupCatalog) {
+		removeCatalogEntry(catalog, name)
+	})
+}
+
+// setCatalogPin records the pin for a backup in the catalog; a nil pin marks it as unpinned
+func (s *vfsStore) setCatalogPin(ctx context.Context, name string, pin *etcd.BackupPin) {
+	s.updateCatalog(ctx, func(catalog *etcd.BackupCatalog) {
+		for _, entry := range catalog.Backups {
+			if entry.Name == name {
+				entry.Pin = pin
+			}
+		}
+	})
+}
+
+// setCatalogAnomaly records the anomaly for a backup in the catalog; a nil anomaly clears the flag
+func (s *vfsStore) setCatalogAnomaly(ctx context.Context, name string, anomaly *etcd.BackupAnomaly) {
+	s.updateCatalog(ctx, func(catalog *etcd.BackupCatalog) {
+		for _, entry := range catalog.Backups {
+			if entry.Name == name {
+				entry.Anomaly = anomaly
+			}
+		}
+	})
+}
+
+func removeCatalogEntry(catalog *etcd.BackupCatalog, name string) {
+	var backups []*etcd.BackupCatalogEntry
+	for _, entry := range catalog.Backups {
+		if entry.Name != name {
+			backups = append(backups, entry)
+		}
+	}
+	catalog.Backups = backups
+}
+
+// loadCatalog reads the catalog, returning nil if there is none
+func (s *vfsStore) loadCatalog(ctx context.Context) (*etcd.BackupCatalog, error) {
+	p := s.backupsBase.Join(CatalogFilename)
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading file %q: %v", p, err)
+	}
+
+	catalog := &etcd.BackupCatalog{}
+	if err := etcd.FromJson(string(data), catalog); err != nil {
+		return nil, fmt.Errorf("error parsing file %q: %v", p, err)
+	}
+	return catalog, nil
+}
+
+func (s *vfsStore) writeCatalog(ctx context.Context, catalog *etcd.BackupCatalog) error {
+	p := s.backupsBase.Join(CatalogFilename)
+
+	data, err := etcd.ToJson(catalog)
+	if err != nil {
+		return fmt.Errorf("error marshalling backup catalog: %v", err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader([]byte(data)), nil); err != nil {
+		return fmt.Errorf("error writing file %q: %v", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func addTestBackupWithSequence(t *testing.T, store Store, sequence string) string {
+	srcFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+	if err := os.WriteFile(srcFile, []byte("backup "+sequence), 0600); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+	name, err := store.AddBackup(srcFile, sequence, &etcd.BackupInfo{EtcdVersion: "3.5.0"})
+	if err != nil {
+		t.Fatalf("AddBackup failed: %v", err)
+	}
+	return name
+}
+
+func listTestBackups(t *testing.T, store Store) []string {
+	backups, err := store.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	return backups
+}
+
+func TestVFSStoreCatalog(t *testing.T) {
+	store, dir := newTestVFSStore(t, nil)
+	s := store.(*vfsStore)
+	ctx := context.TODO()
+
+	if backups := listTestBackups(t, store); len(backups) != 0 {
+		t.Fatalf("expected no backups, got %v", backups)
+	}
+	if _, err := os.Stat(filepath.Join(dir, CatalogFilename)); !os.IsNotExist(err) {
+		t.Errorf("expected no catalog for an empty store, got err=%v", err)
+	}
+
+	first := addTestBackupWithSequence(t, store, "000001")
+
+	// The first listing builds the catalog; later changes keep it up to date
+	if backups := listTestBackups(t, store); !reflect.DeepEqual(backups, []string{first}) {
+		t.Fatalf("ListBackups returned %v", backups)
+	}
+	second := addTestBackupWithSequence(t, store, "000002")
+
+	catalog, err := s.loadCatalog(ctx)
+	if err != nil || catalog == nil {
+		t.Fatalf("expected a catalog, got %v (err=%v)", catalog, err)
+	}
+	if len(catalog.Backups) != 2 || catalog.Backups[1].Name != second {
+		t.Fatalf("catalog was not updated on add: %v", catalog)
+	}
+	if catalog.Backups[1].Info.GetDataSize() == 0 {
+		t.Errorf("expected catalog to hold the backup info, got %v", catalog.Backups[1])
+	}
+
+	if err := store.RemoveBackup(first); err != nil {
+		t.Fatalf("RemoveBackup failed: %v", err)
+	}
+	catalog, err = s.loadCatalog(ctx)
+	if err != nil || len(catalog.Backups) != 1 || catalog.Backups[0].Name != second {
+		t.Fatalf("catalog was not updated on remove: %v (err=%v)", catalog, err)
+	}
+
+	// A backup written without updating the catalog (for example by another process) is not listed until the catalog is rebuilt
+	data, err := os.ReadFile(filepath.Join(dir, second, MetaFilename))
+	if err != nil {
+		t.Fatalf("failed to read meta: %v", err)
+	}
+	hidden := "2000-01-01T00:00:00Z-000001"
+	if err := os.MkdirAll(filepath.Join(dir, hidden), 0755); err != nil {
+		t.Fatalf("failed to create backup dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, hidden, MetaFilename), data, 0600); err != nil {
+		t.Fatalf("failed to write meta: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, hidden, PinFilename), []byte(`{"reason":"keep"}`), 0600); err != nil {
+		t.Fatalf("failed to write pin: %v", err)
+	}
+	if backups := listTestBackups(t, store); !reflect.DeepEqual(backups, []string{second}) {
+		t.Errorf("expected listings to be served from the catalog, got %v", backups)
+	}
+	if err := s.RebuildCatalog(); err != nil {
+		t.Fatalf("RebuildCatalog failed: %v", err)
+	}
+	entries, err := s.ListCatalog()
+	if err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != hidden || entries[0].Pin.GetReason() != "keep" {
+		t.Errorf("expected the rebuilt catalog to pick up the backup and its pin, got %v", entries)
+	}
+
+	// Similarly, a stale catalog is rebuilt
+	if err := os.RemoveAll(filepath.Join(dir, hidden)); err != nil {
+		t.Fatalf("failed to remove backup: %v", err)
+	}
+	catalog, err = s.loadCatalog(ctx)
+	if err != nil || catalog == nil {
+		t.Fatalf("expected a catalog, got %v (err=%v)", catalog, err)
+	}
+	if backups := listTestBackups(t, store); !reflect.DeepEqual(backups, []string{hidden, second}) {
+		t.Errorf("expected listings to be served from the catalog, got %v", backups)
+	}
+	catalog.RebuiltTimestamp = time.Now().Add(-2 * CatalogMaxAge).Unix()
+	if err := s.writeCatalog(ctx, catalog); err != nil {
+		t.Fatalf("writeCatalog failed: %v", err)
+	}
+	if backups := listTestBackups(t, store); !reflect.DeepEqual(backups, []string{second}) {
+		t.Errorf("expected a stale catalog to be rebuilt, got %v", backups)
+	}
+
+	// A missing or unreadable catalog is rebuilt
+	if err := os.WriteFile(filepath.Join(dir, CatalogFilename), []byte("not json"), 0600); err != nil {
+		t.Fatalf("failed to corrupt catalog: %v", err)
+	}
+	if backups := listTestBackups(t, store); !reflect.DeepEqual(backups, []string{second}) {
+		t.Errorf("expected a corrupt catalog to be rebuilt, got %v", backups)
+	}
+	if err := os.Remove(filepath.Join(dir, CatalogFilename)); err != nil {
+		t.Fatalf("failed to remove catalog: %v", err)
+	}
+	if backups := listTestBackups(t, store); !reflect.DeepEqual(backups, []string{second}) {
+		t.Errorf("expected a missing catalog to be rebuilt, got %v", backups)
+	}
+	if _, err := os.Stat(filepath.Join(dir, CatalogFilename)); err != nil {
+		t.Errorf("expected the catalog to be rewritten: %v", err)
+	}
+}
+
+// plainStore hides the optional interfaces of the store it wraps
+type plainStore struct {
+	Store
+}
+
+func TestListEntries(t *testing.T) {
+	s, _ := newTestVFSStore(t, nil)
+	store := s.(*vfsStore)
+	first := addTestBackupWithSequence(t, store, "000001")
+	second := addTestBackupWithSequence(t, store, "000002")
+	if err := store.PinBackup(first, &etcd.BackupPin{Reason: "compliance"}); err != nil {
+		t.Fatalf("PinBackup failed: %v", err)
+	}
+
+	entries, err := ListEntries(store)
+	if err != nil {
+		t.Fatalf("ListEntries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != first || entries[1].Name != second {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+	if entries[0].Pin.GetReason() != "compliance" || entries[1].Pin != nil {
+		t.Errorf("expected only the first backup to be pinned, got %v", entries)
+	}
+
+	// Stores without a catalog are listed directly, without pins or anomalies if they cannot record them
+	entries, err = ListEntries(&plainStore{Store: store})
+	if err != nil {
+		t.Fatalf("ListEntries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != first || entries[0].Info == nil || entries[0].Pin != nil {
+		t.Errorf("unexpected entries %v", entries)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// ErrIntegrity is returned (wrapped) when backup data does not match the checksum recorded when it was taken,
+// or otherwise fails validation (for example, failing to decrypt).
+var ErrIntegrity = errors.New("backup integrity check failed")
+
+// checksumWriter computes the SHA-256 and size of everything written to it
+type checksumWriter struct {
+	hash hash.Hash
+	size int64
+}
+
+func newChecksumWriter() *checksumWriter {
+	return &checksumWriter{hash: sha256.New()}
+}
+
+func (c *checksumWriter) Write(p []byte) (int, error) {
+	n, err := c.hash.Write(p)
+	c.size += int64(n)
+	return n, err
+}
+
+// SHA256 returns the hex-encoded SHA-256 of the data written so far
+func (c *checksumWriter) SHA256() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+// computeChecksum returns the hex-encoded SHA-256 and the size of the data in r
+func computeChecksum(r io.Reader) (string, int64, error) {
+	c := newChecksumWriter()
+	if _, err := io.Copy(c, r); err != nil {
+		return "", 0, err
+	}
+	return c.SHA256(), c.size, nil
+}
+
+// verify checks the data we have seen against the expected checksum and size.
+// Backups taken before checksums were recorded have no checksum, and are not checked.
+func (c *checksumWriter) verify(name string, expectedSha256 string, expectedSize int64) error {
+	if expectedSha256 == "" {
+		return nil
+	}
+	if expectedSize != c.size {
+		return fmt.Errorf("%w: backup %q has size %d bytes, expected %d bytes (truncated?)", ErrIntegrity, name, c.size, expectedSize)
+	}
+	if actual := c.SHA256(); expectedSha256 != actual {
+		return fmt.Errorf("%w: backup %q has sha256 %s, expected %s", ErrIntegrity, name, actual, expectedSha256)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressionEnv is the environment variable selecting the compression codec for new backups.
+// If not set, backups are compressed with gzip.
+const CompressionEnv = "ETCD_MANAGER_BACKUP_COMPRESSION"
+
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// Codec compresses and decompresses the backup data file
+type Codec interface {
+	// Name is the name recorded in the backup info, identifying the codec used for a backup
+	Name() string
+
+	// Extension is the file extension for data compressed with this codec, e.g. ".gz"
+	Extension() string
+
+	// NewWriter returns a writer that compresses data written to it into w
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+
+	// NewReader returns a reader that decompresses data read from r
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+var codecs = map[string]Codec{
+	CompressionGzip: &gzipCodec{},
+	CompressionZstd: &zstdCodec{},
+}
+
+// CodecFor returns the codec with the specified name.
+// Backups taken before the codec was recorded have an empty name, and are gzip compressed.
+func CodecFor(name string) (Codec, error) {
+	if name == "" {
+		name = CompressionGzip
+	}
+	codec := codecs[name]
+	if codec == nil {
+		var known []string
+		for k := range codecs {
+			known = append(known, k)
+		}
+		sort.Strings(known)
+		return nil, fmt.Errorf("unknown backup compression %q (supported: %s)", name, strings.Join(known, ", "))
+	}
+	return codec, nil
+}
+
+// DataFilenameFor returns the name of the data file for backups compressed with codec
+func DataFilenameFor(codec Codec) string {
+	return "etcd.backup" + codec.Extension()
+}
+
+// CodecFromEnv returns the codec named by ETCD_MANAGER_BACKUP_COMPRESSION, defaulting to gzip
+func CodecFromEnv() (Codec, error) {
+	return CodecFor(os.Getenv(CompressionEnv))
+}
+
+type gzipCodec struct{}
+
+func (c *gzipCodec) Name() string {
+	return CompressionGzip
+}
+
+func (c *gzipCodec) Extension() string {
+	return ".gz"
+}
+
+func (c *gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (c *gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type zstdCodec struct{}
+
+func (c *zstdCodec) Name() string {
+	return CompressionZstd
+}
+
+func (c *zstdCodec) Extension() string {
+	return ".zst"
+}
+
+func (c *zstdCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
+
+func (c *zstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	d, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
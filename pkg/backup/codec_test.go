@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("etcd snapshot data "), 10000)
+
+	for _, name := range []string{CompressionGzip, CompressionZstd} {
+		codec, err := CodecFor(name)
+		if err != nil {
+			t.Fatalf("CodecFor(%q) failed: %v", name, err)
+		}
+		if codec.Name() != name {
+			t.Errorf("CodecFor(%q) returned codec %q", name, codec.Name())
+		}
+
+		var compressed bytes.Buffer
+		w, err := codec.NewWriter(&compressed)
+		if err != nil {
+			t.Fatalf("%s: NewWriter failed: %v", name, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("%s: write failed: %v", name, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: close failed: %v", name, err)
+		}
+		if compressed.Len() >= len(data) {
+			t.Errorf("%s: compressed size %d was not smaller than %d", name, compressed.Len(), len(data))
+		}
+
+		r, err := codec.NewReader(&compressed)
+		if err != nil {
+			t.Fatalf("%s: NewReader failed: %v", name, err)
+		}
+		expanded, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%s: read failed: %v", name, err)
+		}
+		if err := r.Close(); err != nil {
+			t.Fatalf("%s: reader close failed: %v", name, err)
+		}
+		if !bytes.Equal(expanded, data) {
+			t.Errorf("%s: expanded data did not match original", name)
+		}
+	}
+}
+
+func TestCodecFor(t *testing.T) {
+	codec, err := CodecFor("")
+	if err != nil {
+		t.Fatalf("CodecFor(\"\") failed: %v", err)
+	}
+	if codec.Name() != CompressionGzip || DataFilenameFor(codec) != DataFilename {
+		t.Errorf("expected backups without a recorded codec to be gzip, got %q", codec.Name())
+	}
+
+	if _, err := CodecFor("lz4"); err == nil {
+		t.Errorf("expected error for unknown codec")
+	}
+}
+
+func TestVFSStoreZstd(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	p, err := vfs.Context.BuildVfsPath(dir)
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	codec, err := CodecFor(CompressionZstd)
+	if err != nil {
+		t.Fatalf("CodecFor failed: %v", err)
+	}
+	store, err := NewVFSStore(p, codec, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+	if store.Codec().Name() != CompressionZstd {
+		t.Errorf("expected store codec to be zstd, got %q", store.Codec().Name())
+	}
+
+	data := []byte("zstd compressed snapshot")
+	srcFile := filepath.Join(t.TempDir(), "snapshot.db.zst")
+	if err := os.WriteFile(srcFile, data, 0600); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+	name, err := store.AddBackup(srcFile, "000001", &etcd.BackupInfo{EtcdVersion: "3.5.0", Compression: CompressionZstd})
+	if err != nil {
+		t.Fatalf("AddBackup failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, name, DataFilenameFor(codec))); err != nil {
+		t.Errorf("expected data file %q: %v", DataFilenameFor(codec), err)
+	}
+
+	// A gzip backup in the same store must still be readable
+	gzipFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+	if err := os.WriteFile(gzipFile, []byte("gzip compressed snapshot"), 0600); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+	gzipName, err := store.AddBackup(gzipFile, "000002", &etcd.BackupInfo{EtcdVersion: "3.5.0"})
+	if err != nil {
+		t.Fatalf("AddBackup failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, gzipName, DataFilename)); err != nil {
+		t.Errorf("expected gzip data file: %v", err)
+	}
+
+	for _, backupName := range []string{name, gzipName} {
+		if err := store.VerifyBackup(backupName); err != nil {
+			t.Errorf("VerifyBackup(%q) failed: %v", backupName, err)
+		}
+	}
+
+	destFile := filepath.Join(t.TempDir(), "snapshot.db.zst")
+	if err := store.DownloadBackup(name, destFile); err != nil {
+		t.Fatalf("DownloadBackup failed: %v", err)
+	}
+	downloaded, err := os.ReadFile(destFile)
+	if err != nil {
+		t.Fatalf("failed to read downloaded backup: %v", err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Errorf("downloaded backup did not match original")
+	}
+}
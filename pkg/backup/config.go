@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// ConfigStore is implemented by stores that hold configuration alongside the backups, such as the retention policy,
+// so that every process using the store applies the same configuration.
+type ConfigStore interface {
+	// ReadConfig reads a configuration file from the root of the store, returning an error wrapping os.ErrNotExist if it does not exist
+	ReadConfig(name string) ([]byte, error)
+}
+
+var _ ConfigStore = &vfsStore{}
+var _ ConfigStore = &replicatedStore{}
+
+// validateConfigName checks that a configuration file name is a plain _etcd file name in the root of the store
+func validateConfigName(name string) error {
+	if name != path.Base(name) || !strings.HasPrefix(name, "_etcd") {
+		return fmt.Errorf("invalid configuration file name %q", name)
+	}
+	return nil
+}
+
+func (s *vfsStore) ReadConfig(name string) ([]byte, error) {
+	if err := validateConfigName(name); err != nil {
+		return nil, err
+	}
+
+	p := s.backupsBase.Join(name)
+	data, err := p.ReadFile(context.TODO())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("file %q not found: %w", p, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("error reading file %q: %v", p, err)
+	}
+	return data, nil
+}
+
+// ReadConfig returns the configuration from the first store that holds it
+func (s *replicatedStore) ReadConfig(name string) ([]byte, error) {
+	var errs []error
+	for _, store := range s.stores {
+		configStore, ok := store.(ConfigStore)
+		if !ok {
+			continue
+		}
+		data, err := configStore.ReadConfig(name)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			klog.Warningf("error reading %q from backup store %s: %v", name, store.Spec(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+		}
+	}
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("error reading %q: %w", name, errors.Join(errs...))
+	}
+	return nil, fmt.Errorf("%q not found in any backup store: %w", name, os.ErrNotExist)
+}
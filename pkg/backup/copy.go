@@ -0,0 +1,269 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// BackupSequence returns the sequence part of a backup name, which follows the timestamp recorded in info
+func BackupSequence(name string, info *etcd.BackupInfo) (string, error) {
+	prefix := time.Unix(info.Timestamp, 0).UTC().Format(time.RFC3339) + "-"
+	if info.Timestamp == 0 || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+		return "", fmt.Errorf("backup name %q does not match the backup timestamp %d", name, info.Timestamp)
+	}
+	return strings.TrimPrefix(name, prefix), nil
+}
+
+// CopyBackup copies a backup from src to dest, under the same name, along with its pin, verification and anomaly flag.
+// The data is decrypted and verified on download from src, and re-encrypted with dest's key (if any) on upload.
+// The cluster state is copied only if stateKey is set, because it cannot be read without it.
+func CopyBackup(src Store, dest Store, name string, stateKey *EncryptionKey) error {
+	info, err := src.LoadInfo(name)
+	if err != nil {
+		return fmt.Errorf("error loading info for backup %q: %w", name, err)
+	}
+	sequence, err := BackupSequence(name, info)
+	if err != nil {
+		return err
+	}
+	codec, err := CodecFor(info.Compression)
+	if err != nil {
+		return err
+	}
+
+	tempDir, err := os.MkdirTemp("", "etcd-backup-copy")
+	if err != nil {
+		return fmt.Errorf("error creating temp directory: %v", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			klog.Warningf("error removing temp directory %q: %v", tempDir, err)
+		}
+	}()
+
+	dataFile := filepath.Join(tempDir, DataFilenameFor(codec))
+	if err := src.DownloadBackup(name, dataFile); err != nil {
+		return fmt.Errorf("error downloading backup %q: %w", name, err)
+	}
+
+	// AddBackup records the checksum and size of the data as stored in dest
+	destInfo := proto.Clone(info).(*etcd.BackupInfo)
+	destName, err := dest.AddBackup(dataFile, sequence, destInfo)
+	if err != nil {
+		return fmt.Errorf("error adding backup %q to %s: %w", name, dest.Spec(), err)
+	}
+	if destName != name {
+		return fmt.Errorf("backup %q was copied as %q", name, destName)
+	}
+
+	return CopyBackupExtras(src, dest, name, stateKey)
+}
+
+// CopiedBackup returns true if the backup in dest is a complete, intact copy of the backup data in src.
+// The pin, verification, anomaly flag and cluster state may still need to be copied with CopyBackupExtras.
+func CopiedBackup(src Store, dest Store, name string) bool {
+	srcInfo, err := src.LoadInfo(name)
+	if err != nil {
+		return false
+	}
+	destInfo, err := dest.LoadInfo(name)
+	if err != nil || !SameBackup(srcInfo, destInfo) {
+		return false
+	}
+	if err := dest.VerifyBackup(name); err != nil {
+		klog.Warningf("existing copy of backup %q failed verification, copying again: %v", name, err)
+		return false
+	}
+	return true
+}
+
+// CopyBackupExtras copies the pin, verification, anomaly flag and (if stateKey is set) cluster state of a backup
+// from src to dest, where the backup itself must already have been copied.
+// Anything src does not support is skipped; anything src holds that dest cannot record is an error.
+func CopyBackupExtras(src Store, dest Store, name string, stateKey *EncryptionKey) error {
+	if srcPins, ok := src.(PinStore); ok {
+		pin, err := srcPins.LoadPin(name)
+		if err != nil {
+			return fmt.Errorf("error loading pin for backup %q: %w", name, err)
+		}
+		if pin != nil {
+			destPins, ok := dest.(PinStore)
+			if !ok {
+				return fmt.Errorf("backup %q is pinned, but backup store %s does not support pinning backups", name, dest.Spec())
+			}
+			if err := destPins.PinBackup(name, pin); err != nil {
+				return fmt.Errorf("error copying pin for backup %q: %w", name, err)
+			}
+		}
+	}
+
+	if srcVerifications, ok := src.(VerificationStore); ok {
+		verification, err := srcVerifications.LoadVerification(name)
+		if err != nil {
+			return fmt.Errorf("error loading verification for backup %q: %w", name, err)
+		}
+		if verification != nil {
+			destVerifications, ok := dest.(VerificationStore)
+			if !ok {
+				return fmt.Errorf("backup %q has been drilled, but backup store %s does not support recording restore drills", name, dest.Spec())
+			}
+			if err := destVerifications.SaveVerification(name, verification); err != nil {
+				return fmt.Errorf("error copying verification for backup %q: %w", name, err)
+			}
+		}
+	}
+
+	if srcAnomalies, ok := src.(AnomalyStore); ok {
+		anomaly, err := srcAnomalies.LoadAnomaly(name)
+		if err != nil {
+			return fmt.Errorf("error loading anomaly for backup %q: %w", name, err)
+		}
+		if anomaly != nil {
+			destAnomalies, ok := dest.(AnomalyStore)
+			if !ok {
+				return fmt.Errorf("backup %q is flagged as anomalous, but backup store %s does not support flagging anomalies", name, dest.Spec())
+			}
+			if err := destAnomalies.FlagAnomaly(name, anomaly); err != nil {
+				return fmt.Errorf("error copying anomaly for backup %q: %w", name, err)
+			}
+		}
+	}
+
+	if srcStates, ok := src.(StateStore); ok && stateKey != nil {
+		state, err := srcStates.LoadState(name, stateKey)
+		if err != nil {
+			return fmt.Errorf("error loading cluster state for backup %q: %w", name, err)
+		}
+		if state != nil {
+			destStates, ok := dest.(StateStore)
+			if !ok {
+				return fmt.Errorf("backup %q has an archived cluster state, but backup store %s does not support archiving the cluster state", name, dest.Spec())
+			}
+			if err := destStates.SaveState(name, state, stateKey); err != nil {
+				return fmt.Errorf("error copying cluster state for backup %q: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CopyRevisionSegments copies the archived revision segments for an etcd cluster from src to dest,
+// skipping segments that dest already holds, and returns the number copied.
+// The data is decrypted and verified on read from src, and re-encrypted with dest's key (if any) on upload.
+func CopyRevisionSegments(src Store, dest Store, clusterID string) (int, error) {
+	srcArchive, ok := src.(RevisionArchive)
+	if !ok {
+		return 0, nil
+	}
+	names, err := srcArchive.ListRevisionSegments(clusterID)
+	if err != nil {
+		return 0, fmt.Errorf("error listing revision segments for etcd cluster %q: %w", clusterID, err)
+	}
+	if len(names) == 0 {
+		return 0, nil
+	}
+
+	destArchive, ok := dest.(RevisionArchive)
+	if !ok {
+		return 0, fmt.Errorf("%s holds archived revisions, but backup store %s does not support archiving revisions", src.Spec(), dest.Spec())
+	}
+	existing, err := destArchive.ListRevisionSegments(clusterID)
+	if err != nil {
+		return 0, fmt.Errorf("error listing revision segments for etcd cluster %q in %s: %w", clusterID, dest.Spec(), err)
+	}
+	present := make(map[string]bool)
+	for _, name := range existing {
+		present[name] = true
+	}
+
+	copied := 0
+	for _, name := range names {
+		if present[name] {
+			continue
+		}
+		info, data, err := srcArchive.ReadRevisionSegment(clusterID, name)
+		if err != nil {
+			return copied, fmt.Errorf("error reading revision segment %q: %w", name, err)
+		}
+		// AddRevisionSegment records the checksum and size of the data as stored in dest
+		destInfo := proto.Clone(info).(*etcd.RevisionSegmentInfo)
+		if _, err := destArchive.AddRevisionSegment(destInfo, data); err != nil {
+			return copied, fmt.Errorf("error adding revision segment %q to %s: %w", name, dest.Spec(), err)
+		}
+		copied++
+	}
+	return copied, nil
+}
+
+// SameBackup returns true if a and b describe the same backup, as taken, ignoring how it is stored
+func SameBackup(a, b *etcd.BackupInfo) bool {
+	return a.Timestamp == b.Timestamp &&
+		a.EtcdVersion == b.EtcdVersion &&
+		a.EtcdClusterId == b.EtcdClusterId &&
+		a.Revision == b.Revision
+}
+
+// CheckSameCluster returns an error if dest holds backups that do not belong to the cluster backed up in src:
+// a backup with the same name as one in src but different contents, or a backup from an etcd cluster that
+// none of the backups in src come from.  Backups that predate the recording of the etcd cluster ID are not compared by ID.
+func CheckSameCluster(src Store, dest Store) error {
+	srcNames, err := src.ListBackups()
+	if err != nil {
+		return fmt.Errorf("error listing backups in %s: %w", src.Spec(), err)
+	}
+	srcInfos := make(map[string]*etcd.BackupInfo)
+	clusterIDs := make(map[string]bool)
+	for _, name := range srcNames {
+		info, err := src.LoadInfo(name)
+		if err != nil {
+			return fmt.Errorf("error loading info for backup %q: %w", name, err)
+		}
+		srcInfos[name] = info
+		clusterIDs[info.EtcdClusterId] = true
+	}
+
+	destNames, err := dest.ListBackups()
+	if err != nil {
+		return fmt.Errorf("error listing backups in %s: %w", dest.Spec(), err)
+	}
+	for _, name := range destNames {
+		info, err := dest.LoadInfo(name)
+		if err != nil {
+			return fmt.Errorf("error loading info for backup %q in %s: %w", name, dest.Spec(), err)
+		}
+		if srcInfo := srcInfos[name]; srcInfo != nil {
+			if !SameBackup(srcInfo, info) {
+				return fmt.Errorf("backup %q in %s differs from the backup of the same name in %s", name, dest.Spec(), src.Spec())
+			}
+			continue
+		}
+		if info.EtcdClusterId != "" && !clusterIDs[info.EtcdClusterId] {
+			return fmt.Errorf("backup %q in %s is from etcd cluster %s, which has no backups in %s", name, dest.Spec(), info.EtcdClusterId, src.Spec())
+		}
+	}
+	return nil
+}
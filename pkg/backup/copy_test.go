@@ -0,0 +1,255 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func TestBackupSequence(t *testing.T) {
+	info := &etcd.BackupInfo{Timestamp: 1700000000}
+	sequence, err := BackupSequence("2023-11-14T22:13:20Z-000042", info)
+	if err != nil {
+		t.Fatalf("BackupSequence failed: %v", err)
+	}
+	if sequence != "000042" {
+		t.Errorf("BackupSequence returned %q, expected %q", sequence, "000042")
+	}
+
+	for _, name := range []string{"2023-11-14T22:13:21Z-000042", "2023-11-14T22:13:20Z-", "backup"} {
+		if _, err := BackupSequence(name, info); err == nil {
+			t.Errorf("expected an error for backup name %q", name)
+		}
+	}
+}
+
+func TestCopyBackup(t *testing.T) {
+	srcKey := newTestEncryptionKey(t)
+	s, _ := newTestVFSStore(t, srcKey)
+	src := s.(*vfsStore)
+	d, destDir := newTestVFSStore(t, nil)
+	dest := d.(*vfsStore)
+	stateKey := newTestEncryptionKey(t)
+
+	data := bytes.Repeat([]byte("etcd"), 1000)
+	name := addTestBackup(t, src, data)
+
+	pin := &etcd.BackupPin{Timestamp: 1700000000, Reason: "before migration"}
+	if err := src.PinBackup(name, pin); err != nil {
+		t.Fatalf("PinBackup failed: %v", err)
+	}
+	anomaly := &etcd.BackupAnomaly{Timestamp: 1700000000, Reason: "data size 10 is 1% of the baseline 1000"}
+	if err := src.FlagAnomaly(name, anomaly); err != nil {
+		t.Fatalf("FlagAnomaly failed: %v", err)
+	}
+	state := &etcd.BackupState{Timestamp: 1700000000, ClusterSpec: &etcd.ClusterSpec{MemberCount: 3, EtcdVersion: "3.5.9"}}
+	if err := src.SaveState(name, state, stateKey); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	if err := CopyBackup(src, dest, name, stateKey); err != nil {
+		t.Fatalf("CopyBackup failed: %v", err)
+	}
+
+	// The source was encrypted, the destination is not, so we can read the data directly
+	stored, err := os.ReadFile(filepath.Join(destDir, name, DataFilename))
+	if err != nil {
+		t.Fatalf("failed to read copied data: %v", err)
+	}
+	if !bytes.Equal(stored, data) {
+		t.Errorf("copied data did not match the original")
+	}
+	if err := dest.VerifyBackup(name); err != nil {
+		t.Errorf("copied backup failed verification: %v", err)
+	}
+
+	srcInfo, err := src.LoadInfo(name)
+	if err != nil {
+		t.Fatalf("LoadInfo failed: %v", err)
+	}
+	destInfo, err := dest.LoadInfo(name)
+	if err != nil {
+		t.Fatalf("LoadInfo failed: %v", err)
+	}
+	if destInfo.EtcdVersion != srcInfo.EtcdVersion || destInfo.Timestamp != srcInfo.Timestamp {
+		t.Errorf("copied info %v did not match the original %v", destInfo, srcInfo)
+	}
+	if destInfo.DataSize != int64(len(data)) {
+		t.Errorf("copied info has data size %d, expected %d", destInfo.DataSize, len(data))
+	}
+
+	if loaded, err := dest.LoadPin(name); err != nil || !proto.Equal(loaded, pin) {
+		t.Errorf("copied pin was %v (err=%v), expected %v", loaded, err, pin)
+	}
+	if loaded, err := dest.LoadAnomaly(name); err != nil || !proto.Equal(loaded, anomaly) {
+		t.Errorf("copied anomaly was %v (err=%v), expected %v", loaded, err, anomaly)
+	}
+	if loaded, err := dest.LoadState(name, stateKey); err != nil || !proto.Equal(loaded, state) {
+		t.Errorf("copied state was %v (err=%v), expected %v", loaded, err, state)
+	}
+	if loaded, err := dest.LoadVerification(name); err != nil || loaded != nil {
+		t.Errorf("expected no verification, got %v (err=%v)", loaded, err)
+	}
+}
+
+func TestCopyBackupWithoutStateKey(t *testing.T) {
+	src, _ := newTestVFSStore(t, nil)
+	dest, destDir := newTestVFSStore(t, nil)
+	stateKey := newTestEncryptionKey(t)
+
+	name := addTestBackup(t, src, []byte("backup data"))
+	state := &etcd.BackupState{Timestamp: 1700000000}
+	if err := src.(StateStore).SaveState(name, state, stateKey); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+
+	if err := CopyBackup(src, dest, name, nil); err != nil {
+		t.Fatalf("CopyBackup failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(destDir, name, StateFilename)); !os.IsNotExist(err) {
+		t.Errorf("expected the cluster state not to be copied without a key (err=%v)", err)
+	}
+}
+
+func TestCopiedBackup(t *testing.T) {
+	src, _ := newTestVFSStore(t, nil)
+	dest, destDir := newTestVFSStore(t, nil)
+
+	name := addTestBackup(t, src, []byte("backup data"))
+	if CopiedBackup(src, dest, name) {
+		t.Errorf("expected a missing backup not to be reported as copied")
+	}
+
+	if err := CopyBackup(src, dest, name, nil); err != nil {
+		t.Fatalf("CopyBackup failed: %v", err)
+	}
+	if !CopiedBackup(src, dest, name) {
+		t.Errorf("expected the backup to be reported as copied")
+	}
+
+	// A copy that no longer verifies must be copied again
+	files, err := filepath.Glob(filepath.Join(destDir, name, "etcd.backup*"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("expected one data file, got %v (err=%v)", files, err)
+	}
+	if err := os.WriteFile(files[0], []byte("corrupted"), 0600); err != nil {
+		t.Fatalf("failed to corrupt backup: %v", err)
+	}
+	if CopiedBackup(src, dest, name) {
+		t.Errorf("expected a corrupted copy not to be reported as copied")
+	}
+}
+
+func TestCopyRevisionSegments(t *testing.T) {
+	src, _ := newTestVFSStore(t, newTestEncryptionKey(t))
+	dest, _ := newTestVFSStore(t, nil)
+	srcArchive := src.(RevisionArchive)
+	destArchive := dest.(RevisionArchive)
+
+	codec := src.Codec()
+	data, err := EncodeRevisionSegment(codec, testRevisions())
+	if err != nil {
+		t.Fatalf("EncodeRevisionSegment failed: %v", err)
+	}
+	var names []string
+	for _, r := range [][2]int64{{11, 20}, {21, 30}} {
+		name, err := srcArchive.AddRevisionSegment(&etcd.RevisionSegmentInfo{
+			EtcdClusterId: "cdf818194e3a8c32",
+			StartRevision: r[0],
+			EndRevision:   r[1],
+			Compression:   codec.Name(),
+		}, data)
+		if err != nil {
+			t.Fatalf("AddRevisionSegment failed: %v", err)
+		}
+		names = append(names, name)
+	}
+
+	copied, err := CopyRevisionSegments(src, dest, "cdf818194e3a8c32")
+	if err != nil || copied != 2 {
+		t.Fatalf("expected 2 segments to be copied, got %d (err=%v)", copied, err)
+	}
+	segments, err := destArchive.ListRevisionSegments("cdf818194e3a8c32")
+	if err != nil || !reflect.DeepEqual(segments, names) {
+		t.Errorf("expected segments %v in the destination, got %v (err=%v)", names, segments, err)
+	}
+	if _, copiedData, err := destArchive.ReadRevisionSegment("cdf818194e3a8c32", names[0]); err != nil || !bytes.Equal(copiedData, data) {
+		t.Errorf("copied segment does not match (err=%v)", err)
+	}
+
+	// Segments already in the destination are skipped
+	if copied, err := CopyRevisionSegments(src, dest, "cdf818194e3a8c32"); err != nil || copied != 0 {
+		t.Errorf("expected no segments to be copied again, got %d (err=%v)", copied, err)
+	}
+}
+
+func addTestBackupFromCluster(t *testing.T, store Store, timestamp int64, clusterID string) string {
+	srcFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+	if err := os.WriteFile(srcFile, []byte("backup data"), 0600); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+	info := &etcd.BackupInfo{EtcdVersion: "3.5.9", Timestamp: timestamp, EtcdClusterId: clusterID, Revision: timestamp}
+	name, err := store.AddBackup(srcFile, "000001", info)
+	if err != nil {
+		t.Fatalf("AddBackup failed: %v", err)
+	}
+	return name
+}
+
+func TestCheckSameCluster(t *testing.T) {
+	src, _ := newTestVFSStore(t, nil)
+	first := addTestBackupFromCluster(t, src, 1700000000, "aaaa")
+	addTestBackupFromCluster(t, src, 1700003600, "aaaa")
+
+	dest, _ := newTestVFSStore(t, nil)
+	if err := CheckSameCluster(src, dest); err != nil {
+		t.Errorf("expected an empty destination to be accepted: %v", err)
+	}
+
+	// A partial migration
+	if err := CopyBackup(src, dest, first, nil); err != nil {
+		t.Fatalf("CopyBackup failed: %v", err)
+	}
+	if err := CheckSameCluster(src, dest); err != nil {
+		t.Errorf("expected a partial copy to be accepted: %v", err)
+	}
+
+	// A backup since pruned from the source, from the same etcd cluster
+	addTestBackupFromCluster(t, dest, 1600000000, "aaaa")
+	if err := CheckSameCluster(src, dest); err != nil {
+		t.Errorf("expected an older backup of the same cluster to be accepted: %v", err)
+	}
+
+	other, _ := newTestVFSStore(t, nil)
+	addTestBackupFromCluster(t, other, 1600000000, "bbbb")
+	if err := CheckSameCluster(src, other); err == nil {
+		t.Errorf("expected a backup from another etcd cluster to be refused")
+	}
+
+	clash, _ := newTestVFSStore(t, nil)
+	addTestBackupFromCluster(t, clash, 1700000000, "")
+	if err := CheckSameCluster(src, clash); err == nil {
+		t.Errorf("expected a different backup with the same name to be refused")
+	}
+}
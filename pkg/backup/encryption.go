@@ -0,0 +1,289 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// EncryptionKeyFileEnv is the environment variable naming a local file holding the backup encryption key.
+// When set, backups are encrypted before they are uploaded to the backup store.
+const EncryptionKeyFileEnv = "ETCD_MANAGER_BACKUP_ENCRYPTION_KEY_FILE"
+
+const (
+	// EncryptionAlgorithmAES256GCMStream encrypts the data file in fixed-size chunks,
+	// each sealed with AES-256-GCM, so that backups can be encrypted and decrypted without holding them in memory.
+	EncryptionAlgorithmAES256GCMStream = "aes-256-gcm-stream"
+
+	// KeyWrapAlgorithmAES256GCM seals the per-backup data key with AES-256-GCM under the key-encryption key.
+	KeyWrapAlgorithmAES256GCM = "aes-256-gcm"
+)
+
+// encryptionChunkSize is the size of each plaintext chunk sealed by the stream encryption
+const encryptionChunkSize = 64 * 1024
+
+// streamNoncePrefixSize is the length of the random nonce prefix; the remaining 5 bytes of the
+// 12 byte GCM nonce hold a 4 byte chunk counter and a final-chunk flag.
+const streamNoncePrefixSize = 7
+
+// EncryptionKey is a key-encryption key, used to wrap the random data key generated for each backup
+type EncryptionKey struct {
+	key []byte
+}
+
+// ID returns a short fingerprint of the key, recorded with each backup so a wrong key can be reported clearly
+func (k *EncryptionKey) ID() string {
+	h := sha256.Sum256(k.key)
+	return hex.EncodeToString(h[:8])
+}
+
+// LoadEncryptionKey reads a 32 byte AES-256 key from a file; the key may be stored raw or base64 encoded.
+func LoadEncryptionKey(p string) (*EncryptionKey, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading encryption key file %q: %w", p, err)
+	}
+
+	if len(b) == 32 {
+		return &EncryptionKey{key: b}, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return nil, fmt.Errorf("encryption key file %q must contain 32 raw bytes or base64 encoded bytes: %w", p, err)
+	}
+	if len(decoded) != 32 {
+		return nil, fmt.Errorf("encryption key in %q must be 32 bytes, was %d bytes", p, len(decoded))
+	}
+	return &EncryptionKey{key: decoded}, nil
+}
+
+// LoadEncryptionKeyFromEnv loads the key named by ETCD_MANAGER_BACKUP_ENCRYPTION_KEY_FILE, returning nil if it is not set.
+func LoadEncryptionKeyFromEnv() (*EncryptionKey, error) {
+	p := os.Getenv(EncryptionKeyFileEnv)
+	if p == "" {
+		return nil, nil
+	}
+	return LoadEncryptionKey(p)
+}
+
+// encryptionInfo is persisted as EncryptionFilename alongside an encrypted backup
+type encryptionInfo struct {
+	Algorithm        string `json:"algorithm"`
+	KeyWrapAlgorithm string `json:"keyWrapAlgorithm"`
+	KeyID            string `json:"keyID"`
+	WrappedKey       []byte `json:"wrappedKey"`
+	NoncePrefix      []byte `json:"noncePrefix"`
+	ChunkSize        int    `json:"chunkSize"`
+}
+
+// newEncryptionInfo generates a random data key for a backup, returning it along with the info needed to recover it.
+func newEncryptionInfo(kek *EncryptionKey) (*encryptionInfo, []byte, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, fmt.Errorf("error generating data key: %w", err)
+	}
+
+	noncePrefix := make([]byte, streamNoncePrefixSize)
+	if _, err := io.ReadFull(rand.Reader, noncePrefix); err != nil {
+		return nil, nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	aead, err := newGCM(kek.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	info := &encryptionInfo{
+		Algorithm:        EncryptionAlgorithmAES256GCMStream,
+		KeyWrapAlgorithm: KeyWrapAlgorithmAES256GCM,
+		KeyID:            kek.ID(),
+		WrappedKey:       aead.Seal(nonce, nonce, dataKey, nil),
+		NoncePrefix:      noncePrefix,
+		ChunkSize:        encryptionChunkSize,
+	}
+	return info, dataKey, nil
+}
+
+// unwrapDataKey recovers the data key for a backup, using the key-encryption key
+func (i *encryptionInfo) unwrapDataKey(kek *EncryptionKey) ([]byte, error) {
+	if i.Algorithm != EncryptionAlgorithmAES256GCMStream {
+		return nil, fmt.Errorf("unsupported backup encryption algorithm %q", i.Algorithm)
+	}
+	if i.KeyWrapAlgorithm != KeyWrapAlgorithmAES256GCM {
+		return nil, fmt.Errorf("unsupported backup key wrap algorithm %q", i.KeyWrapAlgorithm)
+	}
+	if kek == nil {
+		return nil, fmt.Errorf("backup is encrypted (key %s) but no encryption key is configured (set %s)", i.KeyID, EncryptionKeyFileEnv)
+	}
+	if i.KeyID != kek.ID() {
+		return nil, fmt.Errorf("backup was encrypted with key %s, but the configured key is %s", i.KeyID, kek.ID())
+	}
+
+	aead, err := newGCM(kek.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(i.WrappedKey) < aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped data key is too short")
+	}
+	nonce := i.WrappedKey[:aead.NonceSize()]
+	dataKey, err := aead.Open(nil, nonce, i.WrappedKey[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+func (i *encryptionInfo) toJSON() ([]byte, error) {
+	return json.MarshalIndent(i, "", "  ")
+}
+
+func parseEncryptionInfo(data []byte) (*encryptionInfo, error) {
+	i := &encryptionInfo{}
+	if err := json.Unmarshal(data, i); err != nil {
+		return nil, err
+	}
+	if i.ChunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size %d", i.ChunkSize)
+	}
+	if len(i.NoncePrefix) != streamNoncePrefixSize {
+		return nil, fmt.Errorf("invalid nonce prefix length %d", len(i.NoncePrefix))
+	}
+	return i, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error building cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error building GCM: %w", err)
+	}
+	return aead, nil
+}
+
+// streamCipher is an io.WriteCloser that seals (or opens) a stream in fixed-size chunks.
+// Each chunk nonce carries a counter and a final-chunk flag, so reordered or truncated data is detected.
+// Because we only know a chunk is the last one once the stream is closed, we hold back the most recent chunk.
+type streamCipher struct {
+	out         io.Writer
+	aead        cipher.AEAD
+	noncePrefix []byte
+	decrypt     bool
+
+	// inputChunkSize is the size of each chunk we consume: plaintext when encrypting, ciphertext when decrypting
+	inputChunkSize int
+
+	buf     []byte
+	counter uint32
+}
+
+func newStreamCipher(out io.Writer, dataKey []byte, info *encryptionInfo, decrypt bool) (*streamCipher, error) {
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	s := &streamCipher{
+		out:            out,
+		aead:           aead,
+		noncePrefix:    info.NoncePrefix,
+		decrypt:        decrypt,
+		inputChunkSize: info.ChunkSize,
+	}
+	if decrypt {
+		s.inputChunkSize += aead.Overhead()
+	}
+	return s, nil
+}
+
+// newEncryptingWriter returns a writer that encrypts everything written to it into out
+func newEncryptingWriter(out io.Writer, dataKey []byte, info *encryptionInfo) (io.WriteCloser, error) {
+	return newStreamCipher(out, dataKey, info, false)
+}
+
+// newDecryptingWriter returns a writer that decrypts everything written to it into out
+func newDecryptingWriter(out io.Writer, dataKey []byte, info *encryptionInfo) (io.WriteCloser, error) {
+	return newStreamCipher(out, dataKey, info, true)
+}
+
+func (s *streamCipher) Write(p []byte) (int, error) {
+	s.buf = append(s.buf, p...)
+	for len(s.buf) > s.inputChunkSize {
+		if err := s.processChunk(s.buf[:s.inputChunkSize], false); err != nil {
+			return 0, err
+		}
+		s.buf = s.buf[s.inputChunkSize:]
+	}
+	return len(p), nil
+}
+
+// Close processes the final chunk; when decrypting, this is what detects a truncated stream.
+func (s *streamCipher) Close() error {
+	err := s.processChunk(s.buf, true)
+	s.buf = nil
+	return err
+}
+
+func (s *streamCipher) processChunk(chunk []byte, last bool) error {
+	nonce := make([]byte, 0, s.aead.NonceSize())
+	nonce = append(nonce, s.noncePrefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, s.counter)
+	if last {
+		nonce = append(nonce, 1)
+	} else {
+		nonce = append(nonce, 0)
+	}
+
+	if s.counter == ^uint32(0) {
+		return fmt.Errorf("encrypted stream is too long")
+	}
+	s.counter++
+
+	var out []byte
+	if s.decrypt {
+		plaintext, err := s.aead.Open(nil, nonce, chunk, nil)
+		if err != nil {
+			return fmt.Errorf("%w: error decrypting backup (data corrupted or truncated?): %v", ErrIntegrity, err)
+		}
+		out = plaintext
+	} else {
+		out = s.aead.Seal(nil, nonce, chunk, nil)
+	}
+
+	if _, err := s.out.Write(out); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func newTestEncryptionKey(t *testing.T) *EncryptionKey {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return &EncryptionKey{key: key}
+}
+
+func TestStreamCipherRoundTrip(t *testing.T) {
+	kek := newTestEncryptionKey(t)
+
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3*encryptionChunkSize + 17} {
+		plaintext := make([]byte, size)
+		if _, err := rand.Read(plaintext); err != nil {
+			t.Fatalf("failed to generate plaintext: %v", err)
+		}
+
+		info, dataKey, err := newEncryptionInfo(kek)
+		if err != nil {
+			t.Fatalf("newEncryptionInfo failed: %v", err)
+		}
+
+		var ciphertext bytes.Buffer
+		w, err := newEncryptingWriter(&ciphertext, dataKey, info)
+		if err != nil {
+			t.Fatalf("newEncryptingWriter failed: %v", err)
+		}
+		// Write in uneven pieces, to exercise the chunk buffering
+		for i := 0; i < len(plaintext); i += 1000 {
+			end := min(i+1000, len(plaintext))
+			if _, err := w.Write(plaintext[i:end]); err != nil {
+				t.Fatalf("encrypt write failed: %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("encrypt close failed: %v", err)
+		}
+
+		unwrapped, err := info.unwrapDataKey(kek)
+		if err != nil {
+			t.Fatalf("unwrapDataKey failed: %v", err)
+		}
+
+		var decrypted bytes.Buffer
+		r, err := newDecryptingWriter(&decrypted, unwrapped, info)
+		if err != nil {
+			t.Fatalf("newDecryptingWriter failed: %v", err)
+		}
+		if _, err := r.Write(ciphertext.Bytes()); err != nil {
+			t.Fatalf("decrypt write failed: %v", err)
+		}
+		if err := r.Close(); err != nil {
+			t.Fatalf("decrypt close failed: %v", err)
+		}
+
+		if !bytes.Equal(plaintext, decrypted.Bytes()) {
+			t.Errorf("size %d: decrypted data did not match plaintext", size)
+		}
+
+		// Truncating at a chunk boundary must be detected
+		if size > encryptionChunkSize {
+			truncated := ciphertext.Bytes()[:encryptionChunkSize+16]
+			r, err := newDecryptingWriter(&bytes.Buffer{}, unwrapped, info)
+			if err != nil {
+				t.Fatalf("newDecryptingWriter failed: %v", err)
+			}
+			_, err = r.Write(truncated)
+			if err == nil {
+				err = r.Close()
+			}
+			if err == nil {
+				t.Errorf("size %d: expected error decrypting truncated data", size)
+			}
+		}
+	}
+}
+
+func TestUnwrapDataKeyWrongKey(t *testing.T) {
+	info, _, err := newEncryptionInfo(newTestEncryptionKey(t))
+	if err != nil {
+		t.Fatalf("newEncryptionInfo failed: %v", err)
+	}
+
+	if _, err := info.unwrapDataKey(newTestEncryptionKey(t)); err == nil || !strings.Contains(err.Error(), "was encrypted with key") {
+		t.Errorf("expected wrong key error, got %v", err)
+	}
+	if _, err := info.unwrapDataKey(nil); err == nil || !strings.Contains(err.Error(), EncryptionKeyFileEnv) {
+		t.Errorf("expected missing key error, got %v", err)
+	}
+}
+
+func TestLoadEncryptionKey(t *testing.T) {
+	dir := t.TempDir()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	raw := filepath.Join(dir, "raw")
+	if err := os.WriteFile(raw, key, 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	encoded := filepath.Join(dir, "encoded")
+	if err := os.WriteFile(encoded, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	short := filepath.Join(dir, "short")
+	if err := os.WriteFile(short, []byte(base64.StdEncoding.EncodeToString(key[:16])), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	for _, p := range []string{raw, encoded} {
+		k, err := LoadEncryptionKey(p)
+		if err != nil {
+			t.Fatalf("LoadEncryptionKey(%q) failed: %v", p, err)
+		}
+		if !bytes.Equal(k.key, key) {
+			t.Errorf("LoadEncryptionKey(%q) returned the wrong key", p)
+		}
+	}
+
+	if _, err := LoadEncryptionKey(short); err == nil {
+		t.Errorf("expected error loading short key")
+	}
+}
+
+func TestEncryptedVFSStore(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := vfs.Context.BuildVfsPath(filepath.Join(dir, "backups"))
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+
+	kek := newTestEncryptionKey(t)
+	store, err := NewVFSStore(p, nil, kek)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+
+	plaintext := []byte(strings.Repeat("secret data ", 10000))
+	srcFile := filepath.Join(dir, "snapshot.db.gz")
+	if err := os.WriteFile(srcFile, plaintext, 0600); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+
+	name, err := store.AddBackup(srcFile, "000001", &etcd.BackupInfo{EtcdVersion: "3.5.0"})
+	if err != nil {
+		t.Fatalf("AddBackup failed: %v", err)
+	}
+
+	stored, err := os.ReadFile(filepath.Join(dir, "backups", name, DataFilename))
+	if err != nil {
+		t.Fatalf("failed to read stored backup: %v", err)
+	}
+	if bytes.Contains(stored, []byte("secret data")) {
+		t.Errorf("stored backup was not encrypted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "backups", name, EncryptionFilename)); err != nil {
+		t.Errorf("expected encryption info to be written: %v", err)
+	}
+
+	destFile := filepath.Join(dir, "download", "snapshot.db.gz")
+	if err := store.DownloadBackup(name, destFile); err != nil {
+		t.Fatalf("DownloadBackup failed: %v", err)
+	}
+	downloaded, err := os.ReadFile(destFile)
+	if err != nil {
+		t.Fatalf("failed to read downloaded backup: %v", err)
+	}
+	if !bytes.Equal(downloaded, plaintext) {
+		t.Errorf("downloaded backup did not match original")
+	}
+
+	unkeyed, err := NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+	if err := unkeyed.DownloadBackup(name, destFile); err == nil {
+		t.Errorf("expected error downloading encrypted backup without a key")
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"google.golang.org/api/googleapi"
+	storage "google.golang.org/api/storage/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// streamPartSize is the size of the parts (S3) or chunks (GCS) in which streams are uploaded;
+// it bounds the memory used, and (with S3's limit of 10000 parts) the largest backup we can stream to S3.
+const streamPartSize = 16 * 1024 * 1024
+
+// s3MaxParts is the most parts S3 accepts in a multipart upload
+const s3MaxParts = 10000
+
+// streamUploader uploads the data read from r to an object
+type streamUploader func(ctx context.Context, r io.Reader) error
+
+// streamUploaderFor returns a streamUploader for the object at p, or nil if we cannot stream to it.
+// We can stream to GCS, and to AWS S3; other S3-compatible stores (with S3_ENDPOINT, or do://, scw:// and so on)
+// need client configuration that vfs does not expose, so they are written from a local file.
+func streamUploaderFor(p vfs.Path) streamUploader {
+	switch p := p.(type) {
+	case *vfs.S3Path:
+		if !strings.HasPrefix(p.Path(), "s3://") || os.Getenv("S3_ENDPOINT") != "" {
+			return nil
+		}
+		return func(ctx context.Context, r io.Reader) error {
+			client, err := newS3Client(ctx, p)
+			if err != nil {
+				return err
+			}
+			options := s3WriteOptionsFor(ctx, client, p.Bucket())
+			return uploadS3Multipart(ctx, client, p.Bucket(), p.Key(), options, r)
+		}
+
+	case *vfs.GSPath:
+		return func(ctx context.Context, r io.Reader) error {
+			client, err := p.Client(ctx)
+			if err != nil {
+				return fmt.Errorf("error building GCS client: %w", err)
+			}
+			// Media uploads from a reader that cannot seek are resumable uploads, sent one chunk at a time
+			obj := &storage.Object{Name: p.Object()}
+			if _, err := client.Objects.Insert(p.Bucket(), obj).Context(ctx).Media(r, googleapi.ChunkSize(streamPartSize)).Do(); err != nil {
+				return fmt.Errorf("error uploading %s: %w", p, err)
+			}
+			return nil
+		}
+
+	default:
+		return nil
+	}
+}
+
+// uploadStream uploads the data written by write to the object at p, without staging it in a local file
+func uploadStream(ctx context.Context, p vfs.Path, write func(w io.Writer) error) error {
+	upload := streamUploaderFor(p)
+	if upload == nil {
+		return fmt.Errorf("streaming uploads to %s are not supported", p)
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := upload(ctx, pr)
+		// If the upload stopped early, this fails the writer rather than leaving it blocked
+		_ = pr.CloseWithError(fmt.Errorf("upload to %s stopped", p))
+		done <- err
+	}()
+
+	err := write(pw)
+	// A nil error closes the pipe normally, which ends the upload
+	_ = pw.CloseWithError(err)
+
+	if uploadErr := <-done; uploadErr != nil {
+		return uploadErr
+	}
+	return err
+}
+
+// newS3Client builds an S3 client for the bucket of p, configured as vfs configures its clients for AWS S3
+func newS3Client(ctx context.Context, p *vfs.S3Path) (*s3.Client, error) {
+	region, err := p.Region(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error finding region of %s: %w", p, err)
+	}
+	config, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("error loading AWS config: %w", err)
+	}
+	return s3.NewFromConfig(config, func(o *s3.Options) {
+		o.EndpointResolverV2 = &vfs.ResolverV2{}
+	}), nil
+}
+
+// s3WriteOptions are the request parameters vfs sets when it writes an object to S3
+type s3WriteOptions struct {
+	ServerSideEncryption s3types.ServerSideEncryption
+	ACL                  s3types.ObjectCannedACL
+}
+
+// s3BucketEncryptionAPI is the part of the S3 client we use to find the default encryption of a bucket
+type s3BucketEncryptionAPI interface {
+	GetBucketEncryption(ctx context.Context, params *s3.GetBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error)
+}
+
+// s3WriteOptionsFor returns the options vfs uses to write to an s3:// bucket: AES256 server-side encryption
+// unless the bucket encrypts objects by default, and the canned ACL in KOPS_STATE_S3_ACL.
+func s3WriteOptionsFor(ctx context.Context, client s3BucketEncryptionAPI, bucket string) s3WriteOptions {
+	options := s3WriteOptions{
+		ServerSideEncryption: s3types.ServerSideEncryptionAes256,
+		ACL:                  s3types.ObjectCannedACL(strings.TrimSpace(os.Getenv("KOPS_STATE_S3_ACL"))),
+	}
+
+	// Like vfs, we encrypt with AES256 if we cannot read the bucket's policy
+	result, err := client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		klog.V(8).Infof("unable to read bucket encryption policy for %q: will encrypt using AES256: %v", bucket, err)
+		return options
+	}
+	if result.ServerSideEncryptionConfiguration != nil {
+		for _, rule := range result.ServerSideEncryptionConfiguration.Rules {
+			if rule.ApplyServerSideEncryptionByDefault != nil {
+				options.ServerSideEncryption = ""
+			}
+		}
+	}
+	return options
+}
+
+// s3MultipartAPI is the part of the S3 client we use for multipart uploads
+type s3MultipartAPI interface {
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// uploadS3Multipart uploads the data read from r to s3://bucket/key as a multipart upload, holding one part in memory at a time.
+// The object only appears once the upload is complete; a failed upload is aborted.
+func uploadS3Multipart(ctx context.Context, client s3MultipartAPI, bucket string, key string, options s3WriteOptions, r io.Reader) error {
+	created, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:               aws.String(bucket),
+		Key:                  aws.String(key),
+		ChecksumAlgorithm:    s3types.ChecksumAlgorithmCrc32,
+		ServerSideEncryption: options.ServerSideEncryption,
+		ACL:                  options.ACL,
+	})
+	if err != nil {
+		return fmt.Errorf("error starting upload to s3://%s/%s: %w", bucket, key, err)
+	}
+	uploadID := created.UploadId
+
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		// S3 keeps (and bills for) the parts of an upload until it is aborted
+		_, err := client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			UploadId: uploadID,
+		})
+		if err != nil {
+			klog.Warningf("error aborting upload to s3://%s/%s: %v", bucket, key, err)
+		}
+	}()
+
+	var parts []s3types.CompletedPart
+	buf := make([]byte, streamPartSize)
+	for partNumber := int32(1); ; partNumber++ {
+		n, err := io.ReadFull(r, buf)
+		if err == io.EOF && partNumber > 1 {
+			break
+		}
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return fmt.Errorf("error reading data for s3://%s/%s: %w", bucket, key, err)
+		}
+		if partNumber > s3MaxParts {
+			return fmt.Errorf("data for s3://%s/%s is larger than %d bytes, the most we can stream", bucket, key, s3MaxParts*streamPartSize)
+		}
+
+		uploaded, err := client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:            aws.String(bucket),
+			Key:               aws.String(key),
+			UploadId:          uploadID,
+			PartNumber:        aws.Int32(partNumber),
+			Body:              bytes.NewReader(buf[:n]),
+			ChecksumAlgorithm: s3types.ChecksumAlgorithmCrc32,
+		})
+		if err != nil {
+			return fmt.Errorf("error uploading part %d of s3://%s/%s: %w", partNumber, bucket, key, err)
+		}
+		parts = append(parts, s3types.CompletedPart{
+			ETag:          uploaded.ETag,
+			ChecksumCRC32: uploaded.ChecksumCRC32,
+			PartNumber:    aws.Int32(partNumber),
+		})
+
+		if n < streamPartSize {
+			break
+		}
+	}
+
+	_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        uploadID,
+		MultipartUpload: &s3types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return fmt.Errorf("error completing upload to s3://%s/%s: %w", bucket, key, err)
+	}
+	completed = true
+	return nil
+}
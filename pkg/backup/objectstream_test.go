@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// fakeS3Multipart records a multipart upload in memory
+type fakeS3Multipart struct {
+	created   *s3.CreateMultipartUploadInput
+	parts     map[int32][]byte
+	completed []byte
+	aborted   bool
+}
+
+func (f *fakeS3Multipart) CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	f.created = params
+	f.parts = make(map[int32][]byte)
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-1")}, nil
+}
+
+func (f *fakeS3Multipart) UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.parts[*params.PartNumber] = data
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag-%d", *params.PartNumber))}, nil
+}
+
+func (f *fakeS3Multipart) CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	var b bytes.Buffer
+	for i, part := range params.MultipartUpload.Parts {
+		if *part.PartNumber != int32(i+1) || *part.ETag != fmt.Sprintf("etag-%d", i+1) {
+			return nil, fmt.Errorf("unexpected part %d: %v", i, part)
+		}
+		b.Write(f.parts[*part.PartNumber])
+	}
+	f.completed = b.Bytes()
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (f *fakeS3Multipart) AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	f.aborted = true
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func TestUploadS3Multipart(t *testing.T) {
+	for _, size := range []int{0, 100, streamPartSize, 2*streamPartSize + 100} {
+		data := bytes.Repeat([]byte{'x'}, size)
+		client := &fakeS3Multipart{}
+		if err := uploadS3Multipart(context.TODO(), client, "bucket", "key", s3WriteOptions{}, bytes.NewReader(data)); err != nil {
+			t.Fatalf("uploadS3Multipart(%d bytes) failed: %v", size, err)
+		}
+		if !bytes.Equal(client.completed, data) {
+			t.Errorf("uploaded %d bytes, expected %d", len(client.completed), size)
+		}
+		// An empty stream is uploaded as a single empty part
+		expected := (size + streamPartSize - 1) / streamPartSize
+		if expected == 0 {
+			expected = 1
+		}
+		if len(client.parts) != expected {
+			t.Errorf("uploaded %d bytes in %d parts, expected %d", size, len(client.parts), expected)
+		}
+		if client.aborted {
+			t.Errorf("successful upload was aborted")
+		}
+	}
+}
+
+func TestUploadS3MultipartAborts(t *testing.T) {
+	client := &fakeS3Multipart{}
+	r := io.MultiReader(bytes.NewReader(bytes.Repeat([]byte{'x'}, streamPartSize+10)), &failingReader{err: errors.New("snapshot failed")})
+	err := uploadS3Multipart(context.TODO(), client, "bucket", "key", s3WriteOptions{}, r)
+	if err == nil || !strings.Contains(err.Error(), "snapshot failed") {
+		t.Fatalf("expected the read error, got %v", err)
+	}
+	if !client.aborted || client.completed != nil {
+		t.Errorf("failed upload was not aborted (aborted=%v, completed=%v)", client.aborted, client.completed != nil)
+	}
+}
+
+// fakeS3BucketEncryption returns a fixed bucket encryption policy
+type fakeS3BucketEncryption struct {
+	rules []s3types.ServerSideEncryptionRule
+	err   error
+}
+
+func (f *fakeS3BucketEncryption) GetBucketEncryption(ctx context.Context, params *s3.GetBucketEncryptionInput, optFns ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &s3.GetBucketEncryptionOutput{
+		ServerSideEncryptionConfiguration: &s3types.ServerSideEncryptionConfiguration{Rules: f.rules},
+	}, nil
+}
+
+func TestUploadS3MultipartWriteOptions(t *testing.T) {
+	defaultEncryption := []s3types.ServerSideEncryptionRule{
+		{ApplyServerSideEncryptionByDefault: &s3types.ServerSideEncryptionByDefault{SSEAlgorithm: s3types.ServerSideEncryptionAwsKms}},
+	}
+	grid := []struct {
+		name       string
+		encryption *fakeS3BucketEncryption
+		acl        string
+		expectSSE  s3types.ServerSideEncryption
+		expectACL  s3types.ObjectCannedACL
+	}{
+		{name: "no default encryption", encryption: &fakeS3BucketEncryption{err: errors.New("ServerSideEncryptionConfigurationNotFoundError")}, expectSSE: s3types.ServerSideEncryptionAes256},
+		{name: "default encryption", encryption: &fakeS3BucketEncryption{rules: defaultEncryption}},
+		{name: "acl", encryption: &fakeS3BucketEncryption{rules: defaultEncryption}, acl: " bucket-owner-full-control ", expectACL: s3types.ObjectCannedACLBucketOwnerFullControl},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			t.Setenv("KOPS_STATE_S3_ACL", g.acl)
+
+			options := s3WriteOptionsFor(context.TODO(), g.encryption, "bucket")
+			client := &fakeS3Multipart{}
+			if err := uploadS3Multipart(context.TODO(), client, "bucket", "key", options, bytes.NewReader([]byte("data"))); err != nil {
+				t.Fatalf("uploadS3Multipart failed: %v", err)
+			}
+			if client.created.ServerSideEncryption != g.expectSSE {
+				t.Errorf("upload used ServerSideEncryption %q, expected %q", client.created.ServerSideEncryption, g.expectSSE)
+			}
+			if client.created.ACL != g.expectACL {
+				t.Errorf("upload used ACL %q, expected %q", client.created.ACL, g.expectACL)
+			}
+		})
+	}
+}
+
+// failingReader returns err on every read
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// IsPinned returns true if pin is set and has not expired at now
+func IsPinned(pin *etcd.BackupPin, now time.Time) bool {
+	if pin == nil {
+		return false
+	}
+	return pin.Expiry == 0 || now.Unix() < pin.Expiry
+}
+
+func (s *vfsStore) PinBackup(name string, pin *etcd.BackupPin) error {
+	// LoadInfo validates the name, and checks that the backup exists
+	if _, err := s.LoadInfo(name); err != nil {
+		return fmt.Errorf("cannot pin backup %q: %w", name, err)
+	}
+
+	ctx := context.TODO()
+	p := s.backupsBase.Join(name, PinFilename)
+
+	data, err := etcd.ToJson(pin)
+	if err != nil {
+		return fmt.Errorf("error marshalling pin: %v", err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader([]byte(data)), nil); err != nil {
+		return fmt.Errorf("error writing file %q: %v", p, err)
+	}
+
+	s.setCatalogPin(ctx, name, pin)
+	return nil
+}
+
+func (s *vfsStore) UnpinBackup(name string) error {
+	if err := validateBackupName(name); err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	p := s.backupsBase.Join(name, PinFilename)
+	if err := p.Remove(ctx); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing file %q: %v", p, err)
+	}
+
+	s.setCatalogPin(ctx, name, nil)
+	return nil
+}
+
+func (s *vfsStore) LoadPin(name string) (*etcd.BackupPin, error) {
+	if err := validateBackupName(name); err != nil {
+		return nil, err
+	}
+
+	p := s.backupsBase.Join(name, PinFilename)
+	data, err := p.ReadFile(context.TODO())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading file %q: %v", p, err)
+	}
+
+	pin := &etcd.BackupPin{}
+	if err := etcd.FromJson(string(data), pin); err != nil {
+		return nil, fmt.Errorf("error parsing file %q: %v", p, err)
+	}
+	return pin, nil
+}
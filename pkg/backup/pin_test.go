@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func TestIsPinned(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	if IsPinned(nil, now) {
+		t.Errorf("nil pin should not be pinned")
+	}
+	if !IsPinned(&etcd.BackupPin{}, now) {
+		t.Errorf("pin without expiry should be pinned")
+	}
+	if !IsPinned(&etcd.BackupPin{Expiry: now.Unix() + 1}, now) {
+		t.Errorf("pin expiring in the future should be pinned")
+	}
+	if IsPinned(&etcd.BackupPin{Expiry: now.Unix()}, now) {
+		t.Errorf("expired pin should not be pinned")
+	}
+}
+
+func TestVFSStorePin(t *testing.T) {
+	s, dir := newTestVFSStore(t, nil)
+	store := s.(*vfsStore)
+	catalog := Catalog(store)
+	name := addTestBackup(t, store, []byte("backup data"))
+
+	if err := store.PinBackup("2000-01-01T00:00:00Z-000001", &etcd.BackupPin{}); err == nil {
+		t.Errorf("expected an error pinning a backup that does not exist")
+	}
+
+	// Build the catalog, so we can check it follows the pin
+	if _, err := catalog.ListCatalog(); err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+
+	pin := &etcd.BackupPin{Timestamp: 1700000000, Reason: "before upgrade", Expiry: 1800000000}
+	if err := store.PinBackup(name, pin); err != nil {
+		t.Fatalf("PinBackup failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, name, PinFilename)); err != nil {
+		t.Errorf("expected pin alongside the backup: %v", err)
+	}
+
+	loaded, err := store.LoadPin(name)
+	if err != nil {
+		t.Fatalf("LoadPin failed: %v", err)
+	}
+	if !proto.Equal(loaded, pin) {
+		t.Errorf("LoadPin returned %v, expected %v", loaded, pin)
+	}
+
+	entries, err := catalog.ListCatalog()
+	if err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+	if len(entries) != 1 || !proto.Equal(entries[0].Pin, pin) {
+		t.Errorf("catalog did not record the pin: %v", entries)
+	}
+
+	// A rebuilt catalog picks up the pin
+	if err := catalog.RebuildCatalog(); err != nil {
+		t.Fatalf("RebuildCatalog failed: %v", err)
+	}
+	entries, err = catalog.ListCatalog()
+	if err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+	if len(entries) != 1 || !proto.Equal(entries[0].Pin, pin) {
+		t.Errorf("rebuilt catalog did not record the pin: %v", entries)
+	}
+
+	if err := store.UnpinBackup(name); err != nil {
+		t.Fatalf("UnpinBackup failed: %v", err)
+	}
+	if loaded, err := store.LoadPin(name); err != nil || loaded != nil {
+		t.Errorf("expected no pin after unpin, got %v (err=%v)", loaded, err)
+	}
+	entries, err = catalog.ListCatalog()
+	if err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Pin != nil {
+		t.Errorf("catalog did not record the unpin: %v", entries)
+	}
+
+	// Unpinning an unpinned backup is not an error
+	if err := store.UnpinBackup(name); err != nil {
+		t.Errorf("UnpinBackup of unpinned backup failed: %v", err)
+	}
+}
@@ -0,0 +1,722 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// StoreSeparator separates the locations of a replicated store in a store spec
+const StoreSeparator = ","
+
+// ReplicationPolicyEnv is the environment variable that sets the ReplicationPolicy for a replicated store
+const ReplicationPolicyEnv = "ETCD_MANAGER_BACKUP_REPLICATION_POLICY"
+
+// ReplicationPolicy controls how many locations of a replicated store must accept a write for it to succeed
+type ReplicationPolicy string
+
+const (
+	// ReplicationPolicyAll requires every location to accept the write
+	ReplicationPolicyAll ReplicationPolicy = "all"
+	// ReplicationPolicyAny requires at least one location to accept the write
+	ReplicationPolicyAny ReplicationPolicy = "any"
+	// ReplicationPolicyQuorum requires a majority of the locations to accept the write
+	ReplicationPolicyQuorum ReplicationPolicy = "quorum"
+)
+
+// ParseReplicationPolicy parses a replication policy; the empty string means ReplicationPolicyAll
+func ParseReplicationPolicy(s string) (ReplicationPolicy, error) {
+	switch ReplicationPolicy(s) {
+	case "":
+		return ReplicationPolicyAll, nil
+	case ReplicationPolicyAll, ReplicationPolicyAny, ReplicationPolicyQuorum:
+		return ReplicationPolicy(s), nil
+	default:
+		return "", fmt.Errorf("unknown backup replication policy %q (expected %s, %s or %s)", s, ReplicationPolicyAll, ReplicationPolicyAny, ReplicationPolicyQuorum)
+	}
+}
+
+// ReplicationPolicyFromEnv returns the replication policy configured by ReplicationPolicyEnv
+func ReplicationPolicyFromEnv() (ReplicationPolicy, error) {
+	return ParseReplicationPolicy(os.Getenv(ReplicationPolicyEnv))
+}
+
+// required returns the number of successful writes needed out of n locations
+func (p ReplicationPolicy) required(n int) int {
+	switch p {
+	case ReplicationPolicyAny:
+		return 1
+	case ReplicationPolicyQuorum:
+		return n/2 + 1
+	default:
+		return n
+	}
+}
+
+// SplitStoreSpec splits a store spec into the locations it names.
+// A spec naming more than one location describes a replicated store.
+func SplitStoreSpec(spec string) []string {
+	var locations []string
+	for _, location := range strings.Split(spec, StoreSeparator) {
+		location = strings.TrimSpace(location)
+		if location != "" {
+			locations = append(locations, location)
+		}
+	}
+	return locations
+}
+
+// replicatedStore is a Store that writes each backup to several stores, and reads from the first that can serve it
+type replicatedStore struct {
+	stores []Store
+	policy ReplicationPolicy
+}
+
+var _ Store = &replicatedStore{}
+var _ VerificationStore = &replicatedStore{}
+var _ PinStore = &replicatedStore{}
+var _ AnomalyStore = &replicatedStore{}
+var _ StateStore = &replicatedStore{}
+var _ StreamingStore = &replicatedStore{}
+var _ RevisionArchive = &replicatedStore{}
+var _ Catalog = &replicatedStore{}
+
+// NewReplicatedStore returns a Store that replicates backups across stores, which should all be RevisionArchives,
+// VerificationStores, PinStores, AnomalyStores and StateStores.
+// Writes succeed when the policy is satisfied; reads fall back through the stores in order.
+func NewReplicatedStore(stores []Store, policy ReplicationPolicy) (Store, error) {
+	if len(stores) == 0 {
+		return nil, fmt.Errorf("replicated backup store requires at least one store")
+	}
+	for _, store := range stores {
+		if _, ok := store.(RevisionArchive); !ok {
+			return nil, fmt.Errorf("backup store %s does not support archiving revisions", store.Spec())
+		}
+		if _, ok := store.(VerificationStore); !ok {
+			return nil, fmt.Errorf("backup store %s does not support recording restore drills", store.Spec())
+		}
+		if _, ok := store.(PinStore); !ok {
+			return nil, fmt.Errorf("backup store %s does not support pinning backups", store.Spec())
+		}
+		if _, ok := store.(AnomalyStore); !ok {
+			return nil, fmt.Errorf("backup store %s does not support flagging anomalies", store.Spec())
+		}
+		if _, ok := store.(StateStore); !ok {
+			return nil, fmt.Errorf("backup store %s does not support archiving the cluster state", store.Spec())
+		}
+	}
+	if _, err := ParseReplicationPolicy(string(policy)); err != nil {
+		return nil, err
+	}
+
+	s := &replicatedStore{
+		stores: stores,
+		policy: policy,
+	}
+	return s, nil
+}
+
+func (s *replicatedStore) Spec() string {
+	var specs []string
+	for _, store := range s.stores {
+		specs = append(specs, store.Spec())
+	}
+	return strings.Join(specs, StoreSeparator)
+}
+
+// Codec returns the codec of the first store; every store is built from the same configuration
+func (s *replicatedStore) Codec() Codec {
+	return s.stores[0].Codec()
+}
+
+// replicate runs fn against every store in parallel, returning the index of the first store that succeeded,
+// or an error if fewer stores succeeded than the policy requires.
+func (s *replicatedStore) replicate(description string, fn func(store Store) error) (int, error) {
+	errs := make([]error, len(s.stores))
+
+	var wg sync.WaitGroup
+	for i, store := range s.stores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(store)
+		}()
+	}
+	wg.Wait()
+
+	first := -1
+	succeeded := 0
+	var failures []error
+	for i, err := range errs {
+		if err != nil {
+			klog.Warningf("error %s in backup store %s: %v", description, s.stores[i].Spec(), err)
+			failures = append(failures, fmt.Errorf("%s: %w", s.stores[i].Spec(), err))
+			continue
+		}
+		succeeded++
+		if first == -1 {
+			first = i
+		}
+	}
+
+	required := s.policy.required(len(s.stores))
+	if succeeded < required {
+		return -1, fmt.Errorf("error %s: succeeded in %d of %d backup stores, policy %q requires %d: %w", description, succeeded, len(s.stores), s.policy, required, errors.Join(failures...))
+	}
+	return first, nil
+}
+
+func (s *replicatedStore) AddBackup(backupFile string, sequence string, info *etcd.BackupInfo) (string, error) {
+	// Fix the timestamp up front, so that every store gives the backup the same name
+	if info.Timestamp == 0 {
+		info.Timestamp = time.Now().Unix()
+	}
+
+	// Each store records its own checksum (the stored data differs when encrypted), so each gets its own copy of info
+	infos := make(map[Store]*etcd.BackupInfo)
+	for _, store := range s.stores {
+		infos[store] = proto.Clone(info).(*etcd.BackupInfo)
+	}
+	names := make(map[Store]string)
+	var mutex sync.Mutex
+
+	first, err := s.replicate("adding backup", func(store Store) error {
+		name, err := store.AddBackup(backupFile, sequence, infos[store])
+		if err != nil {
+			return err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		names[store] = name
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	proto.Merge(info, infos[s.stores[first]])
+	return names[s.stores[first]], nil
+}
+
+// CanStream returns true if every store can accept a stream
+func (s *replicatedStore) CanStream() bool {
+	for _, store := range s.stores {
+		streamingStore, ok := store.(StreamingStore)
+		if !ok || !streamingStore.CanStream() {
+			return false
+		}
+	}
+	return true
+}
+
+// AddBackupFromStream streams the backup to every store at once, subject to the policy.
+// A store that fails stops receiving the stream, without holding up the others.
+func (s *replicatedStore) AddBackupFromStream(writeData func(w io.Writer) error, sequence string, info *etcd.BackupInfo) (string, error) {
+	if !s.CanStream() {
+		return "", fmt.Errorf("backup store %s does not support streaming backups", s.Spec())
+	}
+
+	// Fix the timestamp up front, so that every store gives the backup the same name
+	if info.Timestamp == 0 {
+		info.Timestamp = time.Now().Unix()
+	}
+
+	infos := make(map[Store]*etcd.BackupInfo)
+	readers := make(map[Store]*io.PipeReader)
+	fanout := &fanoutWriter{}
+	for _, store := range s.stores {
+		infos[store] = proto.Clone(info).(*etcd.BackupInfo)
+		pr, pw := io.Pipe()
+		readers[store] = pr
+		fanout.writers = append(fanout.writers, pw)
+	}
+	names := make(map[Store]string)
+	var mutex sync.Mutex
+
+	type result struct {
+		first int
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		first, err := s.replicate("adding backup", func(store Store) error {
+			pr := readers[store]
+			name, err := store.(StreamingStore).AddBackupFromStream(func(w io.Writer) error {
+				_, err := io.Copy(w, pr)
+				return err
+			}, sequence, infos[store])
+			// If the store stopped early, this drops it from the fanout rather than blocking the others
+			_ = pr.CloseWithError(fmt.Errorf("backup store %s stopped reading", store.Spec()))
+			if err != nil {
+				return err
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			names[store] = name
+			return nil
+		})
+		done <- result{first: first, err: err}
+	}()
+
+	err := writeData(fanout)
+	for _, pw := range fanout.writers {
+		// A nil error ends each stream normally
+		_ = pw.CloseWithError(err)
+	}
+	r := <-done
+	if r.err != nil {
+		return "", r.err
+	}
+	if err != nil {
+		return "", err
+	}
+
+	proto.Merge(info, infos[s.stores[r.first]])
+	return names[s.stores[r.first]], nil
+}
+
+// fanoutWriter writes to every writer that has not yet failed, failing only when every writer has failed
+type fanoutWriter struct {
+	writers []*io.PipeWriter
+	failed  []bool
+}
+
+func (w *fanoutWriter) Write(p []byte) (int, error) {
+	if w.failed == nil {
+		w.failed = make([]bool, len(w.writers))
+	}
+	var errs []error
+	for i, writer := range w.writers {
+		if w.failed[i] {
+			continue
+		}
+		if _, err := writer.Write(p); err != nil {
+			w.failed[i] = true
+			errs = append(errs, err)
+		}
+	}
+	for _, failed := range w.failed {
+		if !failed {
+			return len(p), nil
+		}
+	}
+	return 0, fmt.Errorf("every backup store stopped reading: %w", errors.Join(errs...))
+}
+
+// ListBackups returns the backups in any of the stores, tolerating stores that cannot be listed as long as one can
+func (s *replicatedStore) ListBackups() ([]string, error) {
+	seen := make(map[string]bool)
+	var errs []error
+	for _, store := range s.stores {
+		backups, err := store.ListBackups()
+		if err != nil {
+			klog.Warningf("error listing backups in backup store %s: %v", store.Spec(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+			continue
+		}
+		for _, backup := range backups {
+			seen[backup] = true
+		}
+	}
+	if len(errs) == len(s.stores) {
+		return nil, fmt.Errorf("error listing backups: %w", errors.Join(errs...))
+	}
+
+	var backups []string
+	for backup := range seen {
+		backups = append(backups, backup)
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// ListCatalog merges the catalogs of the stores, preferring the entry from the earliest store that holds a backup
+func (s *replicatedStore) ListCatalog() ([]*etcd.BackupCatalogEntry, error) {
+	seen := make(map[string]*etcd.BackupCatalogEntry)
+	var errs []error
+	for _, store := range s.stores {
+		entries, err := listCatalog(store)
+		if err != nil {
+			klog.Warningf("error listing catalog in backup store %s: %v", store.Spec(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+			continue
+		}
+		for _, entry := range entries {
+			existing := seen[entry.Name]
+			if existing == nil {
+				seen[entry.Name] = entry
+				continue
+			}
+			if existing.Pin == nil && entry.Pin != nil {
+				// A pin in any store keeps the backup
+				existing.Pin = entry.Pin
+			}
+			if existing.Anomaly == nil && entry.Anomaly != nil {
+				existing.Anomaly = entry.Anomaly
+			}
+		}
+	}
+	if len(errs) == len(s.stores) {
+		return nil, fmt.Errorf("error listing catalog: %w", errors.Join(errs...))
+	}
+
+	var entries []*etcd.BackupCatalogEntry
+	for _, entry := range seen {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// listCatalog returns the catalog of a store, falling back to listing the backups (without info) if it has no catalog
+func listCatalog(store Store) ([]*etcd.BackupCatalogEntry, error) {
+	if catalog, ok := store.(Catalog); ok {
+		return catalog.ListCatalog()
+	}
+	backups, err := store.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	var entries []*etcd.BackupCatalogEntry
+	for _, backup := range backups {
+		entries = append(entries, &etcd.BackupCatalogEntry{Name: backup})
+	}
+	return entries, nil
+}
+
+func (s *replicatedStore) RebuildCatalog() error {
+	var errs []error
+	for _, store := range s.stores {
+		catalog, ok := store.(Catalog)
+		if !ok {
+			continue
+		}
+		if err := catalog.RebuildCatalog(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("error rebuilding catalog: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// RemoveBackup removes the backup from every store; a store that does not hold the backup is not an error,
+// as a backup written under the any or quorum policies may not have reached every store.
+func (s *replicatedStore) RemoveBackup(backup string) error {
+	var errs []error
+	var missing []error
+	for _, store := range s.stores {
+		if err := store.RemoveBackup(backup); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				missing = append(missing, fmt.Errorf("%s: %w", store.Spec(), err))
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("error removing backup %q: %w", backup, errors.Join(errs...))
+	}
+	if len(missing) == len(s.stores) {
+		return fmt.Errorf("error removing backup %q: %w", backup, errors.Join(missing...))
+	}
+	return nil
+}
+
+// firstOf calls fn against each store in turn, until one succeeds
+func (s *replicatedStore) firstOf(description string, fn func(store Store) error) error {
+	var errs []error
+	for _, store := range s.stores {
+		err := fn(store)
+		if err == nil {
+			return nil
+		}
+		klog.Warningf("error %s from backup store %s: %v", description, store.Spec(), err)
+		errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+	}
+	return fmt.Errorf("error %s: %w", description, errors.Join(errs...))
+}
+
+func (s *replicatedStore) LoadInfo(backup string) (*etcd.BackupInfo, error) {
+	var info *etcd.BackupInfo
+	err := s.firstOf(fmt.Sprintf("loading info for backup %q", backup), func(store Store) error {
+		var err error
+		info, err = store.LoadInfo(backup)
+		return err
+	})
+	return info, err
+}
+
+// DownloadBackup downloads the backup from the first store that holds an intact copy
+func (s *replicatedStore) DownloadBackup(name string, destFile string) error {
+	return s.firstOf(fmt.Sprintf("downloading backup %q", name), func(store Store) error {
+		return store.DownloadBackup(name, destFile)
+	})
+}
+
+// VerifyBackup verifies every copy of the backup, so a missing or corrupted replica is reported
+func (s *replicatedStore) VerifyBackup(name string) error {
+	var errs []error
+	for _, store := range s.stores {
+		if err := store.VerifyBackup(name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SaveVerification records the drill result in every store holding the backup, subject to the policy
+func (s *replicatedStore) SaveVerification(name string, verification *etcd.BackupVerification) error {
+	_, err := s.replicate(fmt.Sprintf("saving verification for backup %q", name), func(store Store) error {
+		return store.(VerificationStore).SaveVerification(name, verification)
+	})
+	return err
+}
+
+// LoadVerification returns the first drill result recorded in any of the stores
+func (s *replicatedStore) LoadVerification(name string) (*etcd.BackupVerification, error) {
+	var errs []error
+	for _, store := range s.stores {
+		verification, err := store.(VerificationStore).LoadVerification(name)
+		if err != nil {
+			klog.Warningf("error loading verification for backup %q from backup store %s: %v", name, store.Spec(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+			continue
+		}
+		if verification != nil {
+			return verification, nil
+		}
+	}
+	if len(errs) == len(s.stores) {
+		return nil, fmt.Errorf("error loading verification for backup %q: %w", name, errors.Join(errs...))
+	}
+	return nil, nil
+}
+
+// PinBackup pins the backup in every store, subject to the policy.
+// Stores that do not hold the backup cannot pin it, so with the all policy every copy must exist.
+func (s *replicatedStore) PinBackup(name string, pin *etcd.BackupPin) error {
+	_, err := s.replicate(fmt.Sprintf("pinning backup %q", name), func(store Store) error {
+		return store.(PinStore).PinBackup(name, pin)
+	})
+	return err
+}
+
+// UnpinBackup unpins the backup in every store
+func (s *replicatedStore) UnpinBackup(name string) error {
+	var errs []error
+	for _, store := range s.stores {
+		if err := store.(PinStore).UnpinBackup(name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("error unpinning backup %q: %w", name, errors.Join(errs...))
+	}
+	return nil
+}
+
+// LoadPin returns the first pin recorded in any of the stores
+func (s *replicatedStore) LoadPin(name string) (*etcd.BackupPin, error) {
+	var errs []error
+	for _, store := range s.stores {
+		pin, err := store.(PinStore).LoadPin(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+			continue
+		}
+		if pin != nil {
+			return pin, nil
+		}
+	}
+	if len(errs) != 0 {
+		// A pin we cannot read may still be in force, so we don't report the backup as unpinned
+		return nil, fmt.Errorf("error loading pin for backup %q: %w", name, errors.Join(errs...))
+	}
+	return nil, nil
+}
+
+// FlagAnomaly flags the backup in every store, subject to the policy
+func (s *replicatedStore) FlagAnomaly(name string, anomaly *etcd.BackupAnomaly) error {
+	_, err := s.replicate(fmt.Sprintf("flagging backup %q", name), func(store Store) error {
+		return store.(AnomalyStore).FlagAnomaly(name, anomaly)
+	})
+	return err
+}
+
+// ClearAnomaly clears the anomaly flag in every store
+func (s *replicatedStore) ClearAnomaly(name string) error {
+	var errs []error
+	for _, store := range s.stores {
+		if err := store.(AnomalyStore).ClearAnomaly(name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("error clearing anomaly for backup %q: %w", name, errors.Join(errs...))
+	}
+	return nil
+}
+
+// LoadAnomaly returns the first anomaly recorded in any of the stores
+func (s *replicatedStore) LoadAnomaly(name string) (*etcd.BackupAnomaly, error) {
+	var errs []error
+	for _, store := range s.stores {
+		anomaly, err := store.(AnomalyStore).LoadAnomaly(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+			continue
+		}
+		if anomaly != nil {
+			return anomaly, nil
+		}
+	}
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("error loading anomaly for backup %q: %w", name, errors.Join(errs...))
+	}
+	return nil, nil
+}
+
+// SaveState archives the cluster state in every store holding the backup, subject to the policy
+func (s *replicatedStore) SaveState(name string, state *etcd.BackupState, key *EncryptionKey) error {
+	_, err := s.replicate(fmt.Sprintf("saving cluster state for backup %q", name), func(store Store) error {
+		return store.(StateStore).SaveState(name, state, key)
+	})
+	return err
+}
+
+// LoadState returns the first cluster state archived in any of the stores
+func (s *replicatedStore) LoadState(name string, key *EncryptionKey) (*etcd.BackupState, error) {
+	var errs []error
+	for _, store := range s.stores {
+		state, err := store.(StateStore).LoadState(name, key)
+		if err != nil {
+			klog.Warningf("error loading cluster state for backup %q from backup store %s: %v", name, store.Spec(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+			continue
+		}
+		if state != nil {
+			return state, nil
+		}
+	}
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("error loading cluster state for backup %q: %w", name, errors.Join(errs...))
+	}
+	return nil, nil
+}
+
+func (s *replicatedStore) AddRevisionSegment(info *etcd.RevisionSegmentInfo, data []byte) (string, error) {
+	infos := make(map[Store]*etcd.RevisionSegmentInfo)
+	for _, store := range s.stores {
+		infos[store] = proto.Clone(info).(*etcd.RevisionSegmentInfo)
+	}
+	names := make(map[Store]string)
+	var mutex sync.Mutex
+
+	first, err := s.replicate("adding revision segment", func(store Store) error {
+		name, err := store.(RevisionArchive).AddRevisionSegment(infos[store], data)
+		if err != nil {
+			return err
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		names[store] = name
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	proto.Merge(info, infos[s.stores[first]])
+	return names[s.stores[first]], nil
+}
+
+// ListRevisionSegments returns the segments in any of the stores, tolerating stores that cannot be listed as long as one can
+func (s *replicatedStore) ListRevisionSegments(clusterID string) ([]string, error) {
+	seen := make(map[string]bool)
+	var errs []error
+	for _, store := range s.stores {
+		segments, err := store.(RevisionArchive).ListRevisionSegments(clusterID)
+		if err != nil {
+			klog.Warningf("error listing revision segments in backup store %s: %v", store.Spec(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+			continue
+		}
+		for _, segment := range segments {
+			seen[segment] = true
+		}
+	}
+	if len(errs) == len(s.stores) {
+		return nil, fmt.Errorf("error listing revision segments: %w", errors.Join(errs...))
+	}
+
+	var segments []string
+	for segment := range seen {
+		segments = append(segments, segment)
+	}
+	// Segment names are zero-padded revisions, so they sort into revision order
+	sort.Strings(segments)
+	return segments, nil
+}
+
+func (s *replicatedStore) LoadRevisionSegmentInfo(clusterID string, name string) (*etcd.RevisionSegmentInfo, error) {
+	var info *etcd.RevisionSegmentInfo
+	err := s.firstOf(fmt.Sprintf("loading info for revision segment %q", name), func(store Store) error {
+		var err error
+		info, err = store.(RevisionArchive).LoadRevisionSegmentInfo(clusterID, name)
+		return err
+	})
+	return info, err
+}
+
+func (s *replicatedStore) ReadRevisionSegment(clusterID string, name string) (*etcd.RevisionSegmentInfo, []byte, error) {
+	var info *etcd.RevisionSegmentInfo
+	var data []byte
+	err := s.firstOf(fmt.Sprintf("reading revision segment %q", name), func(store Store) error {
+		var err error
+		info, data, err = store.(RevisionArchive).ReadRevisionSegment(clusterID, name)
+		return err
+	})
+	return info, data, err
+}
+
+func (s *replicatedStore) RemoveRevisionSegment(clusterID string, name string) error {
+	var errs []error
+	for _, store := range s.stores {
+		if err := store.(RevisionArchive).RemoveRevisionSegment(clusterID, name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Spec(), err))
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("error removing revision segment %q: %w", name, errors.Join(errs...))
+	}
+	return nil
+}
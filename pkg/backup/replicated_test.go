@@ -0,0 +1,347 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// failingStore is a store that rejects new backups
+type failingStore struct {
+	*vfsStore
+}
+
+func (s *failingStore) AddBackup(backupFile string, sequence string, info *etcd.BackupInfo) (string, error) {
+	return "", fmt.Errorf("store is unavailable")
+}
+
+// AddBackupFromStream rejects new backups without reading the stream
+func (s *failingStore) AddBackupFromStream(writeData func(w io.Writer) error, sequence string, info *etcd.BackupInfo) (string, error) {
+	return "", fmt.Errorf("store is unavailable")
+}
+
+func TestReplicatedStoreAddBackupFromStream(t *testing.T) {
+	for _, policy := range []ReplicationPolicy{ReplicationPolicyAll, ReplicationPolicyQuorum} {
+		t.Run(string(policy), func(t *testing.T) {
+			var stores []Store
+			var dirs []string
+			for i := 0; i < 3; i++ {
+				store, dir := newTestVFSStore(t, nil)
+				if i == 0 {
+					store = &failingStore{vfsStore: store.(*vfsStore)}
+				}
+				stores = append(stores, store)
+				dirs = append(dirs, dir)
+			}
+			replicated, err := NewReplicatedStore(stores, policy)
+			if err != nil {
+				t.Fatalf("NewReplicatedStore failed: %v", err)
+			}
+			streamingStore := replicated.(StreamingStore)
+			if !streamingStore.CanStream() {
+				t.Fatalf("expected replicated filesystem stores to support streaming")
+			}
+
+			// More than a pipe can buffer, so the failed store must not block the others
+			data := bytes.Repeat([]byte("stream"), 100000)
+			name, err := streamingStore.AddBackupFromStream(func(w io.Writer) error {
+				for i := 0; i < len(data); i += 1000 {
+					if _, err := w.Write(data[i : i+1000]); err != nil {
+						return err
+					}
+				}
+				return nil
+			}, "000001", &etcd.BackupInfo{EtcdVersion: "3.5.0"})
+			if policy == ReplicationPolicyAll {
+				if err == nil {
+					t.Fatalf("expected AddBackupFromStream to fail with a failing store")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddBackupFromStream failed: %v", err)
+			}
+
+			for i := 1; i < len(stores); i++ {
+				destFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+				if err := stores[i].DownloadBackup(name, destFile); err != nil {
+					t.Fatalf("DownloadBackup from store %d failed: %v", i, err)
+				}
+				downloaded, err := os.ReadFile(destFile)
+				if err != nil {
+					t.Fatalf("failed to read download: %v", err)
+				}
+				if !bytes.Equal(downloaded, data) {
+					t.Errorf("data in store %d did not match", i)
+				}
+			}
+		})
+	}
+}
+
+func TestReplicationPolicy(t *testing.T) {
+	grid := []struct {
+		Policy    ReplicationPolicy
+		Failing   int
+		ExpectErr bool
+	}{
+		{Policy: ReplicationPolicyAll, Failing: 0},
+		{Policy: ReplicationPolicyAll, Failing: 1, ExpectErr: true},
+		{Policy: ReplicationPolicyQuorum, Failing: 1},
+		{Policy: ReplicationPolicyQuorum, Failing: 2, ExpectErr: true},
+		{Policy: ReplicationPolicyAny, Failing: 2},
+		{Policy: ReplicationPolicyAny, Failing: 3, ExpectErr: true},
+	}
+
+	for _, g := range grid {
+		t.Run(fmt.Sprintf("%s with %d failing", g.Policy, g.Failing), func(t *testing.T) {
+			var stores []Store
+			var dirs []string
+			for i := 0; i < 3; i++ {
+				store, dir := newTestVFSStore(t, nil)
+				if i < g.Failing {
+					store = &failingStore{vfsStore: store.(*vfsStore)}
+				}
+				stores = append(stores, store)
+				dirs = append(dirs, dir)
+			}
+
+			replicated, err := NewReplicatedStore(stores, g.Policy)
+			if err != nil {
+				t.Fatalf("NewReplicatedStore failed: %v", err)
+			}
+
+			srcFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+			if err := os.WriteFile(srcFile, []byte("backup data"), 0600); err != nil {
+				t.Fatalf("failed to write source file: %v", err)
+			}
+			name, err := replicated.AddBackup(srcFile, "000001", &etcd.BackupInfo{})
+			if g.ExpectErr {
+				if err == nil {
+					t.Fatalf("expected AddBackup to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AddBackup failed: %v", err)
+			}
+
+			// Every store that accepted the backup holds it under the same name
+			for i := g.Failing; i < len(dirs); i++ {
+				if _, err := os.Stat(filepath.Join(dirs[i], name, MetaFilename)); err != nil {
+					t.Errorf("backup %q missing from store %d: %v", name, i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReplicatedStoreFallback(t *testing.T) {
+	primary, primaryDir := newTestVFSStore(t, nil)
+	secondary, _ := newTestVFSStore(t, newTestEncryptionKey(t))
+
+	replicated, err := NewReplicatedStore([]Store{primary, secondary}, ReplicationPolicyAll)
+	if err != nil {
+		t.Fatalf("NewReplicatedStore failed: %v", err)
+	}
+
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	srcFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+	if err := os.WriteFile(srcFile, data, 0600); err != nil {
+		t.Fatalf("failed to write source file: %v", err)
+	}
+	var names []string
+	for _, sequence := range []string{"000001", "000002"} {
+		name, err := replicated.AddBackup(srcFile, sequence, &etcd.BackupInfo{EtcdVersion: "3.5.0"})
+		if err != nil {
+			t.Fatalf("AddBackup failed: %v", err)
+		}
+		names = append(names, name)
+	}
+	older, newer := names[0], names[1]
+
+	// A backup that only reached one store is still listed
+	if err := primary.RemoveBackup(older); err != nil {
+		t.Fatalf("RemoveBackup failed: %v", err)
+	}
+	backups, err := replicated.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if !reflect.DeepEqual(backups, []string{older, newer}) {
+		t.Errorf("ListBackups returned %v, expected %v", backups, []string{older, newer})
+	}
+
+	// Downloads fall back past missing and corrupted copies
+	dataFile := filepath.Join(primaryDir, newer, DataFilename)
+	stored, err := os.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("failed to read stored backup: %v", err)
+	}
+	stored[len(stored)/2] ^= 0xff
+	if err := os.WriteFile(dataFile, stored, 0600); err != nil {
+		t.Fatalf("failed to write stored backup: %v", err)
+	}
+
+	for _, name := range []string{older, newer} {
+		destFile := filepath.Join(t.TempDir(), "download")
+		if err := replicated.DownloadBackup(name, destFile); err != nil {
+			t.Fatalf("DownloadBackup(%q) failed: %v", name, err)
+		}
+		downloaded, err := os.ReadFile(destFile)
+		if err != nil {
+			t.Fatalf("failed to read downloaded backup: %v", err)
+		}
+		if !bytes.Equal(downloaded, data) {
+			t.Errorf("downloaded backup %q did not match", name)
+		}
+
+		if _, err := replicated.LoadInfo(name); err != nil {
+			t.Errorf("LoadInfo(%q) failed: %v", name, err)
+		}
+	}
+
+	// Verification reports each bad copy
+	err = replicated.VerifyBackup(newer)
+	if err == nil || !strings.Contains(err.Error(), primary.Spec()) {
+		t.Errorf("expected verification of %q to report the corrupted copy in %s, got %v", newer, primary.Spec(), err)
+	}
+
+	if err := replicated.RemoveBackup(newer); err != nil {
+		t.Fatalf("RemoveBackup failed: %v", err)
+	}
+	backups, err = replicated.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if !reflect.DeepEqual(backups, []string{older}) {
+		t.Errorf("after remove, ListBackups returned %v", backups)
+	}
+}
+
+func TestReplicatedStoreRemovePartiallyReplicated(t *testing.T) {
+	primary, _ := newTestVFSStore(t, nil)
+	secondary, _ := newTestVFSStore(t, nil)
+
+	replicated, err := NewReplicatedStore([]Store{primary, secondary}, ReplicationPolicyAny)
+	if err != nil {
+		t.Fatalf("NewReplicatedStore failed: %v", err)
+	}
+
+	// As if the backup was written while the primary was unavailable
+	name := addTestBackup(t, secondary, []byte("backup data"))
+
+	if err := replicated.RemoveBackup(name); err != nil {
+		t.Fatalf("RemoveBackup of a partially replicated backup failed: %v", err)
+	}
+	backups, err := replicated.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 0 {
+		t.Errorf("after remove, ListBackups returned %v", backups)
+	}
+
+	// A backup that no store holds is still an error
+	if err := replicated.RemoveBackup("2000-01-01T00:00:00Z-000001"); err == nil || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected removing a missing backup to fail with a not-exist error, got %v", err)
+	}
+}
+
+func TestNewStoreReplicated(t *testing.T) {
+	t.Setenv(ReplicationPolicyEnv, string(ReplicationPolicyQuorum))
+
+	base := t.TempDir()
+	spec := filepath.Join(base, "a") + StoreSeparator + filepath.Join(base, "b")
+	store, err := NewStore(spec)
+	if err != nil {
+		t.Fatalf("NewStore(%q) failed: %v", spec, err)
+	}
+
+	replicated, ok := store.(*replicatedStore)
+	if !ok {
+		t.Fatalf("expected a replicated store, got %T", store)
+	}
+	if replicated.policy != ReplicationPolicyQuorum {
+		t.Errorf("unexpected policy %q", replicated.policy)
+	}
+
+	// Peers rebuild the store from its spec
+	rebuilt, err := NewStore(store.Spec())
+	if err != nil {
+		t.Fatalf("NewStore(%q) failed: %v", store.Spec(), err)
+	}
+	if rebuilt.Spec() != store.Spec() {
+		t.Errorf("spec did not round trip: %q != %q", rebuilt.Spec(), store.Spec())
+	}
+
+	t.Setenv(ReplicationPolicyEnv, "most")
+	if _, err := NewStore(spec); err == nil {
+		t.Errorf("expected an error for an unknown replication policy")
+	}
+}
+
+func TestReplicatedStoreListCatalog(t *testing.T) {
+	primary, _ := newTestVFSStore(t, nil)
+	secondary, _ := newTestVFSStore(t, nil)
+
+	replicated, err := NewReplicatedStore([]Store{primary, secondary}, ReplicationPolicyAny)
+	if err != nil {
+		t.Fatalf("NewReplicatedStore failed: %v", err)
+	}
+
+	// Both stores hold the first backup, with the pin only in the secondary and the anomaly only in the primary
+	shared := addTestBackupWithSequence(t, replicated, "000001")
+	if err := secondary.(PinStore).PinBackup(shared, &etcd.BackupPin{Reason: "compliance"}); err != nil {
+		t.Fatalf("PinBackup failed: %v", err)
+	}
+	if err := primary.(AnomalyStore).FlagAnomaly(shared, &etcd.BackupAnomaly{Reason: "shrank"}); err != nil {
+		t.Fatalf("FlagAnomaly failed: %v", err)
+	}
+	secondaryOnly := addTestBackupWithSequence(t, secondary, "000002")
+
+	entries, err := replicated.(Catalog).ListCatalog()
+	if err != nil {
+		t.Fatalf("ListCatalog failed: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	if expected := []string{shared, secondaryOnly}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("ListCatalog returned %v, expected %v", names, expected)
+	}
+	if entries[0].Pin.GetReason() != "compliance" {
+		t.Errorf("expected the pin from the secondary store to be merged, got %v", entries[0].Pin)
+	}
+	if entries[0].Anomaly.GetReason() != "shrank" {
+		t.Errorf("expected the anomaly from the primary store to be kept, got %v", entries[0].Anomaly)
+	}
+	if entries[1].Pin != nil || entries[1].Anomaly != nil {
+		t.Errorf("unexpected pin or anomaly on %s: %v", secondaryOnly, entries[1])
+	}
+}
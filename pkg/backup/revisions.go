@@ -0,0 +1,288 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// RevisionsDir is the directory under the backup store base holding archived revision segments,
+// in a subdirectory for each etcd cluster ID
+const RevisionsDir = "_etcd_revisions"
+
+// RevisionSegmentMetaFilename is the info for a segment; it is written last, marking the segment as complete
+const RevisionSegmentMetaFilename = "_etcd_segment.meta"
+
+// revisionSegmentDataFilename is the name of the data file for a segment, before the codec's extension
+const revisionSegmentDataFilename = "revisions"
+
+var _ RevisionArchive = &vfsStore{}
+
+// RevisionSegmentName returns the name for a segment covering startRevision to endRevision.
+// Revisions are zero padded, so that names sort in revision order.
+func RevisionSegmentName(startRevision, endRevision int64) string {
+	return fmt.Sprintf("%016d-%016d", startRevision, endRevision)
+}
+
+// ParseRevisionSegmentName returns the start and end revisions of a segment, from its name
+func ParseRevisionSegmentName(name string) (int64, int64, error) {
+	var startRevision, endRevision int64
+	if _, err := fmt.Sscanf(name, "%d-%d", &startRevision, &endRevision); err != nil {
+		return 0, 0, fmt.Errorf("cannot parse revision segment name %q: %v", name, err)
+	}
+	if RevisionSegmentName(startRevision, endRevision) != name {
+		return 0, 0, fmt.Errorf("cannot parse revision segment name %q", name)
+	}
+	return startRevision, endRevision, nil
+}
+
+// revisionSegmentDataFile returns the name of the data file for a segment compressed with codec
+func revisionSegmentDataFile(codec Codec) string {
+	return revisionSegmentDataFilename + codec.Extension()
+}
+
+// EncodeRevisionSegment serializes and compresses a sequence of archived revisions
+func EncodeRevisionSegment(codec Codec, revisions []*etcd.ArchivedRevision) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := codec.NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		if _, err := protodelim.MarshalTo(w, revision); err != nil {
+			return nil, fmt.Errorf("error encoding revision %d: %w", revision.Revision, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("error compressing revisions: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeRevisionSegment calls fn for each of the archived revisions in segment data, in order
+func DecodeRevisionSegment(codec Codec, data []byte, fn func(revision *etcd.ArchivedRevision) error) error {
+	r, err := codec.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error decompressing revisions: %w", err)
+	}
+	defer r.Close()
+
+	br := bufio.NewReader(r)
+	for {
+		revision := &etcd.ArchivedRevision{}
+		if err := protodelim.UnmarshalFrom(br, revision); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("error decoding revisions: %w", err)
+		}
+		if err := fn(revision); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *vfsStore) AddRevisionSegment(info *etcd.RevisionSegmentInfo, data []byte) (string, error) {
+	ctx := context.TODO()
+
+	codec, err := CodecFor(info.Compression)
+	if err != nil {
+		return "", err
+	}
+
+	if err := validateBackupName(info.EtcdClusterId); err != nil {
+		return "", fmt.Errorf("invalid etcd cluster id: %v", err)
+	}
+
+	name := RevisionSegmentName(info.StartRevision, info.EndRevision)
+	dir := s.backupsBase.Join(RevisionsDir, info.EtcdClusterId, name)
+
+	if s.encryptionKey != nil {
+		encryption, dataKey, err := newEncryptionInfo(s.encryptionKey)
+		if err != nil {
+			return "", err
+		}
+		if err := s.writeEncryptionInfo(ctx, dir, encryption); err != nil {
+			return "", err
+		}
+
+		var encrypted bytes.Buffer
+		w, err := newEncryptingWriter(&encrypted, dataKey, encryption)
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write(data); err != nil {
+			return "", fmt.Errorf("error encrypting revision segment: %v", err)
+		}
+		if err := w.Close(); err != nil {
+			return "", fmt.Errorf("error encrypting revision segment: %v", err)
+		}
+		data = encrypted.Bytes()
+	}
+
+	sha, size, err := computeChecksum(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	info.DataSha256 = sha
+	info.DataSize = size
+
+	dataPath := dir.Join(revisionSegmentDataFile(codec))
+	if err := dataPath.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		return "", fmt.Errorf("error writing file %q: %v", dataPath, err)
+	}
+
+	meta, err := etcd.ToJson(info)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling segment info: %v", err)
+	}
+	metaPath := dir.Join(RevisionSegmentMetaFilename)
+	if err := metaPath.WriteFile(ctx, bytes.NewReader([]byte(meta)), nil); err != nil {
+		return "", fmt.Errorf("error writing file %q: %v", metaPath, err)
+	}
+
+	return name, nil
+}
+
+func (s *vfsStore) ListRevisionSegments(clusterID string) ([]string, error) {
+	if err := validateBackupName(clusterID); err != nil {
+		return nil, fmt.Errorf("invalid etcd cluster id: %v", err)
+	}
+
+	base := s.backupsBase.Join(RevisionsDir, clusterID)
+	files, err := base.ReadTree(context.TODO())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %v", base, err)
+	}
+
+	var segments []string
+	for _, f := range files {
+		if f.Base() != RevisionSegmentMetaFilename {
+			continue
+		}
+
+		tokens := strings.Split(f.Path(), "/")
+		if len(tokens) < 2 {
+			klog.Infof("skipping unexpectedly short path %q", f.Path())
+			continue
+		}
+		name := tokens[len(tokens)-2]
+		if _, _, err := ParseRevisionSegmentName(name); err != nil {
+			klog.Warningf("ignoring unexpected revision segment %q", name)
+			continue
+		}
+		segments = append(segments, name)
+	}
+
+	sort.Strings(segments)
+
+	return segments, nil
+}
+
+func (s *vfsStore) LoadRevisionSegmentInfo(clusterID string, name string) (*etcd.RevisionSegmentInfo, error) {
+	dir, err := s.revisionSegmentDir(clusterID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	p := dir.Join(RevisionSegmentMetaFilename)
+	data, err := p.ReadFile(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %q: %v", p, err)
+	}
+
+	info := &etcd.RevisionSegmentInfo{}
+	if err := etcd.FromJson(string(data), info); err != nil {
+		return nil, fmt.Errorf("error parsing file %q: %v", p, err)
+	}
+	return info, nil
+}
+
+func (s *vfsStore) ReadRevisionSegment(clusterID string, name string) (*etcd.RevisionSegmentInfo, []byte, error) {
+	dir, err := s.revisionSegmentDir(clusterID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := s.LoadRevisionSegmentInfo(clusterID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	codec, err := CodecFor(info.Compression)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read revision segment %q: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.readDataFile(context.TODO(), dir, revisionSegmentDataFile(codec), name, info.DataSha256, info.DataSize, &buf); err != nil {
+		return nil, nil, err
+	}
+	return info, buf.Bytes(), nil
+}
+
+func (s *vfsStore) RemoveRevisionSegment(clusterID string, name string) error {
+	p, err := s.revisionSegmentDir(clusterID, name)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	files, err := p.ReadTree(ctx)
+	if err != nil {
+		return fmt.Errorf("error deleting - cannot read %s: %v", p, err)
+	}
+
+	// Remove the meta file first, so a partially deleted segment is not listed
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Base() == RevisionSegmentMetaFilename && files[j].Base() != RevisionSegmentMetaFilename
+	})
+	for _, f := range files {
+		if err := f.RemoveAllVersions(ctx); err != nil {
+			return fmt.Errorf("error deleting revision segment in %q: %v", p, err)
+		}
+	}
+
+	return nil
+}
+
+// revisionSegmentDir returns the directory holding a segment, validating the cluster id and segment name
+func (s *vfsStore) revisionSegmentDir(clusterID string, name string) (vfs.Path, error) {
+	if err := validateBackupName(clusterID); err != nil {
+		return nil, fmt.Errorf("invalid etcd cluster id: %v", err)
+	}
+	if _, _, err := ParseRevisionSegmentName(name); err != nil {
+		return nil, err
+	}
+	return s.backupsBase.Join(RevisionsDir, clusterID, name), nil
+}
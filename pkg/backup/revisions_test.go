@@ -0,0 +1,191 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func TestParseRevisionSegmentName(t *testing.T) {
+	name := RevisionSegmentName(101, 2500)
+	if name != "0000000000000101-0000000000002500" {
+		t.Errorf("unexpected segment name %q", name)
+	}
+
+	start, end, err := ParseRevisionSegmentName(name)
+	if err != nil {
+		t.Fatalf("ParseRevisionSegmentName(%q) failed: %v", name, err)
+	}
+	if start != 101 || end != 2500 {
+		t.Errorf("ParseRevisionSegmentName(%q) returned %d-%d", name, start, end)
+	}
+
+	for _, invalid := range []string{"", "101-2500", "0000000000000101", "../0000000000000101-0000000000002500"} {
+		if _, _, err := ParseRevisionSegmentName(invalid); err == nil {
+			t.Errorf("expected error parsing %q", invalid)
+		}
+	}
+}
+
+func testRevisions() []*etcd.ArchivedRevision {
+	return []*etcd.ArchivedRevision{
+		{
+			Revision:  11,
+			Timestamp: 1700000000,
+			Events: []*etcd.ArchivedEvent{
+				{Key: []byte("/a"), Value: []byte("1")},
+				{Key: []byte("/b"), Value: []byte("2")},
+			},
+		},
+		{
+			Revision:  12,
+			Timestamp: 1700000005,
+			Events: []*etcd.ArchivedEvent{
+				{Key: []byte("/a"), Delete: true},
+			},
+		},
+	}
+}
+
+func TestRevisionSegmentRoundTrip(t *testing.T) {
+	revisions := testRevisions()
+
+	for _, name := range []string{CompressionGzip, CompressionZstd} {
+		codec, err := CodecFor(name)
+		if err != nil {
+			t.Fatalf("CodecFor(%q) failed: %v", name, err)
+		}
+
+		data, err := EncodeRevisionSegment(codec, revisions)
+		if err != nil {
+			t.Fatalf("%s: EncodeRevisionSegment failed: %v", name, err)
+		}
+
+		var decoded []*etcd.ArchivedRevision
+		if err := DecodeRevisionSegment(codec, data, func(revision *etcd.ArchivedRevision) error {
+			decoded = append(decoded, revision)
+			return nil
+		}); err != nil {
+			t.Fatalf("%s: DecodeRevisionSegment failed: %v", name, err)
+		}
+
+		if len(decoded) != len(revisions) {
+			t.Fatalf("%s: decoded %d revisions, expected %d", name, len(decoded), len(revisions))
+		}
+		for i := range revisions {
+			if !proto.Equal(decoded[i], revisions[i]) {
+				t.Errorf("%s: revision %d was %v, expected %v", name, i, decoded[i], revisions[i])
+			}
+		}
+	}
+}
+
+func TestVFSStoreRevisionSegments(t *testing.T) {
+	for _, encrypted := range []bool{false, true} {
+		var key *EncryptionKey
+		if encrypted {
+			key = newTestEncryptionKey(t)
+		}
+		store, dir := newTestVFSStore(t, key)
+		archive := store.(RevisionArchive)
+
+		codec := store.Codec()
+		data, err := EncodeRevisionSegment(codec, testRevisions())
+		if err != nil {
+			t.Fatalf("EncodeRevisionSegment failed: %v", err)
+		}
+
+		var names []string
+		for _, r := range [][2]int64{{21, 30}, {11, 20}} {
+			name, err := archive.AddRevisionSegment(&etcd.RevisionSegmentInfo{
+				EtcdClusterId: "cdf818194e3a8c32",
+				StartRevision: r[0],
+				EndRevision:   r[1],
+				Compression:   codec.Name(),
+			}, data)
+			if err != nil {
+				t.Fatalf("AddRevisionSegment failed: %v", err)
+			}
+			names = append(names, name)
+		}
+
+		segments, err := archive.ListRevisionSegments("cdf818194e3a8c32")
+		if err != nil {
+			t.Fatalf("ListRevisionSegments failed: %v", err)
+		}
+		expected := []string{names[1], names[0]}
+		if !reflect.DeepEqual(segments, expected) {
+			t.Errorf("ListRevisionSegments returned %v, expected %v", segments, expected)
+		}
+
+		if other, err := archive.ListRevisionSegments("1234"); err != nil || len(other) != 0 {
+			t.Errorf("expected no segments for another cluster, got %v (err=%v)", other, err)
+		}
+
+		backups, err := store.ListBackups()
+		if err != nil {
+			t.Fatalf("ListBackups failed: %v", err)
+		}
+		if len(backups) != 0 {
+			t.Errorf("expected revision segments not to be listed as backups, got %v", backups)
+		}
+
+		info, read, err := archive.ReadRevisionSegment("cdf818194e3a8c32", names[0])
+		if err != nil {
+			t.Fatalf("ReadRevisionSegment failed: %v", err)
+		}
+		if !bytes.Equal(read, data) {
+			t.Errorf("segment data did not match what was written")
+		}
+		if info.StartRevision != 21 || info.EndRevision != 30 {
+			t.Errorf("unexpected segment info %v", info)
+		}
+
+		// Corrupt the stored data
+		dataFile := filepath.Join(dir, RevisionsDir, "cdf818194e3a8c32", names[0], revisionSegmentDataFile(codec))
+		stored, err := os.ReadFile(dataFile)
+		if err != nil {
+			t.Fatalf("failed to read stored segment: %v", err)
+		}
+		stored[len(stored)/2] ^= 0xff
+		if err := os.WriteFile(dataFile, stored, 0600); err != nil {
+			t.Fatalf("failed to write stored segment: %v", err)
+		}
+		if _, _, err := archive.ReadRevisionSegment("cdf818194e3a8c32", names[0]); !errors.Is(err, ErrIntegrity) {
+			t.Errorf("expected integrity error reading corrupted segment, got %v", err)
+		}
+
+		if err := archive.RemoveRevisionSegment("cdf818194e3a8c32", names[0]); err != nil {
+			t.Fatalf("RemoveRevisionSegment failed: %v", err)
+		}
+		segments, err = archive.ListRevisionSegments("cdf818194e3a8c32")
+		if err != nil {
+			t.Fatalf("ListRevisionSegments failed: %v", err)
+		}
+		if !reflect.DeepEqual(segments, []string{names[1]}) {
+			t.Errorf("after remove, ListRevisionSegments returned %v", segments)
+		}
+	}
+}
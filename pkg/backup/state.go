@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// StateKeyFileEnv is the environment variable naming a local file holding the key for the cluster state archive.
+// The archive holds CA private keys, so it is always encrypted, with a key separate from the backup encryption key.
+const StateKeyFileEnv = "ETCD_MANAGER_BACKUP_STATE_KEY_FILE"
+
+// StateFilename holds the encrypted cluster state archived alongside a backup
+const StateFilename = "_etcd_backup.state"
+
+// StateEncryptionFilename holds the algorithm and wrapped data key for StateFilename
+const StateEncryptionFilename = "_etcd_backup.state.encryption"
+
+// LoadStateKeyFromEnv loads the key named by ETCD_MANAGER_BACKUP_STATE_KEY_FILE, returning nil if it is not set.
+func LoadStateKeyFromEnv() (*EncryptionKey, error) {
+	p := os.Getenv(StateKeyFileEnv)
+	if p == "" {
+		return nil, nil
+	}
+	return LoadEncryptionKey(p)
+}
+
+func (s *vfsStore) SaveState(name string, state *etcd.BackupState, key *EncryptionKey) error {
+	// LoadInfo validates the name, and checks that the backup exists
+	if _, err := s.LoadInfo(name); err != nil {
+		return fmt.Errorf("cannot save state for backup %q: %w", name, err)
+	}
+	if key == nil {
+		return fmt.Errorf("an encryption key is required to save the cluster state")
+	}
+
+	ctx := context.TODO()
+
+	data, err := etcd.ToJson(state)
+	if err != nil {
+		return fmt.Errorf("error marshalling cluster state: %v", err)
+	}
+
+	encryption, dataKey, err := newEncryptionInfo(key)
+	if err != nil {
+		return err
+	}
+	var encrypted bytes.Buffer
+	w, err := newEncryptingWriter(&encrypted, dataKey, encryption)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(data)); err != nil {
+		return fmt.Errorf("error encrypting cluster state: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error encrypting cluster state: %v", err)
+	}
+
+	// As for backup data, we write the wrapped key first, so that we never have a state we can't decrypt
+	encryptionData, err := encryption.toJSON()
+	if err != nil {
+		return fmt.Errorf("error marshalling encryption info: %v", err)
+	}
+	encryptionPath := s.backupsBase.Join(name, StateEncryptionFilename)
+	if err := encryptionPath.WriteFile(ctx, bytes.NewReader(encryptionData), nil); err != nil {
+		return fmt.Errorf("error writing file %q: %v", encryptionPath, err)
+	}
+
+	p := s.backupsBase.Join(name, StateFilename)
+	if err := p.WriteFile(ctx, bytes.NewReader(encrypted.Bytes()), nil); err != nil {
+		return fmt.Errorf("error writing file %q: %v", p, err)
+	}
+	return nil
+}
+
+func (s *vfsStore) LoadState(name string, key *EncryptionKey) (*etcd.BackupState, error) {
+	if err := validateBackupName(name); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+
+	p := s.backupsBase.Join(name, StateFilename)
+	encrypted, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading file %q: %v", p, err)
+	}
+
+	encryptionPath := s.backupsBase.Join(name, StateEncryptionFilename)
+	encryptionData, err := encryptionPath.ReadFile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %q: %v", encryptionPath, err)
+	}
+	encryption, err := parseEncryptionInfo(encryptionData)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing file %q: %v", encryptionPath, err)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("cluster state for backup %q is encrypted (key %s) but no key is configured (set %s)", name, encryption.KeyID, StateKeyFileEnv)
+	}
+	dataKey, err := encryption.unwrapDataKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting cluster state for backup %q: %w", name, err)
+	}
+
+	var data bytes.Buffer
+	w, err := newDecryptingWriter(&data, dataKey, encryption)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(encrypted); err != nil {
+		return nil, fmt.Errorf("error decrypting file %q: %w", p, err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("error decrypting file %q: %w", p, err)
+	}
+
+	state := &etcd.BackupState{}
+	if err := etcd.FromJson(data.String(), state); err != nil {
+		return nil, fmt.Errorf("error parsing file %q: %v", p, err)
+	}
+	return state, nil
+}
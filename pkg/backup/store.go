@@ -17,16 +17,37 @@ limitations under the License.
 package backup
 
 import (
+	"fmt"
+	"io"
+
 	"k8s.io/kops/util/pkg/vfs"
 	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
 )
 
 const MetaFilename = "_etcd_backup.meta"
+
+// DataFilename is the name of the data file for gzip compressed backups; see DataFilenameFor for other codecs
 const DataFilename = "etcd.backup.gz"
 
+// VerificationFilename holds the result of the last restore drill against a backup
+const VerificationFilename = "_etcd_backup.verification"
+
+// PinFilename marks a backup that retention cleanup must not remove
+const PinFilename = "_etcd_backup.pin"
+
+// AnomalyFilename flags a backup whose size or key count differs sharply from the preceding backups
+const AnomalyFilename = "_etcd_backup.anomaly"
+
+// EncryptionFilename holds the algorithm and wrapped data key for an encrypted backup
+const EncryptionFilename = "_etcd_backup.encryption"
+
 type Store interface {
 	Spec() string
 
+	// Codec returns the codec that new backups should be compressed with.
+	// The caller compresses the data, and records the codec name in BackupInfo.Compression.
+	Codec() Codec
+
 	// AddBackup adds a backup to the store, returning the name of the backup
 	AddBackup(backupFile string, sequence string, info *etcd.BackupInfo) (string, error)
 
@@ -39,11 +60,114 @@ type Store interface {
 	// LoadInfo loads the backup information that should have been saved alongside a backup
 	LoadInfo(backup string) (*etcd.BackupInfo, error)
 
-	// DownloadBackup downloads the backup to the specific file
+	// DownloadBackup downloads the backup to the specific file, decrypting it if needed.
+	// The data is verified against the checksum recorded in the backup info; a mismatch returns an error wrapping ErrIntegrity.
 	DownloadBackup(name string, destFile string) error
+
+	// VerifyBackup reads the backup data and checks it against the checksum recorded in the backup info
+	VerifyBackup(name string) error
+}
+
+// StreamingStore is implemented by stores that can accept backup data as a stream,
+// so that a backup does not have to be staged in a local file before it is added.
+type StreamingStore interface {
+	Store
+
+	// CanStream returns true if AddBackupFromStream is supported for this store's storage location
+	CanStream() bool
+
+	// AddBackupFromStream adds a backup to the store, with the data written by writeData, returning the name of the backup
+	AddBackupFromStream(writeData func(w io.Writer) error, sequence string, info *etcd.BackupInfo) (string, error)
+}
+
+// VerificationStore is implemented by stores that can record the results of restore drills alongside the backups
+type VerificationStore interface {
+	// SaveVerification records the result of a restore drill against a backup
+	SaveVerification(name string, verification *etcd.BackupVerification) error
+
+	// LoadVerification loads the recorded result of a restore drill against a backup, returning nil if it has not been drilled
+	LoadVerification(name string) (*etcd.BackupVerification, error)
+}
+
+// PinStore is implemented by stores that can pin backups, so that retention cleanup does not remove them
+type PinStore interface {
+	// PinBackup marks a backup so that retention cleanup will not remove it
+	PinBackup(name string, pin *etcd.BackupPin) error
+
+	// UnpinBackup removes the pin from a backup, if it is pinned
+	UnpinBackup(name string) error
+
+	// LoadPin loads the pin for a backup, returning nil if it is not pinned
+	LoadPin(name string) (*etcd.BackupPin, error)
+}
+
+// AnomalyStore is implemented by stores that can flag backups that differ sharply from the preceding backups
+type AnomalyStore interface {
+	// FlagAnomaly records that a backup differs sharply from the preceding backups
+	FlagAnomaly(name string, anomaly *etcd.BackupAnomaly) error
+
+	// ClearAnomaly removes the anomaly flag from a backup, if it is flagged
+	ClearAnomaly(name string) error
+
+	// LoadAnomaly loads the anomaly flag for a backup, returning nil if it is not flagged
+	LoadAnomaly(name string) (*etcd.BackupAnomaly, error)
 }
 
+// StateStore is implemented by stores that can archive the cluster state alongside the backups
+type StateStore interface {
+	// SaveState archives the cluster state alongside a backup, encrypted with key
+	SaveState(name string, state *etcd.BackupState, key *EncryptionKey) error
+
+	// LoadState loads the cluster state archived alongside a backup, decrypting it with key, returning nil if there is none
+	LoadState(name string, key *EncryptionKey) (*etcd.BackupState, error)
+}
+
+// RevisionArchive is implemented by stores that can hold archived etcd revisions alongside the backups,
+// which are replayed on top of a backup for point-in-time recovery.
+// Revisions are only meaningful within a single etcd cluster, so segments are grouped by etcd cluster ID.
+type RevisionArchive interface {
+	// AddRevisionSegment adds a segment of archived revisions, encoded with EncodeRevisionSegment, returning the name of the segment
+	AddRevisionSegment(info *etcd.RevisionSegmentInfo, data []byte) (string, error)
+
+	// ListRevisionSegments returns the names of the archived segments for a cluster, ordered by start revision
+	ListRevisionSegments(clusterID string) ([]string, error)
+
+	// LoadRevisionSegmentInfo loads the information saved alongside a segment
+	LoadRevisionSegmentInfo(clusterID string, name string) (*etcd.RevisionSegmentInfo, error)
+
+	// ReadRevisionSegment returns the info and (decrypted) data for a segment, verified against its checksum
+	ReadRevisionSegment(clusterID string, name string) (*etcd.RevisionSegmentInfo, []byte, error)
+
+	// RemoveRevisionSegment deletes a segment (as returned by ListRevisionSegments)
+	RemoveRevisionSegment(clusterID string, name string) error
+}
+
+// NewStore builds the store for a store spec.  A spec naming several locations, separated by StoreSeparator,
+// builds a replicated store, with the policy set by ReplicationPolicyEnv.
 func NewStore(storage string) (Store, error) {
+	locations := SplitStoreSpec(storage)
+	if len(locations) > 1 {
+		policy, err := ReplicationPolicyFromEnv()
+		if err != nil {
+			return nil, err
+		}
+
+		var stores []Store
+		for _, location := range locations {
+			store, err := newLocationStore(location)
+			if err != nil {
+				return nil, fmt.Errorf("error initializing backup store %q: %w", location, err)
+			}
+			stores = append(stores, store)
+		}
+		return NewReplicatedStore(stores, policy)
+	}
+
+	return newLocationStore(storage)
+}
+
+// newLocationStore builds the store for a single location
+func newLocationStore(storage string) (Store, error) {
 	//u, err := url.Parse(storage)
 	//if err != nil {
 	//	return nil, fmt.Errorf("error parsing storage url %q", storage)
@@ -61,5 +185,15 @@ func NewStore(storage string) (Store, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewVFSStore(p)
+
+	codec, err := CodecFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	encryptionKey, err := LoadEncryptionKeyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewVFSStore(p, codec, encryptionKey)
 }
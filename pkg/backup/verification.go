@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func (s *vfsStore) SaveVerification(name string, verification *etcd.BackupVerification) error {
+	if err := validateBackupName(name); err != nil {
+		return err
+	}
+
+	p := s.backupsBase.Join(name, VerificationFilename)
+
+	data, err := etcd.ToJson(verification)
+	if err != nil {
+		return fmt.Errorf("error marshalling verification: %v", err)
+	}
+
+	if err := p.WriteFile(context.TODO(), bytes.NewReader([]byte(data)), nil); err != nil {
+		return fmt.Errorf("error writing file %q: %v", p, err)
+	}
+	return nil
+}
+
+func (s *vfsStore) LoadVerification(name string) (*etcd.BackupVerification, error) {
+	if err := validateBackupName(name); err != nil {
+		return nil, err
+	}
+
+	p := s.backupsBase.Join(name, VerificationFilename)
+
+	data, err := p.ReadFile(context.TODO())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading file %q: %v", p, err)
+	}
+
+	verification := &etcd.BackupVerification{}
+	if err := etcd.FromJson(string(data), verification); err != nil {
+		return nil, fmt.Errorf("error parsing file %q: %v", p, err)
+	}
+	return verification, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func TestVFSStoreVerification(t *testing.T) {
+	s, _ := newTestVFSStore(t, nil)
+	store := s.(*vfsStore)
+	name := addTestBackup(t, store, []byte("backup data"))
+
+	verification, err := store.LoadVerification(name)
+	if err != nil {
+		t.Fatalf("LoadVerification failed: %v", err)
+	}
+	if verification != nil {
+		t.Errorf("expected no verification for a new backup, got %v", verification)
+	}
+
+	expected := &etcd.BackupVerification{
+		Timestamp: 1700000000,
+		Success:   true,
+		Revision:  42,
+		KeyCount:  10,
+		Hash:      1234,
+	}
+	if err := store.SaveVerification(name, expected); err != nil {
+		t.Fatalf("SaveVerification failed: %v", err)
+	}
+
+	verification, err = store.LoadVerification(name)
+	if err != nil {
+		t.Fatalf("LoadVerification failed: %v", err)
+	}
+	if !proto.Equal(verification, expected) {
+		t.Errorf("LoadVerification returned %v, expected %v", verification, expected)
+	}
+
+	// The verification is removed with the backup
+	if err := store.RemoveBackup(name); err != nil {
+		t.Fatalf("RemoveBackup failed: %v", err)
+	}
+	verification, err = store.LoadVerification(name)
+	if err != nil || verification != nil {
+		t.Errorf("expected no verification after remove, got %v (err=%v)", verification, err)
+	}
+}
@@ -20,10 +20,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -45,10 +47,18 @@ func validateBackupName(name string) error {
 	return nil
 }
 
-func NewVFSStore(p vfs.Path) (Store, error) {
+// NewVFSStore builds a Store backed by a vfs.Path.
+// New backups are compressed with codec (gzip if nil).
+// If encryptionKey is non-nil, new backups are encrypted with a random data key, wrapped by encryptionKey.
+func NewVFSStore(p vfs.Path, codec Codec, encryptionKey *EncryptionKey) (Store, error) {
+	if codec == nil {
+		codec = codecs[CompressionGzip]
+	}
 	s := &vfsStore{
-		spec:        p.Path(),
-		backupsBase: p,
+		spec:          p.Path(),
+		backupsBase:   p,
+		codec:         codec,
+		encryptionKey: encryptionKey,
 	}
 	return s, nil
 }
@@ -56,56 +66,326 @@ func NewVFSStore(p vfs.Path) (Store, error) {
 type vfsStore struct {
 	spec        string
 	backupsBase vfs.Path
+
+	// catalogMutex serializes our reads and writes of the catalog
+	catalogMutex sync.Mutex
+
+	// codec is the compression codec for new backups
+	codec Codec
+
+	// encryptionKey is the key-encryption key for backups, or nil if backups should not be encrypted
+	encryptionKey *EncryptionKey
 }
 
 var _ Store = &vfsStore{}
+var _ VerificationStore = &vfsStore{}
+var _ PinStore = &vfsStore{}
+var _ AnomalyStore = &vfsStore{}
+var _ StateStore = &vfsStore{}
+var _ StreamingStore = &vfsStore{}
+
+// newBackupName returns the name for a new backup, setting the timestamp in info if it is not already set.
+// The name is derived from the timestamp, so a backup written to several stores gets the same name in each.
+func newBackupName(sequence string, info *etcd.BackupInfo) string {
+	if info.Timestamp == 0 {
+		info.Timestamp = time.Now().Unix()
+	}
+
+	return time.Unix(info.Timestamp, 0).UTC().Format(time.RFC3339) + "-" + sequence
+}
 
 func (s *vfsStore) AddBackup(srcFile string, sequence string, info *etcd.BackupInfo) (string, error) {
 	ctx := context.TODO()
 
-	now := time.Now()
+	name := newBackupName(sequence, info)
 
-	if info.Timestamp == 0 {
-		info.Timestamp = now.Unix()
+	codec, err := CodecFor(info.Compression)
+	if err != nil {
+		return "", err
 	}
 
-	name := now.UTC().Format(time.RFC3339) + "-" + sequence
-
 	// Copy the backup file
 	if srcFile != "" {
+		if s.encryptionKey != nil {
+			encryptedFile, err := s.encryptBackupFile(ctx, name, srcFile)
+			if err != nil {
+				return "", err
+			}
+			defer func() {
+				if err := os.Remove(encryptedFile); err != nil {
+					klog.Warningf("error removing encrypted backup file %q: %v", encryptedFile, err)
+				}
+			}()
+			srcFile = encryptedFile
+		}
+
 		f, err := os.Open(srcFile)
 		if err != nil {
 			return "", fmt.Errorf("error opening %q: %v", srcFile, err)
 		}
 		defer f.Close()
 
-		destPath := s.backupsBase.Join(name).Join(DataFilename)
+		// Record the checksum of the data as stored, so we can detect corruption on download
+		sha, size, err := computeChecksum(f)
+		if err != nil {
+			return "", fmt.Errorf("error computing checksum of %q: %v", srcFile, err)
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return "", fmt.Errorf("error seeking %q: %v", srcFile, err)
+		}
+		info.DataSha256 = sha
+		info.DataSize = size
+
+		destPath := s.backupsBase.Join(name).Join(DataFilenameFor(codec))
 		err = destPath.WriteFile(ctx, f, nil)
 		if err != nil {
 			return "", fmt.Errorf("error copying %q to %q: %v", srcFile, destPath, err)
 		}
 	}
 
-	// Save the meta file
-	{
-		p := s.backupsBase.Join(name, MetaFilename)
+	if err := s.writeInfo(ctx, name, info); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
 
-		data, err := etcd.ToJson(info)
-		if err != nil {
-			return "", fmt.Errorf("error marshalling state: %v", err)
+// CanStream returns true if the backup store is on a local (or mounted) filesystem, or in GCS or AWS S3.
+// Other object stores need a seekable source for upload, so backups to them must be staged in a local file.
+func (s *vfsStore) CanStream() bool {
+	if _, ok := s.backupsBase.(*vfs.FSPath); ok {
+		return true
+	}
+	return streamUploaderFor(s.backupsBase) != nil
+}
+
+func (s *vfsStore) AddBackupFromStream(writeData func(w io.Writer) error, sequence string, info *etcd.BackupInfo) (string, error) {
+	ctx := context.TODO()
+
+	if !s.CanStream() {
+		return "", fmt.Errorf("backup store %s does not support streaming backups", s.spec)
+	}
+
+	name := newBackupName(sequence, info)
+
+	codec, err := CodecFor(info.Compression)
+	if err != nil {
+		return "", err
+	}
+
+	// The checksum covers the data as stored, so we hash after encrypting
+	checksum := newChecksumWriter()
+	writeStored := func(out io.Writer) error {
+		var w io.Writer = io.MultiWriter(out, checksum)
+
+		var encrypter io.WriteCloser
+		if s.encryptionKey != nil {
+			encryption, dataKey, err := newEncryptionInfo(s.encryptionKey)
+			if err != nil {
+				return err
+			}
+			if err := s.writeEncryptionInfo(ctx, s.backupsBase.Join(name), encryption); err != nil {
+				return err
+			}
+			encrypter, err = newEncryptingWriter(w, dataKey, encryption)
+			if err != nil {
+				return err
+			}
+			w = encrypter
 		}
 
-		err = p.WriteFile(ctx, bytes.NewReader([]byte(data)), nil)
-		if err != nil {
-			return "", fmt.Errorf("error writing file %q: %v", p, err)
+		if err := writeData(w); err != nil {
+			return err
+		}
+
+		if encrypter != nil {
+			if err := encrypter.Close(); err != nil {
+				return fmt.Errorf("error encrypting backup: %v", err)
+			}
 		}
+		return nil
+	}
+
+	dataPath := s.backupsBase.Join(name, DataFilenameFor(codec))
+	if _, ok := dataPath.(*vfs.FSPath); ok {
+		err = writeLocalFile(dataPath.Path(), writeStored)
+	} else {
+		err = uploadStream(ctx, dataPath, writeStored)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	info.DataSha256 = checksum.SHA256()
+	info.DataSize = checksum.size
+
+	if err := s.writeInfo(ctx, name, info); err != nil {
+		return "", err
 	}
 
 	return name, nil
 }
 
+// writeLocalFile writes the data written by write to destFile, through a temp file so that destFile is only created once complete
+func writeLocalFile(destFile string, write func(w io.Writer) error) error {
+	dir := path.Dir(destFile)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating directories %q: %v", dir, err)
+	}
+
+	f, err := os.CreateTemp(dir, "tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temp file in %q: %v", dir, err)
+	}
+	tempfile := f.Name()
+	defer func() {
+		if f != nil {
+			_ = f.Close()
+		}
+		if tempfile != "" {
+			_ = os.Remove(tempfile)
+		}
+	}()
+
+	if err := write(f); err != nil {
+		return err
+	}
+
+	err = f.Close()
+	f = nil
+	if err != nil {
+		return fmt.Errorf("error writing %q: %v", tempfile, err)
+	}
+
+	if err := os.Rename(tempfile, destFile); err != nil {
+		return fmt.Errorf("error during file write of %q: rename failed: %v", destFile, err)
+	}
+	tempfile = ""
+
+	return nil
+}
+
+// writeInfo saves the meta file for a backup, and records it in the catalog.
+// This should be written last, as it marks the backup as complete.
+func (s *vfsStore) writeInfo(ctx context.Context, name string, info *etcd.BackupInfo) error {
+	p := s.backupsBase.Join(name, MetaFilename)
+
+	data, err := etcd.ToJson(info)
+	if err != nil {
+		return fmt.Errorf("error marshalling state: %v", err)
+	}
+
+	err = p.WriteFile(ctx, bytes.NewReader([]byte(data)), nil)
+	if err != nil {
+		return fmt.Errorf("error writing file %q: %v", p, err)
+	}
+
+	s.addToCatalog(ctx, name, info)
+
+	return nil
+}
+
+// encryptBackupFile encrypts srcFile with a new data key, writing the wrapped key to the store.
+// It returns the path to the encrypted file, which the caller should remove.
+func (s *vfsStore) encryptBackupFile(ctx context.Context, name string, srcFile string) (string, error) {
+	info, dataKey, err := newEncryptionInfo(s.encryptionKey)
+	if err != nil {
+		return "", err
+	}
+
+	in, err := os.Open(srcFile)
+	if err != nil {
+		return "", fmt.Errorf("error opening %q: %v", srcFile, err)
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(path.Dir(srcFile), "encrypted")
+	if err != nil {
+		return "", fmt.Errorf("error creating temp file in %q: %v", path.Dir(srcFile), err)
+	}
+	encryptedFile := out.Name()
+	ok := false
+	defer func() {
+		_ = out.Close()
+		if !ok {
+			_ = os.Remove(encryptedFile)
+		}
+	}()
+
+	w, err := newEncryptingWriter(out, dataKey, info)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return "", fmt.Errorf("error encrypting %q: %v", srcFile, err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("error encrypting %q: %v", srcFile, err)
+	}
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("error writing %q: %v", encryptedFile, err)
+	}
+
+	if err := s.writeEncryptionInfo(ctx, s.backupsBase.Join(name), info); err != nil {
+		return "", err
+	}
+
+	ok = true
+	return encryptedFile, nil
+}
+
+// writeEncryptionInfo saves the wrapped data key for a backup (or revision segment) in dir.
+// We write this before the data, so that we never have data we can't decrypt.
+func (s *vfsStore) writeEncryptionInfo(ctx context.Context, dir vfs.Path, info *encryptionInfo) error {
+	data, err := info.toJSON()
+	if err != nil {
+		return fmt.Errorf("error marshalling encryption info: %v", err)
+	}
+	p := dir.Join(EncryptionFilename)
+	if err := p.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error writing file %q: %v", p, err)
+	}
+	return nil
+}
+
+// loadEncryptionInfo reads the encryption info for a backup (or revision segment) in dir, returning nil if it is not encrypted
+func (s *vfsStore) loadEncryptionInfo(ctx context.Context, dir vfs.Path) (*encryptionInfo, error) {
+	p := dir.Join(EncryptionFilename)
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading file %q: %v", p, err)
+	}
+
+	info, err := parseEncryptionInfo(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing file %q: %v", p, err)
+	}
+	return info, nil
+}
+
+// ListBackups returns the backups in the catalog, which is rebuilt from a full listing if it is missing or stale
 func (s *vfsStore) ListBackups() ([]string, error) {
-	files, err := s.backupsBase.ReadTree(context.TODO())
+	entries, err := s.ListCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		backups = append(backups, entry.Name)
+	}
+
+	klog.Infof("listed backups in %s: %v", s.backupsBase, backups)
+
+	return backups, nil
+}
+
+// listBackupsFromTree walks the store to find every backup
+func (s *vfsStore) listBackupsFromTree(ctx context.Context) ([]string, error) {
+	files, err := s.backupsBase.ReadTree(ctx)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -131,8 +411,6 @@ func (s *vfsStore) ListBackups() ([]string, error) {
 
 	sort.Strings(backups)
 
-	klog.Infof("listed backups in %s: %v", s.backupsBase, backups)
-
 	return backups, nil
 }
 
@@ -144,7 +422,7 @@ func (s *vfsStore) RemoveBackup(backup string) error {
 	ctx := context.TODO()
 	files, err := p.ReadTree(ctx)
 	if err != nil {
-		return fmt.Errorf("error deleting - cannot read %s: %v", p, err)
+		return fmt.Errorf("error deleting - cannot read %s: %w", p, err)
 	}
 
 	for _, f := range files {
@@ -154,6 +432,8 @@ func (s *vfsStore) RemoveBackup(backup string) error {
 		}
 	}
 
+	s.removeFromCatalog(ctx, backup)
+
 	return nil
 }
 
@@ -187,6 +467,10 @@ func (s *vfsStore) Spec() string {
 	return s.spec
 }
 
+func (s *vfsStore) Codec() Codec {
+	return s.codec
+}
+
 func (s *vfsStore) DownloadBackup(name string, destFile string) error {
 	if err := validateBackupName(name); err != nil {
 		return err
@@ -194,6 +478,8 @@ func (s *vfsStore) DownloadBackup(name string, destFile string) error {
 
 	klog.Infof("Downloading backup %q -> %s", name, destFile)
 
+	ctx := context.TODO()
+
 	dir := path.Dir(destFile)
 	err := os.MkdirAll(dir, 0755)
 	if err != nil {
@@ -214,9 +500,7 @@ func (s *vfsStore) DownloadBackup(name string, destFile string) error {
 		}
 	}()
 
-	srcPath := s.backupsBase.Join(name).Join(DataFilename)
-	_, err = srcPath.WriteTo(f)
-	if err != nil {
+	if err := s.readData(ctx, name, f); err != nil {
 		return err
 	}
 
@@ -234,3 +518,68 @@ func (s *vfsStore) DownloadBackup(name string, destFile string) error {
 
 	return nil
 }
+
+func (s *vfsStore) VerifyBackup(name string) error {
+	if err := validateBackupName(name); err != nil {
+		return err
+	}
+
+	klog.Infof("Verifying backup %q", name)
+
+	return s.readData(context.TODO(), name, io.Discard)
+}
+
+// readData copies the (decrypted) data for a backup to w, verifying it against the checksum in the backup info
+func (s *vfsStore) readData(ctx context.Context, name string, w io.Writer) error {
+	info, err := s.LoadInfo(name)
+	if err != nil {
+		return err
+	}
+
+	codec, err := CodecFor(info.Compression)
+	if err != nil {
+		return fmt.Errorf("unable to read backup %q: %w", name, err)
+	}
+
+	return s.readDataFile(ctx, s.backupsBase.Join(name), DataFilenameFor(codec), name, info.DataSha256, info.DataSize, w)
+}
+
+// readDataFile copies the (decrypted) contents of dataFilename in dir to w, verifying it against the expected checksum
+func (s *vfsStore) readDataFile(ctx context.Context, dir vfs.Path, dataFilename string, name string, expectedSha256 string, expectedSize int64, w io.Writer) error {
+	encryption, err := s.loadEncryptionInfo(ctx, dir)
+	if err != nil {
+		return err
+	}
+
+	var decrypter io.WriteCloser
+	if encryption != nil {
+		dataKey, err := encryption.unwrapDataKey(s.encryptionKey)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt backup %q: %w", name, err)
+		}
+		decrypter, err = newDecryptingWriter(w, dataKey, encryption)
+		if err != nil {
+			return err
+		}
+		w = decrypter
+	}
+
+	// The checksum covers the data as stored, so we hash before decrypting
+	checksum := newChecksumWriter()
+	srcPath := dir.Join(dataFilename)
+	if _, err := srcPath.WriteTo(io.MultiWriter(checksum, w)); err != nil {
+		return fmt.Errorf("error reading %q: %w", srcPath, err)
+	}
+
+	if err := checksum.verify(name, expectedSha256, expectedSize); err != nil {
+		return err
+	}
+
+	if decrypter != nil {
+		if err := decrypter.Close(); err != nil {
+			return fmt.Errorf("error decrypting backup %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// newTestVFSStore builds a vfsStore in a temp directory, returning the store and the directory holding backups
+func newTestVFSStore(t *testing.T, encryptionKey *EncryptionKey) (Store, string) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	p, err := vfs.Context.BuildVfsPath(dir)
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := NewVFSStore(p, nil, encryptionKey)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+	return store, dir
+}
+
+// addTestBackup adds a backup holding data to the store
+func addTestBackup(t *testing.T, store Store, data []byte) string {
+	srcFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+	if err := os.WriteFile(srcFile, data, 0600); err != nil {
+		t.Fatalf("failed to write source: %v", err)
+	}
+	name, err := store.AddBackup(srcFile, "000001", &etcd.BackupInfo{EtcdVersion: "3.5.0"})
+	if err != nil {
+		t.Fatalf("AddBackup failed: %v", err)
+	}
+	return name
+}
+
+func TestVFSStoreChecksum(t *testing.T) {
+	for _, encrypted := range []bool{false, true} {
+		var key *EncryptionKey
+		if encrypted {
+			key = newTestEncryptionKey(t)
+		}
+		store, dir := newTestVFSStore(t, key)
+
+		data := bytes.Repeat([]byte("etcd"), 50000)
+		name := addTestBackup(t, store, data)
+
+		info, err := store.LoadInfo(name)
+		if err != nil {
+			t.Fatalf("LoadInfo failed: %v", err)
+		}
+		if info.DataSha256 == "" || info.DataSize == 0 {
+			t.Fatalf("expected checksum to be recorded, got %v", info)
+		}
+
+		if err := store.VerifyBackup(name); err != nil {
+			t.Errorf("VerifyBackup of good backup failed: %v", err)
+		}
+
+		// Flip a byte in the stored data
+		dataFile := filepath.Join(dir, name, DataFilename)
+		stored, err := os.ReadFile(dataFile)
+		if err != nil {
+			t.Fatalf("failed to read stored data: %v", err)
+		}
+		stored[len(stored)/2] ^= 0xff
+		if err := os.WriteFile(dataFile, stored, 0600); err != nil {
+			t.Fatalf("failed to write stored data: %v", err)
+		}
+
+		if err := store.VerifyBackup(name); !errors.Is(err, ErrIntegrity) {
+			t.Errorf("expected integrity error from VerifyBackup, got %v", err)
+		}
+		destFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+		if err := store.DownloadBackup(name, destFile); !errors.Is(err, ErrIntegrity) {
+			t.Errorf("expected integrity error from DownloadBackup, got %v", err)
+		}
+		if _, err := os.Stat(destFile); !os.IsNotExist(err) {
+			t.Errorf("expected no file to be written for corrupted backup, got %v", err)
+		}
+
+		// Truncate the stored data
+		if err := os.WriteFile(dataFile, stored[:len(stored)/2], 0600); err != nil {
+			t.Fatalf("failed to write stored data: %v", err)
+		}
+		if err := store.VerifyBackup(name); !errors.Is(err, ErrIntegrity) {
+			t.Errorf("expected integrity error for truncated backup, got %v", err)
+		}
+	}
+}
+
+func TestVFSStoreWithoutChecksum(t *testing.T) {
+	store, dir := newTestVFSStore(t, nil)
+
+	data := []byte("backup from an older etcd-manager")
+	name := addTestBackup(t, store, data)
+
+	// Rewrite the meta file as an older version would have written it
+	meta, err := etcd.ToJson(&etcd.BackupInfo{EtcdVersion: "3.5.0", Timestamp: 1})
+	if err != nil {
+		t.Fatalf("ToJson failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name, MetaFilename), []byte(meta), 0600); err != nil {
+		t.Fatalf("failed to write meta: %v", err)
+	}
+
+	destFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+	if err := store.DownloadBackup(name, destFile); err != nil {
+		t.Fatalf("DownloadBackup failed: %v", err)
+	}
+	downloaded, err := os.ReadFile(destFile)
+	if err != nil {
+		t.Fatalf("failed to read download: %v", err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Errorf("downloaded data did not match")
+	}
+}
+
+func TestVFSStoreAddBackupFromStream(t *testing.T) {
+	for _, encrypted := range []bool{false, true} {
+		var key *EncryptionKey
+		if encrypted {
+			key = newTestEncryptionKey(t)
+		}
+		s, _ := newTestVFSStore(t, key)
+		store := s.(StreamingStore)
+
+		if !store.CanStream() {
+			t.Fatalf("expected filesystem store to support streaming")
+		}
+
+		data := bytes.Repeat([]byte("stream"), 100000)
+		name, err := store.AddBackupFromStream(func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		}, "000001", &etcd.BackupInfo{EtcdVersion: "3.5.0"})
+		if err != nil {
+			t.Fatalf("AddBackupFromStream failed: %v", err)
+		}
+
+		info, err := store.LoadInfo(name)
+		if err != nil {
+			t.Fatalf("LoadInfo failed: %v", err)
+		}
+		if info.DataSha256 == "" || info.DataSize == 0 {
+			t.Errorf("expected checksum to be recorded, got %v", info)
+		}
+
+		destFile := filepath.Join(t.TempDir(), "snapshot.db.gz")
+		if err := store.DownloadBackup(name, destFile); err != nil {
+			t.Fatalf("DownloadBackup failed: %v", err)
+		}
+		downloaded, err := os.ReadFile(destFile)
+		if err != nil {
+			t.Fatalf("failed to read download: %v", err)
+		}
+		if !bytes.Equal(downloaded, data) {
+			t.Errorf("downloaded data did not match")
+		}
+	}
+}
+
+func TestVFSStoreAddBackupFromStreamFailure(t *testing.T) {
+	s, _ := newTestVFSStore(t, nil)
+	store := s.(StreamingStore)
+
+	_, err := store.AddBackupFromStream(func(w io.Writer) error {
+		if _, err := w.Write([]byte("partial")); err != nil {
+			return err
+		}
+		return errors.New("snapshot failed")
+	}, "000001", &etcd.BackupInfo{EtcdVersion: "3.5.0"})
+	if err == nil {
+		t.Fatalf("expected error from AddBackupFromStream")
+	}
+
+	backups, err := store.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 0 {
+		t.Errorf("expected no backups after failed stream, got %v", backups)
+	}
+}
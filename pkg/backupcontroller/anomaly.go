@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+// AnomalyThresholdEnv is the fraction by which a backup's size or key count may differ from the baseline before it is flagged.
+// Setting it to 0 disables anomaly detection.
+const AnomalyThresholdEnv = "ETCD_MANAGER_BACKUP_ANOMALY_THRESHOLD"
+
+// defaultAnomalyThreshold flags backups less than half, or more than one and a half times, the baseline
+const defaultAnomalyThreshold = 0.5
+
+const (
+	// anomalyBaselineSize is how many of the preceding (unflagged) backups the baseline is taken from
+	anomalyBaselineSize = 5
+	// anomalyMinBaseline is how many preceding backups we need before we flag anything
+	anomalyMinBaseline = 3
+)
+
+// AnomalyDetector compares each new backup with the preceding backups, and flags backups whose data size or key count
+// differs sharply from them.  Flagged backups are not used for the baseline, so a run of bad backups stays flagged.
+type AnomalyDetector struct {
+	backupStore backup.Store
+	anomalies   backup.AnomalyStore
+	threshold   float64
+}
+
+// NewAnomalyDetector builds an AnomalyDetector, with the threshold from ETCD_MANAGER_BACKUP_ANOMALY_THRESHOLD
+func NewAnomalyDetector(backupStore backup.Store) (*AnomalyDetector, error) {
+	threshold := defaultAnomalyThreshold
+	if s := os.Getenv(AnomalyThresholdEnv); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid %s=%q, expected a non-negative fraction such as 0.5", AnomalyThresholdEnv, s)
+		}
+		threshold = v
+	}
+	anomalies, ok := backupStore.(backup.AnomalyStore)
+	if !ok && threshold != 0 {
+		klog.Warningf("backup store %s does not support flagging anomalies, disabling anomaly detection", backupStore.Spec())
+		threshold = 0
+	}
+	RegisterAnomalyMetrics()
+	return &AnomalyDetector{
+		backupStore: backupStore,
+		anomalies:   anomalies,
+		threshold:   threshold,
+	}, nil
+}
+
+// CheckBackup compares the named backup with the baseline, flagging it in the store if it is anomalous.
+// It returns the anomaly, or nil if the backup is in line with the baseline (or there is not yet a baseline).
+func (d *AnomalyDetector) CheckBackup(name string) (*protoetcd.BackupAnomaly, error) {
+	if d.threshold == 0 {
+		return nil, nil
+	}
+
+	entries, err := backup.ListEntries(d.backupStore)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *protoetcd.BackupCatalogEntry
+	var baseline []*protoetcd.BackupCatalogEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Name == name {
+			current = entry
+			continue
+		}
+		if current == nil || len(baseline) >= anomalyBaselineSize {
+			continue
+		}
+		if entry.Anomaly != nil || entry.Info.GetDataSize() == 0 {
+			continue
+		}
+		baseline = append(baseline, entry)
+	}
+	if current == nil {
+		return nil, fmt.Errorf("backup %q not found", name)
+	}
+	if current.Info == nil {
+		return nil, fmt.Errorf("backup %q has no info", name)
+	}
+
+	anomaly := d.compare(current.Info, baseline)
+	if anomaly == nil {
+		return nil, nil
+	}
+
+	klog.Warningf("backup %q is anomalous: %s", name, anomaly.Reason)
+	backupAnomalies.Inc()
+	if err := d.anomalies.FlagAnomaly(name, anomaly); err != nil {
+		return anomaly, fmt.Errorf("error flagging anomalous backup %q: %w", name, err)
+	}
+	return anomaly, nil
+}
+
+// compare returns an anomaly if info differs from the baseline by more than the threshold
+func (d *AnomalyDetector) compare(info *protoetcd.BackupInfo, baseline []*protoetcd.BackupCatalogEntry) *protoetcd.BackupAnomaly {
+	var dataSizes, keyCounts []int64
+	for _, entry := range baseline {
+		dataSizes = append(dataSizes, entry.Info.GetDataSize())
+		if entry.Info.GetKeyCount() != 0 {
+			keyCounts = append(keyCounts, entry.Info.GetKeyCount())
+		}
+	}
+
+	anomaly := &protoetcd.BackupAnomaly{
+		Timestamp: time.Now().Unix(),
+		DataSize:  info.DataSize,
+		KeyCount:  info.KeyCount,
+	}
+	var reasons []string
+	if len(dataSizes) >= anomalyMinBaseline && info.DataSize != 0 {
+		anomaly.BaselineDataSize = median(dataSizes)
+		if reason := d.outside("data size", info.DataSize, anomaly.BaselineDataSize); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(keyCounts) >= anomalyMinBaseline && info.KeyCount != 0 {
+		anomaly.BaselineKeyCount = median(keyCounts)
+		if reason := d.outside("key count", info.KeyCount, anomaly.BaselineKeyCount); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	anomaly.Reason = strings.Join(reasons, "; ")
+	return anomaly
+}
+
+// outside describes how value differs from baseline, or returns "" if it is within the threshold
+func (d *AnomalyDetector) outside(what string, value, baseline int64) string {
+	if baseline == 0 {
+		return ""
+	}
+	ratio := float64(value) / float64(baseline)
+	if ratio >= 1-d.threshold && ratio <= 1+d.threshold {
+		return ""
+	}
+	return fmt.Sprintf("%s %d is %.0f%% of the baseline %d", what, value, ratio*100, baseline)
+}
+
+func median(values []int64) int64 {
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// lastKnownGood returns the newest backup that is not flagged as anomalous, if newer backups are flagged.
+// It returns "" if the newest backup is not flagged, or if every backup is flagged.
+func lastKnownGood(entries []*protoetcd.BackupCatalogEntry) string {
+	if len(entries) == 0 || entries[len(entries)-1].Anomaly == nil {
+		return ""
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Anomaly == nil {
+			return entries[i].Name
+		}
+	}
+	return ""
+}
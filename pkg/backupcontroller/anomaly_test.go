@@ -0,0 +1,184 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+func newTestBackupStore(t *testing.T) backup.Store {
+	p, err := vfs.Context.BuildVfsPath(filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := backup.NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+	return store
+}
+
+func TestAnomalyDetectorCheckBackup(t *testing.T) {
+	store := newTestBackupStore(t)
+	detector, err := NewAnomalyDetector(store)
+	if err != nil {
+		t.Fatalf("NewAnomalyDetector failed: %v", err)
+	}
+
+	start := time.Now().Add(-24 * time.Hour)
+	grid := []struct {
+		DataSize int64
+		KeyCount int64
+		Reason   string
+	}{
+		// Until we have a baseline, nothing is flagged
+		{DataSize: 1000, KeyCount: 100},
+		{DataSize: 10, KeyCount: 100},
+		{DataSize: 1100, KeyCount: 110},
+		{DataSize: 1000, KeyCount: 100},
+		{DataSize: 1200, KeyCount: 120},
+		// A near-empty backup is flagged
+		{DataSize: 10, KeyCount: 1, Reason: "data size 10 is 1% of the baseline 1000; key count 1 is 1% of the baseline 100"},
+		// ... and stays flagged, because flagged backups aren't part of the baseline
+		{DataSize: 12, KeyCount: 1, Reason: "data size 12 is 1% of the baseline 1000"},
+		// Growth is flagged too
+		{DataSize: 1100, KeyCount: 400, Reason: "key count 400 is 400% of the baseline 100"},
+		{DataSize: 1100, KeyCount: 110},
+	}
+	for i, g := range grid {
+		info := &protoetcd.BackupInfo{EtcdVersion: "3.5.0", Timestamp: start.Add(time.Duration(i) * time.Hour).Unix(), DataSize: g.DataSize, KeyCount: g.KeyCount}
+		name, err := store.AddBackup("", "000001", info)
+		if err != nil {
+			t.Fatalf("AddBackup failed: %v", err)
+		}
+
+		anomaly, err := detector.CheckBackup(name)
+		if err != nil {
+			t.Fatalf("CheckBackup failed: %v", err)
+		}
+		stored, err := store.(backup.AnomalyStore).LoadAnomaly(name)
+		if err != nil {
+			t.Fatalf("LoadAnomaly failed: %v", err)
+		}
+		if g.Reason == "" {
+			if anomaly != nil || stored != nil {
+				t.Errorf("backup %d: expected no anomaly, got %v", i, anomaly)
+			}
+			continue
+		}
+		if anomaly == nil || stored == nil {
+			t.Fatalf("backup %d: expected anomaly %q, got none", i, g.Reason)
+		}
+		if !strings.HasPrefix(anomaly.Reason, g.Reason) || stored.Reason != anomaly.Reason {
+			t.Errorf("backup %d: expected anomaly %q, got %q (stored %q)", i, g.Reason, anomaly.Reason, stored.Reason)
+		}
+	}
+
+	if _, err := detector.CheckBackup("2000-01-01T00:00:00Z-000001"); err == nil {
+		t.Errorf("expected error checking a backup that does not exist")
+	}
+}
+
+func TestAnomalyDetectorThreshold(t *testing.T) {
+	store := newTestBackupStore(t)
+
+	t.Setenv(AnomalyThresholdEnv, "not-a-number")
+	if _, err := NewAnomalyDetector(store); err == nil {
+		t.Errorf("expected an invalid threshold to be rejected")
+	}
+
+	t.Setenv(AnomalyThresholdEnv, "0")
+	detector, err := NewAnomalyDetector(store)
+	if err != nil {
+		t.Fatalf("NewAnomalyDetector failed: %v", err)
+	}
+	start := time.Now().Add(-24 * time.Hour)
+	for i, size := range []int64{1000, 1000, 1000, 1} {
+		info := &protoetcd.BackupInfo{EtcdVersion: "3.5.0", Timestamp: start.Add(time.Duration(i) * time.Hour).Unix(), DataSize: size}
+		name, err := store.AddBackup("", "000001", info)
+		if err != nil {
+			t.Fatalf("AddBackup failed: %v", err)
+		}
+		if anomaly, err := detector.CheckBackup(name); err != nil || anomaly != nil {
+			t.Errorf("expected detection to be disabled, got %v (err=%v)", anomaly, err)
+		}
+	}
+}
+
+func TestBackupCleanupKeepsLastKnownGood(t *testing.T) {
+	store := newTestBackupStore(t)
+
+	now := time.Now()
+	var names []string
+	for i := 4; i >= 0; i-- {
+		info := &protoetcd.BackupInfo{EtcdVersion: "3.5.0", Timestamp: now.Add(-time.Duration(i) * 2 * time.Hour).Unix()}
+		name, err := store.AddBackup("", "000001", info)
+		if err != nil {
+			t.Fatalf("AddBackup failed: %v", err)
+		}
+		names = append(names, name)
+	}
+
+	policyFile := filepath.Join(t.TempDir(), "retention.json")
+	if err := os.WriteFile(policyFile, []byte(`{"keepLast": 1}`), 0600); err != nil {
+		t.Fatalf("failed to write retention policy: %v", err)
+	}
+	t.Setenv(RetentionPolicyFileEnv, policyFile)
+
+	// An older anomaly doesn't stop cleanup
+	if err := store.(backup.AnomalyStore).FlagAnomaly(names[1], &protoetcd.BackupAnomaly{Reason: "small"}); err != nil {
+		t.Fatalf("FlagAnomaly failed: %v", err)
+	}
+	cleanup := NewBackupCleanup(store)
+	wouldRemove, err := cleanup.Prune(t.Context(), true)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if expected := names[:4]; !reflect.DeepEqual(wouldRemove, expected) {
+		t.Errorf("would remove %v, expected %v", wouldRemove, expected)
+	}
+
+	// While the newest backups are flagged, we keep the last known-good backup
+	for _, name := range names[3:] {
+		if err := store.(backup.AnomalyStore).FlagAnomaly(name, &protoetcd.BackupAnomaly{Reason: "small"}); err != nil {
+			t.Fatalf("FlagAnomaly failed: %v", err)
+		}
+	}
+	removed, err := cleanup.Prune(t.Context(), false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if expected := []string{names[0], names[1], names[3]}; !reflect.DeepEqual(removed, expected) {
+		t.Errorf("removed %v, expected %v", removed, expected)
+	}
+	backups, err := store.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if expected := []string{names[2], names[4]}; !reflect.DeepEqual(backups, expected) {
+		t.Errorf("after prune, backups were %v, expected %v", backups, expected)
+	}
+}
@@ -0,0 +1,378 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/klog/v2"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+	"sigs.k8s.io/etcd-manager/pkg/contextutil"
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+// RevisionArchiveRetention controls how long archived revisions are kept.
+var RevisionArchiveRetention = 24 * 7 * time.Hour
+
+// maxPendingSegmentSize is the size of pending changes at which we write a segment early
+const maxPendingSegmentSize = 64 * 1024 * 1024
+
+// maxCachedLeases bounds the number of lease TTLs we remember while archiving
+const maxCachedLeases = 10000
+
+// maxPendingSize is the size of pending changes at which, if we still cannot write them, we stop archiving.
+// We drop the pending changes, and resume from the last segment in the store on the next iteration,
+// rather than holding an unbounded backlog in memory.
+const maxPendingSize = 4 * maxPendingSegmentSize
+
+func init() {
+	if s := os.Getenv("ETCD_MANAGER_REVISION_ARCHIVE_RETENTION"); s != "" {
+		v, err := ParseHumanDuration(s)
+		if err != nil {
+			klog.Fatalf("failed to parse ETCD_MANAGER_REVISION_ARCHIVE_RETENTION=%q", s)
+		}
+		RevisionArchiveRetention = v
+	}
+}
+
+// RevisionArchiver continuously archives the changes made to etcd into the backup store,
+// so that a backup can be rolled forward to a point in time after it was taken.
+// Changes are written as segments of consecutive revisions, every segmentInterval.
+type RevisionArchiver struct {
+	archive backup.RevisionArchive
+	codec   backup.Codec
+
+	clientUrls          []string
+	etcdClientTLSConfig *tls.Config
+
+	segmentInterval time.Duration
+
+	// lastPrune is the time at which we last removed expired segments
+	lastPrune time.Time
+}
+
+func NewRevisionArchiver(backupStore backup.Store, clientUrls []string, etcdClientTLSConfig *tls.Config, segmentInterval time.Duration) (*RevisionArchiver, error) {
+	archive, ok := backupStore.(backup.RevisionArchive)
+	if !ok {
+		return nil, fmt.Errorf("backup store %s does not support archiving revisions", backupStore.Spec())
+	}
+	if segmentInterval <= 0 {
+		return nil, fmt.Errorf("segment interval must be positive")
+	}
+
+	a := &RevisionArchiver{
+		archive:             archive,
+		codec:               backupStore.Codec(),
+		clientUrls:          clientUrls,
+		etcdClientTLSConfig: etcdClientTLSConfig,
+		segmentInterval:     segmentInterval,
+	}
+	return a, nil
+}
+
+func (a *RevisionArchiver) Run(ctx context.Context) {
+	contextutil.Forever(ctx,
+		loopInterval, // We do our own sleeping
+		func() {
+			err := a.run(ctx)
+			if err != nil {
+				klog.Warningf("unexpected error running revision archiver loop: %v", err)
+			}
+		})
+}
+
+func (a *RevisionArchiver) run(ctx context.Context) error {
+	klog.V(2).Infof("starting revision archiver iteration")
+
+	etcdClient, err := etcdclient.NewClient(a.clientUrls, a.etcdClientTLSConfig)
+	if err != nil {
+		return fmt.Errorf("unable to reach etcd on %s: %v", a.clientUrls, err)
+	}
+	defer etcdclient.LoggedClose(etcdClient)
+
+	self, err := etcdClient.LocalNodeInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get node state on %s: %v", a.clientUrls, err)
+	}
+	if !self.IsLeader {
+		klog.V(2).Infof("Not leader, won't archive revisions")
+		return nil
+	}
+
+	clusterID, err := etcdClient.ClusterID(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get cluster id on %s: %v", a.clientUrls, err)
+	}
+
+	currentRevision, err := etcdClient.CurrentRevision(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get current revision on %s: %v", a.clientUrls, err)
+	}
+
+	startRevision, err := a.resumeRevision(clusterID, currentRevision)
+	if err != nil {
+		return err
+	}
+
+	err = a.archiveFrom(ctx, etcdClient, clusterID, startRevision)
+	if errors.Is(err, etcdclient.ErrCompacted) {
+		klog.Warningf("revisions from %d were compacted before they were archived; point-in-time recovery is not possible between revisions %d and %d", startRevision, startRevision, currentRevision)
+		return a.archiveFrom(ctx, etcdClient, clusterID, currentRevision+1)
+	}
+	return err
+}
+
+// resumeRevision returns the revision from which we should archive, continuing on from the last archived segment
+func (a *RevisionArchiver) resumeRevision(clusterID string, currentRevision int64) (int64, error) {
+	segments, err := a.archive.ListRevisionSegments(clusterID)
+	if err != nil {
+		return 0, fmt.Errorf("error listing revision segments: %v", err)
+	}
+
+	if len(segments) == 0 {
+		klog.Infof("no archived revisions found for cluster %s, archiving from revision %d", clusterID, currentRevision+1)
+		return currentRevision + 1, nil
+	}
+
+	_, endRevision, err := backup.ParseRevisionSegmentName(segments[len(segments)-1])
+	if err != nil {
+		return 0, err
+	}
+	if endRevision > currentRevision {
+		return 0, fmt.Errorf("archived revisions for cluster %s end at %d, which is after the current revision %d", clusterID, endRevision, currentRevision)
+	}
+	return endRevision + 1, nil
+}
+
+// archiveFrom watches etcd from startRevision, writing segments until we are no longer the leader or the watch fails
+func (a *RevisionArchiver) archiveFrom(ctx context.Context, etcdClient *etcdclient.EtcdClient, clusterID string, startRevision int64) error {
+	klog.Infof("archiving revisions for cluster %s from revision %d", clusterID, startRevision)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	segment := &pendingSegment{
+		clusterID:     clusterID,
+		startRevision: startRevision,
+	}
+
+	// Keys attached to a lease (such as kubernetes events) are archived with the lease's TTL, so that replay can
+	// attach them to a new lease rather than restoring them without an expiry; we look up each lease once.
+	leaseTTLs := make(map[int64]int64)
+	leaseTTL := func(lease int64) int64 {
+		if ttl, found := leaseTTLs[lease]; found {
+			return ttl
+		}
+		ttl, err := etcdClient.LeaseTTL(ctx, lease)
+		if err != nil {
+			klog.Warningf("unable to get TTL of lease %x, archiving its keys as if it had expired: %v", lease, err)
+			return 0
+		}
+		if len(leaseTTLs) >= maxCachedLeases {
+			leaseTTLs = make(map[int64]int64)
+		}
+		leaseTTLs[lease] = ttl
+		return ttl
+	}
+
+	changes := etcdClient.WatchAll(ctx, startRevision)
+
+	ticker := time.NewTicker(a.segmentInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case batch, ok := <-changes:
+			if !ok {
+				// Our context was cancelled; we try to write what we have so it isn't lost
+				return a.writeSegment(segment)
+			}
+			if batch.Err != nil {
+				if err := a.writeSegment(segment); err != nil {
+					klog.Warningf("error writing revision segment: %v", err)
+				}
+				return batch.Err
+			}
+			segment.add(batch.Changes, time.Now(), leaseTTL)
+			if segment.size >= maxPendingSegmentSize {
+				if err := a.flushSegment(segment); err != nil {
+					return err
+				}
+			}
+
+		case <-ticker.C:
+			if err := a.flushSegment(segment); err != nil {
+				return err
+			}
+
+			if err := a.maybePruneSegments(clusterID); err != nil {
+				klog.Warningf("error removing expired revision segments: %v", err)
+			}
+
+			self, err := etcdClient.LocalNodeInfo(ctx)
+			if err != nil {
+				return fmt.Errorf("unable to get node state on %s: %v", a.clientUrls, err)
+			}
+			if !self.IsLeader {
+				klog.Infof("no longer leader, stopping revision archiving")
+				return a.writeSegment(segment)
+			}
+		}
+	}
+}
+
+// flushSegment writes the pending revisions to the backup store, keeping them pending if the write fails.
+// It only returns an error once the pending revisions have grown past maxPendingSize.
+func (a *RevisionArchiver) flushSegment(segment *pendingSegment) error {
+	err := a.writeSegment(segment)
+	if err == nil {
+		return nil
+	}
+	if segment.size >= maxPendingSize {
+		return fmt.Errorf("unable to write %d bytes of pending revisions (from revision %d): %w", segment.size, segment.startRevision, err)
+	}
+	klog.Warningf("error writing revision segment: %v", err)
+	return nil
+}
+
+// writeSegment writes the pending revisions to the backup store.
+// On failure the revisions remain pending, so we will retry them with the next segment.
+func (a *RevisionArchiver) writeSegment(segment *pendingSegment) error {
+	if len(segment.revisions) == 0 {
+		return nil
+	}
+
+	data, err := backup.EncodeRevisionSegment(a.codec, segment.revisions)
+	if err != nil {
+		return err
+	}
+
+	info := segment.info()
+	info.Compression = a.codec.Name()
+	name, err := a.archive.AddRevisionSegment(info, data)
+	if err != nil {
+		return fmt.Errorf("error adding revision segment: %w", err)
+	}
+	klog.V(2).Infof("archived revision segment %s (%d revisions)", name, len(segment.revisions))
+
+	segment.reset(info.EndRevision + 1)
+	return nil
+}
+
+// maybePruneSegments removes segments older than RevisionArchiveRetention, at most once an hour
+func (a *RevisionArchiver) maybePruneSegments(clusterID string) error {
+	now := time.Now()
+	if now.Sub(a.lastPrune) < time.Hour {
+		return nil
+	}
+	a.lastPrune = now
+
+	segments, err := a.archive.ListRevisionSegments(clusterID)
+	if err != nil {
+		return fmt.Errorf("error listing revision segments: %v", err)
+	}
+
+	cutoff := now.Add(-RevisionArchiveRetention).Unix()
+
+	removedCount := 0
+	for _, name := range segments {
+		info, err := a.archive.LoadRevisionSegmentInfo(clusterID, name)
+		if err != nil {
+			return err
+		}
+		// Segments are in revision order, so once we find a segment we need, the rest are newer
+		if info.EndTimestamp >= cutoff {
+			break
+		}
+		if err := a.archive.RemoveRevisionSegment(clusterID, name); err != nil {
+			return fmt.Errorf("error removing revision segment %q: %v", name, err)
+		}
+		removedCount++
+	}
+
+	if removedCount != 0 {
+		klog.Infof("Removed %d expired revision segments", removedCount)
+	}
+	return nil
+}
+
+// pendingSegment accumulates the revisions we have seen, but not yet written to the backup store
+type pendingSegment struct {
+	clusterID     string
+	startRevision int64
+
+	revisions []*protoetcd.ArchivedRevision
+
+	// size is the approximate size of the pending keys and values
+	size int
+}
+
+// add appends the changes in a watch batch; every change for a revision is delivered in the same batch.
+// now is when the batch was received, which is as close as we can get to when etcd made the changes.
+// leaseTTL returns the TTL of the lease a key is attached to.
+func (s *pendingSegment) add(changes []etcdclient.KeyChange, now time.Time, leaseTTL func(lease int64) int64) {
+	for _, change := range changes {
+		var revision *protoetcd.ArchivedRevision
+		if n := len(s.revisions); n != 0 && s.revisions[n-1].Revision == change.Revision {
+			revision = s.revisions[n-1]
+		} else {
+			revision = &protoetcd.ArchivedRevision{
+				Revision:  change.Revision,
+				Timestamp: now.Unix(),
+			}
+			s.revisions = append(s.revisions, revision)
+		}
+
+		event := &protoetcd.ArchivedEvent{
+			Key:    []byte(change.Key),
+			Value:  change.Value,
+			Delete: change.Deleted,
+		}
+		if change.Lease != 0 && !change.Deleted {
+			event.Lease = change.Lease
+			event.LeaseTtl = leaseTTL(change.Lease)
+		}
+		revision.Events = append(revision.Events, event)
+		s.size += len(change.Key) + len(change.Value)
+	}
+}
+
+// info returns the info for a segment holding the pending revisions
+func (s *pendingSegment) info() *protoetcd.RevisionSegmentInfo {
+	first := s.revisions[0]
+	last := s.revisions[len(s.revisions)-1]
+	return &protoetcd.RevisionSegmentInfo{
+		EtcdClusterId:  s.clusterID,
+		StartRevision:  s.startRevision,
+		EndRevision:    last.Revision,
+		StartTimestamp: first.Timestamp,
+		EndTimestamp:   last.Timestamp,
+	}
+}
+
+// reset clears the pending revisions, after they have been written
+func (s *pendingSegment) reset(startRevision int64) {
+	s.startRevision = startRevision
+	s.revisions = nil
+	s.size = 0
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+func TestPendingSegment(t *testing.T) {
+	s := &pendingSegment{
+		clusterID:     "cdf818194e3a8c32",
+		startRevision: 10,
+	}
+
+	t1 := time.Unix(1700000000, 0)
+	t2 := t1.Add(30 * time.Second)
+
+	leaseTTL := func(lease int64) int64 {
+		if lease != 0x77 {
+			t.Errorf("unexpected lookup of lease %x", lease)
+		}
+		return 3600
+	}
+
+	s.add([]etcdclient.KeyChange{
+		{Revision: 12, Key: "/a", Value: []byte("1")},
+		{Revision: 12, Key: "/b", Value: []byte("2"), Lease: 0x77},
+	}, t1, leaseTTL)
+	s.add([]etcdclient.KeyChange{
+		{Revision: 13, Key: "/a", Deleted: true},
+	}, t2, leaseTTL)
+
+	if len(s.revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(s.revisions))
+	}
+	if len(s.revisions[0].Events) != 2 || len(s.revisions[1].Events) != 1 {
+		t.Errorf("changes were not grouped by revision: %v", s.revisions)
+	}
+	if !s.revisions[1].Events[0].Delete {
+		t.Errorf("expected delete to be recorded")
+	}
+	if event := s.revisions[0].Events[0]; event.Lease != 0 || event.LeaseTtl != 0 {
+		t.Errorf("expected no lease for /a, got %v", event)
+	}
+	if event := s.revisions[0].Events[1]; event.Lease != 0x77 || event.LeaseTtl != 3600 {
+		t.Errorf("expected the lease and its TTL to be recorded for /b, got %v", event)
+	}
+
+	info := s.info()
+	if info.StartRevision != 10 || info.EndRevision != 13 {
+		t.Errorf("segment should cover revisions 10 to 13, got %d to %d", info.StartRevision, info.EndRevision)
+	}
+	if info.StartTimestamp != t1.Unix() || info.EndTimestamp != t2.Unix() {
+		t.Errorf("unexpected segment timestamps %d to %d", info.StartTimestamp, info.EndTimestamp)
+	}
+	if info.EtcdClusterId != "cdf818194e3a8c32" {
+		t.Errorf("unexpected cluster id %q", info.EtcdClusterId)
+	}
+
+	s.reset(info.EndRevision + 1)
+	if s.startRevision != 14 || len(s.revisions) != 0 || s.size != 0 {
+		t.Errorf("reset did not clear the segment: %+v", s)
+	}
+}
+
+// failingRevisionArchive is a backup.RevisionArchive that cannot write segments
+type failingRevisionArchive struct {
+	backup.RevisionArchive
+}
+
+func (a *failingRevisionArchive) AddRevisionSegment(info *protoetcd.RevisionSegmentInfo, data []byte) (string, error) {
+	return "", fmt.Errorf("store is unavailable")
+}
+
+func TestFlushSegmentCapsPending(t *testing.T) {
+	codec, err := backup.CodecFor(backup.CompressionGzip)
+	if err != nil {
+		t.Fatalf("CodecFor failed: %v", err)
+	}
+	a := &RevisionArchiver{
+		archive: &failingRevisionArchive{},
+		codec:   codec,
+	}
+
+	s := &pendingSegment{
+		clusterID:     "cdf818194e3a8c32",
+		startRevision: 10,
+	}
+	s.add([]etcdclient.KeyChange{{Revision: 10, Key: "/a", Value: []byte("1")}}, time.Now(), nil)
+
+	// Below the cap, a failed write keeps the revisions pending for the next segment
+	if err := a.flushSegment(s); err != nil {
+		t.Fatalf("flushSegment failed below the cap: %v", err)
+	}
+	if len(s.revisions) != 1 {
+		t.Errorf("expected the revision to remain pending, got %d revisions", len(s.revisions))
+	}
+
+	// At the cap, we give up so that we resume from the store rather than growing without bound
+	s.add([]etcdclient.KeyChange{{Revision: 11, Key: "/b", Value: make([]byte, maxPendingSize)}}, time.Now(), nil)
+	err = a.flushSegment(s)
+	if err == nil || !strings.Contains(err.Error(), "store is unavailable") {
+		t.Errorf("expected flushSegment to fail at the cap, got %v", err)
+	}
+}
@@ -26,6 +26,7 @@ import (
 
 	"k8s.io/klog/v2"
 
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
 	"sigs.k8s.io/etcd-manager/pkg/backup"
 )
 
@@ -97,7 +98,7 @@ func NewBackupCleanup(backupStore backup.Store) *BackupCleanup {
 	}
 }
 
-// MaybeDoBackupMaintenance removes old backups, if a suitable interval has passed.
+// MaybeDoBackupMaintenance removes the backups that the retention policy does not keep, if a suitable interval has passed.
 // It should be called periodically, after every backup for example.
 func (m *BackupCleanup) MaybeDoBackupMaintenance(ctx context.Context) error {
 	now := time.Now()
@@ -110,82 +111,108 @@ func (m *BackupCleanup) MaybeDoBackupMaintenance(ctx context.Context) error {
 	// so that we don't run this again immediately even if the cleanup fails.
 	m.lastBackupCleanup = now
 
-	backupNames, err := m.backupStore.ListBackups()
-	if err != nil {
-		return fmt.Errorf("error listing backups: %v", err)
+	if _, err := m.Prune(ctx, false); err != nil {
+		return err
 	}
+	return nil
+}
 
-	minRetention := time.Hour
+// Prune removes the backups that the retention policy does not keep, returning their names.
+// If dryRun is true, nothing is removed, and the names of the backups that would be removed are returned.
+func (m *BackupCleanup) Prune(ctx context.Context, dryRun bool) ([]string, error) {
+	policy, err := LoadRetentionPolicy(m.backupStore)
+	if err != nil {
+		return nil, fmt.Errorf("error loading retention policy: %v", err)
+	}
 
-	backups := make(map[time.Time]string)
-	retain := make(map[string]bool)
-	ignore := make(map[string]bool)
-	buckets := make(map[time.Time]time.Time)
+	candidates, err := m.listCandidates()
+	if err != nil {
+		return nil, err
+	}
 
-	for _, backup := range backupNames {
-		// Time parsing uses the same layout values as `Format`.
-		i := parseBackupNameInfo(backup)
-		if i == nil {
-			klog.Warningf("ignoring unparseable backup %q", backup)
-			ignore[backup] = true
-			continue
+	removals := policy.selectRemovals(candidates, time.Now())
+
+	pins, _ := m.backupStore.(backup.PinStore)
+	var removed []string
+	for _, backup := range removals {
+		// The catalog could have missed a pin added concurrently, so we check the pin itself before removing anything;
+		// a dry run checks too, so that it reports what a real run would do
+		if pins != nil {
+			pin, err := pins.LoadPin(backup)
+			if err != nil {
+				klog.Warningf("not removing backup %q, unable to check whether it is pinned: %v", backup, err)
+				continue
+			}
+			if backupPinned(backup, pin) {
+				continue
+			}
 		}
 
-		t := i.Timestamp
-		backups[t] = backup
-
-		age := now.Sub(t)
-
-		if age < minRetention {
-			retain[backup] = true
+		if dryRun {
+			klog.Infof("would remove backup %q", backup)
+			removed = append(removed, backup)
 			continue
 		}
 
-		if age < HourlyBackupsRetention {
-			bucketed := t.Truncate(time.Hour)
-			existing := buckets[bucketed]
-			if existing.IsZero() || existing.After(t) {
-				buckets[bucketed] = t
-			}
-			continue
+		klog.V(4).Infof("removing backup %q", backup)
+		if err := m.backupStore.RemoveBackup(backup); err != nil {
+			klog.Warningf("failed to remove backup %q: %v", backup, err)
+		} else {
+			klog.V(2).Infof("removed backup %q", backup)
+			removed = append(removed, backup)
 		}
+	}
 
-		if age < DailyBackupsRetention {
-			bucketed := t.Truncate(time.Hour * 24)
-			existing := buckets[bucketed]
-			if existing.IsZero() || existing.After(t) {
-				buckets[bucketed] = t
-			}
-			continue
-		}
+	if len(removed) != 0 {
+		klog.Infof("Removed %d old backups", len(removed))
 	}
 
-	for _, t := range buckets {
-		retain[backups[t]] = true
+	return removed, nil
+}
+
+// backupPinned returns true if the pin means we must keep the backup
+func backupPinned(name string, pin *protoetcd.BackupPin) bool {
+	if !backup.IsPinned(pin, time.Now()) {
+		return false
+	}
+	if pin.Expiry != 0 {
+		klog.V(2).Infof("keeping backup %q, which is pinned until %s", name, time.Unix(pin.Expiry, 0).UTC().Format(time.RFC3339))
+	} else {
+		klog.V(2).Infof("keeping backup %q, which is pinned", name)
 	}
+	return true
+}
 
-	removedCount := 0
-	for _, backup := range backupNames {
-		if retain[backup] {
-			klog.V(4).Infof("retaining backup %q", backup)
+// listCandidates returns the backups the retention policy applies to.
+// Pinned backups, and backups with unparseable names, are always kept, as is the last known-good backup while
+// the newest backups are flagged as anomalous.
+func (m *BackupCleanup) listCandidates() ([]retentionCandidate, error) {
+	entries, err := backup.ListEntries(m.backupStore)
+	if err != nil {
+		return nil, err
+	}
+
+	knownGood := lastKnownGood(entries)
+
+	var candidates []retentionCandidate
+	for _, entry := range entries {
+		if backupPinned(entry.Name, entry.Pin) {
 			continue
 		}
-		if ignore[backup] {
-			klog.V(4).Infof("ignoring backup %q", backup)
+		if entry.Name == knownGood {
+			klog.Warningf("keeping backup %q, the last known-good backup, because newer backups are flagged as anomalous", entry.Name)
 			continue
 		}
-		klog.V(4).Infof("removing backup %q", backup)
-		if err := m.backupStore.RemoveBackup(backup); err != nil {
-			klog.Warningf("failed to remove backup %q: %v", backup, err)
-		} else {
-			klog.V(2).Infof("removed backup %q", backup)
-			removedCount++
+		i := parseBackupNameInfo(entry.Name)
+		if i == nil {
+			klog.Warningf("ignoring unparseable backup %q", entry.Name)
+			continue
 		}
+		candidates = append(candidates, retentionCandidate{
+			Name:      entry.Name,
+			Timestamp: i.Timestamp,
+			Size:      entry.Info.GetDataSize(),
+		})
 	}
-
-	if removedCount != 0 {
-		klog.Infof("Removed %d old backups", removedCount)
-	}
-
-	return nil
+	return candidates, nil
 }
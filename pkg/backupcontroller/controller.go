@@ -46,7 +46,16 @@ type BackupController struct {
 
 	backupInterval time.Duration
 
+	// Schedule, if set, is used to decide when to take backups, instead of backupInterval
+	Schedule *BackupSchedule
+
 	backupCleanup *BackupCleanup
+
+	// backupHooks are run around each backup
+	backupHooks *BackupHooks
+
+	// anomalyDetector flags backups that differ sharply from the preceding backups
+	anomalyDetector *AnomalyDetector
 }
 
 func NewBackupController(backupStore backup.Store, clusterName string, clientUrls []string, etcdClientTLSConfig *tls.Config, dataDir string, backupInterval time.Duration) (*BackupController, error) {
@@ -54,6 +63,16 @@ func NewBackupController(backupStore backup.Store, clusterName string, clientUrl
 		return nil, fmt.Errorf("ClusterName is required")
 	}
 
+	backupHooks, err := LoadBackupHooksFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	anomalyDetector, err := NewAnomalyDetector(backupStore)
+	if err != nil {
+		return nil, err
+	}
+
 	m := &BackupController{
 		clusterName:         clusterName,
 		backupStore:         backupStore,
@@ -62,6 +81,8 @@ func NewBackupController(backupStore backup.Store, clusterName string, clientUrl
 		etcdClientTLSConfig: etcdClientTLSConfig,
 		backupInterval:      backupInterval,
 		backupCleanup:       NewBackupCleanup(backupStore),
+		backupHooks:         backupHooks,
+		anomalyDetector:     anomalyDetector,
 	}
 	return m, nil
 }
@@ -114,23 +135,46 @@ func (m *BackupController) maybeBackup(ctx context.Context, etcdVersion string,
 	now := time.Now()
 
 	shouldBackup := now.Sub(m.lastBackup) > m.backupInterval
+	if m.Schedule != nil {
+		v, err := m.Schedule.ShouldBackup(m.backupStore, now)
+		if err != nil {
+			return fmt.Errorf("error checking backup schedule: %w", err)
+		}
+		shouldBackup = v
+	}
+	if shouldBackup && now.Before(m.backupHooks.RetryAfter()) {
+		klog.V(2).Infof("deferring backup until %s, after a required pre-backup hook failed", m.backupHooks.RetryAfter().UTC().Format(time.RFC3339))
+		shouldBackup = false
+	}
 	if !shouldBackup {
 		return nil
 	}
 
-	backup, err := m.doClusterBackup(ctx, etcdVersion, members)
-	if err != nil {
+	backup, err := m.backupHooks.RunBackup(ctx, m.backupStore, func() (*protoetcd.DoBackupResponse, error) {
+		return m.doClusterBackup(ctx, etcdVersion, members)
+	})
+	if backup == nil {
 		return err
 	}
+	// err is now from a required post-backup hook; the backup itself was taken
+	hookErr := err
 
 	klog.Infof("took backup: %v", backup)
 	m.lastBackup = now
+	if m.Schedule != nil {
+		m.Schedule.BackupTaken(backup.Name)
+	}
+
+	// We check before cleanup, so that cleanup keeps the last known-good backup if this one is flagged
+	if _, err := m.anomalyDetector.CheckBackup(backup.Name); err != nil {
+		klog.Warningf("error checking backup %q for anomalies: %v", backup.Name, err)
+	}
 
 	if err := m.backupCleanup.MaybeDoBackupMaintenance(ctx); err != nil {
 		klog.Warningf("error during backup cleanup: %v", err)
 	}
 
-	return nil
+	return hookErr
 }
 
 func (m *BackupController) doClusterBackup(ctx context.Context, etcdVersion string, members []*etcdclient.EtcdProcessMember) (*protoetcd.DoBackupResponse, error) {
@@ -140,6 +184,7 @@ func (m *BackupController) doClusterBackup(ctx context.Context, etcdVersion stri
 			EtcdVersion: etcdVersion,
 		},
 		EtcdVersion: etcdVersion,
+		Members:     etcd.BackupMembers(members),
 	}
 
 	return etcd.DoBackup(m.backupStore, info, m.dataDir, m.clientUrls, m.etcdClientTLSConfig)
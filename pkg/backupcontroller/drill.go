@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"k8s.io/klog/v2"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+	"sigs.k8s.io/etcd-manager/pkg/contextutil"
+	"sigs.k8s.io/etcd-manager/pkg/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+// RestoreDrill periodically restores the newest backup into a sandbox etcd, to check that it can actually be restored.
+// The result is recorded alongside the backup, and exposed as metrics.
+type RestoreDrill struct {
+	backupStore backup.Store
+	// verifications records the results of drills in the backup store
+	verifications backup.VerificationStore
+
+	clientUrls          []string
+	etcdClientTLSConfig *tls.Config
+
+	drillInterval time.Duration
+
+	// lastDrill is the time at which we last performed a drill (as leader)
+	lastDrill time.Time
+}
+
+func NewRestoreDrill(backupStore backup.Store, clientUrls []string, etcdClientTLSConfig *tls.Config, drillInterval time.Duration) (*RestoreDrill, error) {
+	if drillInterval <= 0 {
+		return nil, fmt.Errorf("drill interval must be positive")
+	}
+
+	verifications, ok := backupStore.(backup.VerificationStore)
+	if !ok {
+		return nil, fmt.Errorf("backup store %s does not support recording restore drills", backupStore.Spec())
+	}
+
+	RegisterDrillMetrics()
+
+	d := &RestoreDrill{
+		backupStore:         backupStore,
+		verifications:       verifications,
+		clientUrls:          clientUrls,
+		etcdClientTLSConfig: etcdClientTLSConfig,
+		drillInterval:       drillInterval,
+	}
+	return d, nil
+}
+
+func (d *RestoreDrill) Run(ctx context.Context) {
+	contextutil.Forever(ctx,
+		loopInterval, // We do our own sleeping
+		func() {
+			err := d.run(ctx)
+			if err != nil {
+				klog.Warningf("unexpected error running restore drill loop: %v", err)
+			}
+		})
+}
+
+func (d *RestoreDrill) run(ctx context.Context) error {
+	if time.Since(d.lastDrill) < d.drillInterval {
+		return nil
+	}
+
+	// Only the leader drills, so that we don't restore the same backup on every node
+	etcdClient, err := etcdclient.NewClient(d.clientUrls, d.etcdClientTLSConfig)
+	if err != nil {
+		return fmt.Errorf("unable to reach etcd on %s: %v", d.clientUrls, err)
+	}
+	self, err := etcdClient.LocalNodeInfo(ctx)
+	etcdclient.LoggedClose(etcdClient)
+	if err != nil {
+		return fmt.Errorf("unable to get node state on %s: %v", d.clientUrls, err)
+	}
+	if !self.IsLeader {
+		klog.V(2).Infof("Not leader, won't run restore drill")
+		return nil
+	}
+
+	d.lastDrill = time.Now()
+
+	backups, err := d.backupStore.ListBackups()
+	if err != nil {
+		return fmt.Errorf("error listing backups: %v", err)
+	}
+	if len(backups) == 0 {
+		klog.Infof("no backups to run restore drill against")
+		return nil
+	}
+
+	return d.drill(ctx, backups[len(backups)-1])
+}
+
+// drill restores the backup in a sandbox, and records the result
+func (d *RestoreDrill) drill(ctx context.Context, backupName string) error {
+	klog.Infof("running restore drill against backup %q", backupName)
+
+	previous, err := d.verifications.LoadVerification(backupName)
+	if err != nil {
+		klog.Warningf("error loading previous verification of backup %q: %v", backupName, err)
+		previous = nil
+	}
+
+	clusterToken := "drill-etcd-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	tempDir := filepath.Join(os.TempDir(), clusterToken)
+	if err := os.MkdirAll(tempDir, 0700); err != nil {
+		return fmt.Errorf("error creating tempdir %q: %v", tempDir, err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			klog.Warningf("error cleaning up tempdir %q: %v", tempDir, err)
+		}
+	}()
+
+	info, err := d.backupStore.LoadInfo(backupName)
+	if err != nil {
+		return fmt.Errorf("error loading info of backup %q: %v", backupName, err)
+	}
+
+	start := time.Now()
+	result, backupKeyCount, drillErr := etcd.DrillBackup(ctx, d.backupStore, backupName, tempDir, info.Revision)
+	verification := checkDrill(info, previous, result, backupKeyCount, drillErr, time.Now())
+
+	recordDrillMetrics(verification, time.Since(start))
+
+	if verification.Success {
+		klog.Infof("restore drill passed for backup %q: revision %d, %d keys, hash %d", backupName, verification.Revision, verification.KeyCount, verification.Hash)
+	} else {
+		klog.Warningf("restore drill failed for backup %q: %s", backupName, verification.Error)
+	}
+
+	if err := d.verifications.SaveVerification(backupName, verification); err != nil {
+		return fmt.Errorf("error recording verification of backup %q: %v", backupName, err)
+	}
+	return nil
+}
+
+// checkDrill builds the verification for a drill result.
+// The restored data must reach the revision recorded in the backup info, and hold the recorded number of keys
+// at that revision (backupKeyCount, or -1 if it could not be counted).
+// A backup that passed an earlier drill must restore to the same revision and hash every time.
+func checkDrill(info *protoetcd.BackupInfo, previous *protoetcd.BackupVerification, result *protoetcd.BackupVerification, backupKeyCount int64, drillErr error, now time.Time) *protoetcd.BackupVerification {
+	if drillErr != nil {
+		return &protoetcd.BackupVerification{
+			Timestamp: now.Unix(),
+			Error:     drillErr.Error(),
+		}
+	}
+
+	verification := &protoetcd.BackupVerification{
+		Timestamp: now.Unix(),
+		Success:   true,
+		Revision:  result.Revision,
+		KeyCount:  result.KeyCount,
+		Hash:      result.Hash,
+	}
+
+	// Backups taken before these were recorded have no revision or key count
+	if info.Revision != 0 && result.Revision < info.Revision {
+		verification.Success = false
+		verification.Error = fmt.Sprintf("backup restored to revision %d, but was taken at revision %d", result.Revision, info.Revision)
+		return verification
+	}
+	if info.KeyCount != 0 && backupKeyCount >= 0 && backupKeyCount != info.KeyCount {
+		verification.Success = false
+		verification.Error = fmt.Sprintf("backup restored with %d keys at revision %d, but %d keys were recorded when it was taken", backupKeyCount, info.Revision, info.KeyCount)
+		return verification
+	}
+
+	if previous != nil && previous.Success {
+		if previous.Revision != result.Revision || previous.Hash != result.Hash {
+			verification.Success = false
+			verification.Error = fmt.Sprintf("backup restored to revision %d with hash %d, but an earlier drill restored it to revision %d with hash %d", result.Revision, result.Hash, previous.Revision, previous.Hash)
+		}
+	}
+
+	return verification
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+func TestCheckDrill(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	result := &protoetcd.BackupVerification{Revision: 42, KeyCount: 10, Hash: 1234}
+	info := &protoetcd.BackupInfo{Revision: 40, KeyCount: 9}
+
+	grid := []struct {
+		Name           string
+		Info           *protoetcd.BackupInfo
+		Previous       *protoetcd.BackupVerification
+		Result         *protoetcd.BackupVerification
+		BackupKeyCount int64
+		DrillErr       error
+		ExpectedErr    string
+	}{
+		{
+			Name:           "first drill",
+			Info:           info,
+			Result:         result,
+			BackupKeyCount: 9,
+		},
+		{
+			Name:           "backup without recorded revision",
+			Info:           &protoetcd.BackupInfo{},
+			Result:         result,
+			BackupKeyCount: -1,
+		},
+		{
+			Name:           "recorded revision compacted",
+			Info:           info,
+			Result:         result,
+			BackupKeyCount: -1,
+		},
+		{
+			Name:           "restored before recorded revision",
+			Info:           &protoetcd.BackupInfo{Revision: 50, KeyCount: 10},
+			Result:         result,
+			BackupKeyCount: -1,
+			ExpectedErr:    "backup restored to revision 42, but was taken at revision 50",
+		},
+		{
+			Name:           "key count differs from recorded",
+			Info:           info,
+			Result:         result,
+			BackupKeyCount: 7,
+			ExpectedErr:    "backup restored with 7 keys at revision 40, but 9 keys were recorded",
+		},
+		{
+			Name:           "matches earlier drill",
+			Info:           info,
+			Previous:       &protoetcd.BackupVerification{Success: true, Revision: 42, KeyCount: 10, Hash: 1234},
+			Result:         result,
+			BackupKeyCount: 9,
+		},
+		{
+			Name:           "hash changed since earlier drill",
+			Info:           info,
+			Previous:       &protoetcd.BackupVerification{Success: true, Revision: 42, KeyCount: 10, Hash: 999},
+			Result:         result,
+			BackupKeyCount: 9,
+			ExpectedErr:    "an earlier drill restored it to revision 42 with hash 999",
+		},
+		{
+			Name:           "earlier drill failed",
+			Info:           info,
+			Previous:       &protoetcd.BackupVerification{Error: "etcd did not start"},
+			Result:         result,
+			BackupKeyCount: 9,
+		},
+		{
+			Name:        "restore failed",
+			Info:        info,
+			DrillErr:    fmt.Errorf("etcd process exited"),
+			ExpectedErr: "etcd process exited",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			v := checkDrill(g.Info, g.Previous, g.Result, g.BackupKeyCount, g.DrillErr, now)
+			if v.Timestamp != now.Unix() {
+				t.Errorf("unexpected timestamp %d", v.Timestamp)
+			}
+			if g.ExpectedErr != "" {
+				if v.Success || !strings.Contains(v.Error, g.ExpectedErr) {
+					t.Fatalf("expected failure containing %q, got %v", g.ExpectedErr, v)
+				}
+				return
+			}
+			if !v.Success {
+				t.Fatalf("expected drill to pass, got %v", v)
+			}
+			if v.Revision != 42 || v.KeyCount != 10 || v.Hash != 1234 {
+				t.Errorf("verification did not record the drill result: %v", v)
+			}
+		})
+	}
+}
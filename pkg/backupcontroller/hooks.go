@@ -0,0 +1,302 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"k8s.io/klog/v2"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+// BackupHooksFileEnv points at a local file configuring the hooks run around each backup
+const BackupHooksFileEnv = "ETCD_MANAGER_BACKUP_HOOKS_FILE"
+
+// defaultHookTimeout is how long a hook may run, if it does not set a timeout
+const defaultHookTimeout = 30 * time.Second
+
+// minPreHookBackoff and maxPreHookBackoff bound how long we wait before retrying a backup after a required pre hook failed.
+// The wait doubles with each consecutive failure.
+const (
+	minPreHookBackoff = time.Minute
+	maxPreHookBackoff = 30 * time.Minute
+)
+
+// HookPhase is when a hook runs, relative to the backup
+type HookPhase string
+
+const (
+	// HookPhasePre hooks run before the backup; a failing required pre hook stops the backup
+	HookPhasePre HookPhase = "pre"
+	// HookPhasePost hooks run after the backup, whether or not it succeeded
+	HookPhasePost HookPhase = "post"
+)
+
+// BackupHook is a local command, or an HTTP endpoint, that is sent a BackupHookEvent as JSON
+type BackupHook struct {
+	Name  string    `json:"name"`
+	Phase HookPhase `json:"phase"`
+
+	// Exec is the command (and arguments) to run; the event is written to its stdin
+	Exec []string `json:"exec,omitempty"`
+
+	// URL is the endpoint to POST the event to
+	URL string `json:"url,omitempty"`
+
+	// Headers are added to the HTTP request, for example for authorization
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Timeout (eg "30s") bounds how long the hook may run
+	Timeout string `json:"timeout,omitempty"`
+
+	// Required hooks fail the backup if they fail; other hook failures are only logged
+	Required bool `json:"required,omitempty"`
+
+	timeout time.Duration
+}
+
+// BackupHookEvent is the JSON document sent to each hook
+type BackupHookEvent struct {
+	Phase       HookPhase `json:"phase"`
+	BackupStore string    `json:"backupStore"`
+
+	// BackupName and Info are set for post hooks, when the backup succeeded
+	BackupName string          `json:"backupName,omitempty"`
+	Info       json.RawMessage `json:"info,omitempty"`
+
+	// Error is set for post hooks, when the backup failed
+	Error string `json:"error,omitempty"`
+}
+
+// BackupHooks are the hooks run around each backup; a nil BackupHooks runs no hooks
+type BackupHooks struct {
+	Hooks []*BackupHook `json:"hooks"`
+
+	httpClient *http.Client
+
+	// preHookFailures is the number of consecutive backups stopped by a failing required pre hook
+	preHookFailures int
+	// retryAfter is the time before which scheduled backups should not be retried, after a required pre hook failed
+	retryAfter time.Time
+}
+
+// ParseBackupHooks parses and validates a JSON hooks configuration
+func ParseBackupHooks(data []byte) (*BackupHooks, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	hooks := &BackupHooks{}
+	if err := decoder.Decode(hooks); err != nil {
+		return nil, fmt.Errorf("error parsing backup hooks: %v", err)
+	}
+
+	for i, hook := range hooks.Hooks {
+		if hook.Name == "" {
+			return nil, fmt.Errorf("backup hook %d must have a name", i)
+		}
+		if hook.Phase != HookPhasePre && hook.Phase != HookPhasePost {
+			return nil, fmt.Errorf("backup hook %q has unknown phase %q (expected %q or %q)", hook.Name, hook.Phase, HookPhasePre, HookPhasePost)
+		}
+		if (len(hook.Exec) == 0) == (hook.URL == "") {
+			return nil, fmt.Errorf("backup hook %q must set exactly one of exec and url", hook.Name)
+		}
+		hook.timeout = defaultHookTimeout
+		if hook.Timeout != "" {
+			v, err := time.ParseDuration(hook.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing timeout %q for backup hook %q: %v", hook.Timeout, hook.Name, err)
+			}
+			if v <= 0 {
+				return nil, fmt.Errorf("timeout for backup hook %q must be positive", hook.Name)
+			}
+			hook.timeout = v
+		}
+	}
+
+	hooks.httpClient = &http.Client{}
+	return hooks, nil
+}
+
+// LoadBackupHooksFromEnv loads the hooks from the file named by BackupHooksFileEnv, returning nil if it is not set
+func LoadBackupHooksFromEnv() (*BackupHooks, error) {
+	p := os.Getenv(BackupHooksFileEnv)
+	if p == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup hooks file %q: %v", p, err)
+	}
+	hooks, err := ParseBackupHooks(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup hooks file %q: %v", p, err)
+	}
+	klog.Infof("loaded %d backup hooks from %s", len(hooks.Hooks), p)
+	return hooks, nil
+}
+
+// RetryAfter returns the time before which scheduled backups should not be attempted, because a required pre hook
+// failed; without the wait, every controller loop would run the hooks again.  It is zero if backups may go ahead.
+func (h *BackupHooks) RetryAfter() time.Time {
+	if h == nil {
+		return time.Time{}
+	}
+	return h.retryAfter
+}
+
+// RunBackup runs the pre hooks, then doBackup, then the post hooks.
+// Failing hooks are logged; a failing required pre hook stops the backup, and a failing required post hook is returned as an error.
+// A failing required pre hook also sets RetryAfter, backing off with each consecutive failure.
+func (h *BackupHooks) RunBackup(ctx context.Context, backupStore backup.Store, doBackup func() (*protoetcd.DoBackupResponse, error)) (*protoetcd.DoBackupResponse, error) {
+	if h == nil {
+		return doBackup()
+	}
+
+	pre := &BackupHookEvent{
+		Phase:       HookPhasePre,
+		BackupStore: backupStore.Spec(),
+	}
+	if err := h.run(ctx, pre); err != nil {
+		// The post hooks still run, so that anything the other pre hooks quiesced is resumed
+		post := &BackupHookEvent{
+			Phase:       HookPhasePost,
+			BackupStore: backupStore.Spec(),
+			Error:       err.Error(),
+		}
+		if postErr := h.run(ctx, post); postErr != nil {
+			klog.Warningf("error running post-backup hooks: %v", postErr)
+		}
+
+		backoff := minPreHookBackoff << min(h.preHookFailures, 5)
+		if backoff > maxPreHookBackoff {
+			backoff = maxPreHookBackoff
+		}
+		h.preHookFailures++
+		h.retryAfter = time.Now().Add(backoff)
+		return nil, fmt.Errorf("not taking backup (retrying in %v): %w", backoff, err)
+	}
+	h.preHookFailures = 0
+	h.retryAfter = time.Time{}
+
+	response, backupErr := doBackup()
+
+	post := &BackupHookEvent{
+		Phase:       HookPhasePost,
+		BackupStore: backupStore.Spec(),
+	}
+	if backupErr != nil {
+		post.Error = backupErr.Error()
+	} else {
+		post.BackupName = response.Name
+		info, err := backupStore.LoadInfo(response.Name)
+		if err != nil {
+			klog.Warningf("error loading info for backup %q, sending hooks the name only: %v", response.Name, err)
+		} else {
+			data, err := protoetcd.ToJson(info)
+			if err != nil {
+				klog.Warningf("error marshalling info for backup %q: %v", response.Name, err)
+			} else {
+				post.Info = json.RawMessage(data)
+			}
+		}
+	}
+
+	postErr := h.run(ctx, post)
+	if backupErr != nil {
+		if postErr != nil {
+			klog.Warningf("error running post-backup hooks: %v", postErr)
+		}
+		return nil, backupErr
+	}
+	if postErr != nil {
+		return response, fmt.Errorf("backup %q was taken, but %w", response.Name, postErr)
+	}
+	return response, nil
+}
+
+// run runs the hooks for the event's phase, in order, returning an error if a required hook failed
+func (h *BackupHooks) run(ctx context.Context, event *BackupHookEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error marshalling backup hook event: %v", err)
+	}
+
+	var errs []error
+	for _, hook := range h.Hooks {
+		if hook.Phase != event.Phase {
+			continue
+		}
+
+		klog.Infof("running %s-backup hook %q", hook.Phase, hook.Name)
+		if err := h.runHook(ctx, hook, data); err != nil {
+			if hook.Required {
+				klog.Warningf("required %s-backup hook %q failed: %v", hook.Phase, hook.Name, err)
+				errs = append(errs, fmt.Errorf("required %s-backup hook %q failed: %w", hook.Phase, hook.Name, err))
+			} else {
+				klog.Warningf("%s-backup hook %q failed (ignoring, as it is not required): %v", hook.Phase, hook.Name, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *BackupHooks) runHook(ctx context.Context, hook *BackupHook, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, hook.timeout)
+	defer cancel()
+
+	if len(hook.Exec) != 0 {
+		cmd := exec.CommandContext(ctx, hook.Exec[0], hook.Exec[1:]...)
+		cmd.Stdin = bytes.NewReader(data)
+		output, err := cmd.CombinedOutput()
+		if len(output) != 0 {
+			klog.Infof("output from backup hook %q: %s", hook.Name, output)
+		}
+		if err != nil {
+			return fmt.Errorf("error running %v: %w", hook.Exec, err)
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error building request to %s: %w", hook.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting to %s: %w", hook.URL, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response from %s: %s %s", hook.URL, resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+func TestParseBackupHooks(t *testing.T) {
+	grid := []struct {
+		Config    string
+		ExpectErr string
+	}{
+		{Config: `{"hooks": [{"name": "a", "phase": "pre", "exec": ["true"]}, {"name": "b", "phase": "post", "url": "http://localhost/", "timeout": "5s", "required": true}]}`},
+		{Config: `{"hooks": [{"phase": "pre", "exec": ["true"]}]}`, ExpectErr: "must have a name"},
+		{Config: `{"hooks": [{"name": "a", "phase": "during", "exec": ["true"]}]}`, ExpectErr: "unknown phase"},
+		{Config: `{"hooks": [{"name": "a", "phase": "pre"}]}`, ExpectErr: "exactly one of exec and url"},
+		{Config: `{"hooks": [{"name": "a", "phase": "pre", "exec": ["true"], "url": "http://localhost/"}]}`, ExpectErr: "exactly one of exec and url"},
+		{Config: `{"hooks": [{"name": "a", "phase": "pre", "exec": ["true"], "timeout": "soon"}]}`, ExpectErr: "error parsing timeout"},
+		{Config: `{"hooks": [{"name": "a", "phase": "pre", "command": ["true"]}]}`, ExpectErr: "unknown field"},
+	}
+
+	for _, g := range grid {
+		_, err := ParseBackupHooks([]byte(g.Config))
+		if g.ExpectErr == "" {
+			if err != nil {
+				t.Errorf("unexpected error parsing %s: %v", g.Config, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), g.ExpectErr) {
+			t.Errorf("parsing %s: expected error containing %q, got %v", g.Config, g.ExpectErr, err)
+		}
+	}
+}
+
+// hookRecorder is an HTTP endpoint that records the events posted to it
+type hookRecorder struct {
+	mutex  sync.Mutex
+	events []*BackupHookEvent
+	status int
+}
+
+func (r *hookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	event := &BackupHookEvent{}
+	if err := json.Unmarshal(body, event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event)
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+	}
+}
+
+func newTestHooks(t *testing.T, config string) *BackupHooks {
+	hooks, err := ParseBackupHooks([]byte(config))
+	if err != nil {
+		t.Fatalf("ParseBackupHooks failed: %v", err)
+	}
+	return hooks
+}
+
+func TestBackupHooksRunBackup(t *testing.T) {
+	p, err := vfs.Context.BuildVfsPath(filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := backup.NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+
+	recorder := &hookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	preOutput := filepath.Join(t.TempDir(), "pre.json")
+	hooks := newTestHooks(t, fmt.Sprintf(`{"hooks": [
+		{"name": "quiesce", "phase": "pre", "exec": ["sh", "-c", "cat > %s"], "required": true},
+		{"name": "flaky", "phase": "pre", "exec": ["false"]},
+		{"name": "catalogue", "phase": "post", "url": %q}
+	]}`, preOutput, server.URL))
+
+	var backupName string
+	response, err := hooks.RunBackup(t.Context(), store, func() (*protoetcd.DoBackupResponse, error) {
+		name, err := store.AddBackup("", "000001", &protoetcd.BackupInfo{EtcdVersion: "3.5.9", Revision: 42})
+		if err != nil {
+			return nil, err
+		}
+		backupName = name
+		return &protoetcd.DoBackupResponse{Name: name}, nil
+	})
+	if err != nil {
+		t.Fatalf("RunBackup failed: %v", err)
+	}
+	if response.Name != backupName {
+		t.Errorf("RunBackup returned %v, expected backup %q", response, backupName)
+	}
+
+	pre := &BackupHookEvent{}
+	data, err := os.ReadFile(preOutput)
+	if err != nil {
+		t.Fatalf("pre hook did not run: %v", err)
+	}
+	if err := json.Unmarshal(data, pre); err != nil {
+		t.Fatalf("pre hook received invalid JSON %q: %v", data, err)
+	}
+	if pre.Phase != HookPhasePre || pre.BackupStore != store.Spec() || pre.BackupName != "" {
+		t.Errorf("unexpected pre hook event %+v", pre)
+	}
+
+	if len(recorder.events) != 1 {
+		t.Fatalf("expected one post hook event, got %d", len(recorder.events))
+	}
+	post := recorder.events[0]
+	if post.Phase != HookPhasePost || post.BackupName != backupName || post.Error != "" {
+		t.Errorf("unexpected post hook event %+v", post)
+	}
+	info := &protoetcd.BackupInfo{}
+	if err := protoetcd.FromJson(string(post.Info), info); err != nil {
+		t.Fatalf("post hook info was not a BackupInfo: %v", err)
+	}
+	if info.EtcdVersion != "3.5.9" || info.Revision != 42 {
+		t.Errorf("unexpected post hook info %v", info)
+	}
+}
+
+func TestBackupHooksRequired(t *testing.T) {
+	p, err := vfs.Context.BuildVfsPath(filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := backup.NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+
+	recorder := &hookRecorder{status: http.StatusInternalServerError}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	// A failing required pre hook stops the backup, but the post hooks still run
+	hooks := newTestHooks(t, fmt.Sprintf(`{"hooks": [
+		{"name": "quiesce", "phase": "pre", "exec": ["false"], "required": true},
+		{"name": "resume", "phase": "post", "url": %q}
+	]}`, server.URL))
+	called := false
+	_, err = hooks.RunBackup(t.Context(), store, func() (*protoetcd.DoBackupResponse, error) {
+		called = true
+		return &protoetcd.DoBackupResponse{Name: "unused"}, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), `"quiesce"`) {
+		t.Errorf("expected the required pre hook to fail the backup, got %v", err)
+	}
+	if called {
+		t.Errorf("backup was taken despite the required pre hook failing")
+	}
+	if len(recorder.events) != 1 || recorder.events[0].Error == "" {
+		t.Errorf("expected the post hook to be told of the failure, got %+v", recorder.events)
+	}
+
+	// Scheduled backups back off after the failure, for longer with each consecutive failure
+	firstRetry := hooks.RetryAfter()
+	if wait := time.Until(firstRetry); wait <= 0 || wait > minPreHookBackoff {
+		t.Errorf("expected to retry within %v, got %v", minPreHookBackoff, wait)
+	}
+	if _, err := hooks.RunBackup(t.Context(), store, func() (*protoetcd.DoBackupResponse, error) {
+		return nil, fmt.Errorf("backup should not be taken")
+	}); err == nil {
+		t.Errorf("expected the required pre hook to fail the backup again")
+	}
+	if wait := time.Until(hooks.RetryAfter()); wait <= minPreHookBackoff || wait > 2*minPreHookBackoff {
+		t.Errorf("expected the backoff to double, got %v", wait)
+	}
+
+	// Once the pre hooks pass, backups are no longer deferred
+	hooks.Hooks[0].Exec = []string{"true"}
+	if _, err := hooks.RunBackup(t.Context(), store, func() (*protoetcd.DoBackupResponse, error) {
+		return nil, fmt.Errorf("backup failed")
+	}); err == nil || err.Error() != "backup failed" {
+		t.Errorf("expected the backup error, got %v", err)
+	}
+	if !hooks.RetryAfter().IsZero() {
+		t.Errorf("expected the backoff to be reset, got %v", hooks.RetryAfter())
+	}
+
+	// A failing required post hook is reported, along with the backup that was taken
+	hooks = newTestHooks(t, fmt.Sprintf(`{"hooks": [{"name": "catalogue", "phase": "post", "url": %q, "required": true}]}`, server.URL))
+	response, err := hooks.RunBackup(t.Context(), store, func() (*protoetcd.DoBackupResponse, error) {
+		name, err := store.AddBackup("", "000001", &protoetcd.BackupInfo{EtcdVersion: "3.5.9"})
+		if err != nil {
+			return nil, err
+		}
+		return &protoetcd.DoBackupResponse{Name: name}, nil
+	})
+	if response == nil {
+		t.Fatalf("expected the backup to be returned, got error %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected the required post hook failure to be reported, got %v", err)
+	}
+
+	// Without hooks, the backup just runs
+	var noHooks *BackupHooks
+	if _, err := noHooks.RunBackup(t.Context(), store, func() (*protoetcd.DoBackupResponse, error) {
+		return nil, fmt.Errorf("backup failed")
+	}); err == nil || err.Error() != "backup failed" {
+		t.Errorf("expected the backup error, got %v", err)
+	}
+}
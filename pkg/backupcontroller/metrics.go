@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+var (
+	drillLastRunTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "etcd_backup_drill_last_run_timestamp_seconds",
+		Help: "Time at which the last restore drill finished",
+	})
+	drillLastSuccessTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "etcd_backup_drill_last_success_timestamp_seconds",
+		Help: "Time at which a backup last passed a restore drill",
+	})
+	drillSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "etcd_backup_drill_success",
+		Help: "1 if the last restore drill passed, 0 if it failed",
+	})
+	drillDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "etcd_backup_drill_duration_seconds",
+		Help: "Duration of the last restore drill",
+	})
+	drillRevision = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "etcd_backup_drill_revision",
+		Help: "etcd revision of the backup restored by the last successful restore drill",
+	})
+	drillKeyCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "etcd_backup_drill_key_count",
+		Help: "Number of keys in the backup restored by the last successful restore drill",
+	})
+)
+
+var backupAnomalies = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "etcd_backup_anomalies_total",
+	Help: "Number of backups flagged because their size or key count differed sharply from the preceding backups",
+})
+
+var registerDrillMetrics sync.Once
+
+// RegisterDrillMetrics registers the restore drill metrics.
+func RegisterDrillMetrics() {
+	registerDrillMetrics.Do(func() {
+		prometheus.MustRegister(
+			drillLastRunTimestamp,
+			drillLastSuccessTimestamp,
+			drillSuccess,
+			drillDuration,
+			drillRevision,
+			drillKeyCount,
+		)
+	})
+}
+
+var registerAnomalyMetrics sync.Once
+
+// RegisterAnomalyMetrics registers the backup anomaly metrics.
+func RegisterAnomalyMetrics() {
+	registerAnomalyMetrics.Do(func() {
+		prometheus.MustRegister(backupAnomalies)
+	})
+}
+
+// recordDrillMetrics updates the restore drill metrics with the result of a drill
+func recordDrillMetrics(verification *protoetcd.BackupVerification, duration time.Duration) {
+	drillLastRunTimestamp.Set(float64(verification.Timestamp))
+	drillDuration.Set(duration.Seconds())
+	if !verification.Success {
+		drillSuccess.Set(0)
+		return
+	}
+	drillSuccess.Set(1)
+	drillLastSuccessTimestamp.Set(float64(verification.Timestamp))
+	drillRevision.Set(float64(verification.Revision))
+	drillKeyCount.Set(float64(verification.KeyCount))
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+// RetentionPolicyFileEnv points at a local file holding the retention policy, which takes precedence over the backup store
+const RetentionPolicyFileEnv = "ETCD_MANAGER_BACKUP_RETENTION_POLICY_FILE"
+
+// RetentionPolicyFilename is the name of the retention policy in the root of the backup store
+const RetentionPolicyFilename = "_etcd_backups.retention"
+
+// RetentionPolicy is a grandfather-father-son retention policy for backups.
+// For each of the hourly, daily, weekly and monthly buckets, we keep the earliest backup in each period,
+// limited to the most recent N periods that hold a backup, and/or to backups younger than the bucket's max age.
+// Periods are in UTC, and weeks start on Monday.
+type RetentionPolicy struct {
+	// MinAge is the age (eg "1h") below which backups are always kept
+	MinAge string `json:"minAge,omitempty"`
+
+	// KeepLast is the number of most recent backups that are always kept
+	KeepLast int `json:"keepLast,omitempty"`
+
+	Hourly  int `json:"hourly,omitempty"`
+	Daily   int `json:"daily,omitempty"`
+	Weekly  int `json:"weekly,omitempty"`
+	Monthly int `json:"monthly,omitempty"`
+
+	// HourlyMaxAge (eg "7d") and so on limit each bucket to backups younger than the age
+	HourlyMaxAge  string `json:"hourlyMaxAge,omitempty"`
+	DailyMaxAge   string `json:"dailyMaxAge,omitempty"`
+	WeeklyMaxAge  string `json:"weeklyMaxAge,omitempty"`
+	MonthlyMaxAge string `json:"monthlyMaxAge,omitempty"`
+
+	// MaxTotalSizeBytes caps the total size of the kept backups, if non-zero.
+	// The oldest backups are removed first to meet the cap, but the newest backup is always kept.
+	MaxTotalSizeBytes int64 `json:"maxTotalSizeBytes,omitempty"`
+
+	minAge        time.Duration
+	hourlyMaxAge  time.Duration
+	dailyMaxAge   time.Duration
+	weeklyMaxAge  time.Duration
+	monthlyMaxAge time.Duration
+}
+
+// DefaultRetentionPolicy is the policy used when none is configured: everything from the last hour, then the earliest
+// backup in each hour for ETCD_MANAGER_HOURLY_BACKUPS_RETENTION, and in each day for ETCD_MANAGER_DAILY_BACKUPS_RETENTION
+func DefaultRetentionPolicy() *RetentionPolicy {
+	return &RetentionPolicy{
+		MinAge:       "1h",
+		HourlyMaxAge: HourlyBackupsRetention.String(),
+		DailyMaxAge:  DailyBackupsRetention.String(),
+		minAge:       time.Hour,
+		hourlyMaxAge: HourlyBackupsRetention,
+		dailyMaxAge:  DailyBackupsRetention,
+	}
+}
+
+// ParseRetentionPolicy parses and validates a JSON retention policy
+func ParseRetentionPolicy(data []byte) (*RetentionPolicy, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	policy := &RetentionPolicy{}
+	if err := decoder.Decode(policy); err != nil {
+		return nil, fmt.Errorf("error parsing retention policy: %v", err)
+	}
+
+	durations := []struct {
+		Field string
+		Value string
+		Into  *time.Duration
+	}{
+		{"minAge", policy.MinAge, &policy.minAge},
+		{"hourlyMaxAge", policy.HourlyMaxAge, &policy.hourlyMaxAge},
+		{"dailyMaxAge", policy.DailyMaxAge, &policy.dailyMaxAge},
+		{"weeklyMaxAge", policy.WeeklyMaxAge, &policy.weeklyMaxAge},
+		{"monthlyMaxAge", policy.MonthlyMaxAge, &policy.monthlyMaxAge},
+	}
+	for _, d := range durations {
+		if d.Value == "" {
+			continue
+		}
+		v, err := ParseHumanDuration(d.Value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing retention policy %s %q: %v", d.Field, d.Value, err)
+		}
+		if v < 0 {
+			return nil, fmt.Errorf("retention policy values must not be negative")
+		}
+		*d.Into = v
+	}
+
+	if policy.KeepLast < 0 || policy.Hourly < 0 || policy.Daily < 0 || policy.Weekly < 0 || policy.Monthly < 0 || policy.MaxTotalSizeBytes < 0 {
+		return nil, fmt.Errorf("retention policy values must not be negative")
+	}
+	keepsBucket := false
+	for _, bucket := range retentionBuckets {
+		if bucket.Count(policy) != 0 || bucket.MaxAge(policy) != 0 {
+			keepsBucket = true
+		}
+	}
+	if policy.KeepLast == 0 && !keepsBucket {
+		return nil, fmt.Errorf("retention policy must keep at least one backup (set keepLast, or a count or max age for hourly, daily, weekly or monthly)")
+	}
+	return policy, nil
+}
+
+// LoadRetentionPolicy loads the retention policy from the file named by RetentionPolicyFileEnv,
+// else from RetentionPolicyFilename in the backup store, else returns DefaultRetentionPolicy
+func LoadRetentionPolicy(backupStore backup.Store) (*RetentionPolicy, error) {
+	if p := os.Getenv(RetentionPolicyFileEnv); p != "" {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("error reading retention policy file %q: %v", p, err)
+		}
+		policy, err := ParseRetentionPolicy(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention policy file %q: %v", p, err)
+		}
+		klog.V(2).Infof("using retention policy from %s", p)
+		return policy, nil
+	}
+
+	if configStore, ok := backupStore.(backup.ConfigStore); ok {
+		data, err := configStore.ReadConfig(RetentionPolicyFilename)
+		if err == nil {
+			policy, err := ParseRetentionPolicy(data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s in backup store %s: %v", RetentionPolicyFilename, backupStore.Spec(), err)
+			}
+			klog.V(2).Infof("using retention policy from %s in backup store", RetentionPolicyFilename)
+			return policy, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return DefaultRetentionPolicy(), nil
+}
+
+// retentionCandidate is a backup that the retention policy may remove
+type retentionCandidate struct {
+	Name      string
+	Timestamp time.Time
+	Size      int64
+}
+
+// retentionBuckets are the kinds of GFS bucket, with the start of the bucket holding a time
+var retentionBuckets = []struct {
+	Name   string
+	Count  func(p *RetentionPolicy) int
+	MaxAge func(p *RetentionPolicy) time.Duration
+	Bucket func(t time.Time) time.Time
+}{
+	{
+		Name:   "hourly",
+		Count:  func(p *RetentionPolicy) int { return p.Hourly },
+		MaxAge: func(p *RetentionPolicy) time.Duration { return p.hourlyMaxAge },
+		Bucket: func(t time.Time) time.Time { return t.UTC().Truncate(time.Hour) },
+	},
+	{
+		Name:   "daily",
+		Count:  func(p *RetentionPolicy) int { return p.Daily },
+		MaxAge: func(p *RetentionPolicy) time.Duration { return p.dailyMaxAge },
+		Bucket: func(t time.Time) time.Time {
+			t = t.UTC()
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		},
+	},
+	{
+		Name:   "weekly",
+		Count:  func(p *RetentionPolicy) int { return p.Weekly },
+		MaxAge: func(p *RetentionPolicy) time.Duration { return p.weeklyMaxAge },
+		Bucket: func(t time.Time) time.Time {
+			t = t.UTC()
+			daysSinceMonday := (int(t.Weekday()) + 6) % 7
+			return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+		},
+	},
+	{
+		Name:   "monthly",
+		Count:  func(p *RetentionPolicy) int { return p.Monthly },
+		MaxAge: func(p *RetentionPolicy) time.Duration { return p.monthlyMaxAge },
+		Bucket: func(t time.Time) time.Time {
+			t = t.UTC()
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		},
+	},
+}
+
+// selectRemovals returns the names of the backups that the policy does not keep, oldest first
+func (p *RetentionPolicy) selectRemovals(backups []retentionCandidate, now time.Time) []string {
+	sorted := make([]retentionCandidate, len(backups))
+	copy(sorted, backups)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	keep := make(map[string]bool)
+
+	for i, b := range sorted {
+		if now.Sub(b.Timestamp) < p.minAge {
+			keep[b.Name] = true
+		}
+		if i >= len(sorted)-p.KeepLast {
+			keep[b.Name] = true
+		}
+	}
+
+	for _, bucket := range retentionBuckets {
+		count := bucket.Count(p)
+		maxAge := bucket.MaxAge(p)
+		if count == 0 && maxAge == 0 {
+			continue
+		}
+
+		// The earliest backup in each bucket; the earliest doesn't change as new backups arrive
+		earliest := make(map[time.Time]retentionCandidate)
+		var starts []time.Time
+		for _, b := range sorted {
+			if maxAge != 0 && now.Sub(b.Timestamp) >= maxAge {
+				continue
+			}
+			start := bucket.Bucket(b.Timestamp)
+			if _, found := earliest[start]; !found {
+				earliest[start] = b
+				starts = append(starts, start)
+			}
+		}
+
+		// starts is in ascending order, so the most recent buckets are at the end
+		if count != 0 && len(starts) > count {
+			starts = starts[len(starts)-count:]
+		}
+		for _, start := range starts {
+			klog.V(4).Infof("retaining %s backup %q", bucket.Name, earliest[start].Name)
+			keep[earliest[start].Name] = true
+		}
+	}
+
+	if p.MaxTotalSizeBytes != 0 {
+		var total int64
+		for _, b := range sorted {
+			if keep[b.Name] {
+				total += b.Size
+			}
+		}
+		for i, b := range sorted {
+			if total <= p.MaxTotalSizeBytes || i == len(sorted)-1 {
+				break
+			}
+			if keep[b.Name] {
+				klog.Infof("removing backup %q to keep total backup size under %d bytes", b.Name, p.MaxTotalSizeBytes)
+				keep[b.Name] = false
+				total -= b.Size
+			}
+		}
+	}
+
+	var removals []string
+	for _, b := range sorted {
+		if !keep[b.Name] {
+			removals = append(removals, b.Name)
+		}
+	}
+	return removals
+}
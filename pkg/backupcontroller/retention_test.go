@@ -0,0 +1,350 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+// candidatesEvery returns a candidate backup every interval, going back count intervals from now
+func candidatesEvery(now time.Time, interval time.Duration, count int) []retentionCandidate {
+	var candidates []retentionCandidate
+	for i := count - 1; i >= 0; i-- {
+		t := now.Add(-time.Duration(i) * interval)
+		candidates = append(candidates, retentionCandidate{
+			Name:      t.UTC().Format(time.RFC3339) + "-000001",
+			Timestamp: t,
+			Size:      100,
+		})
+	}
+	return candidates
+}
+
+func kept(candidates []retentionCandidate, removals []string) []string {
+	removed := make(map[string]bool)
+	for _, name := range removals {
+		removed[name] = true
+	}
+	var names []string
+	for _, c := range candidates {
+		if !removed[c.Name] {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+func TestRetentionPolicySelectRemovals(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, 3, 18, 12, 30, 0, 0, time.UTC)
+
+	grid := []struct {
+		Name       string
+		Policy     string
+		Candidates []retentionCandidate
+		Expected   []string
+	}{
+		{
+			Name:       "keep last",
+			Policy:     `{"keepLast": 2}`,
+			Candidates: candidatesEvery(now, time.Hour, 5),
+			Expected:   []string{"2026-03-18T11:30:00Z-000001", "2026-03-18T12:30:00Z-000001"},
+		},
+		{
+			Name:       "min age",
+			Policy:     `{"minAge": "90m", "keepLast": 1}`,
+			Candidates: candidatesEvery(now, time.Hour, 5),
+			Expected:   []string{"2026-03-18T11:30:00Z-000001", "2026-03-18T12:30:00Z-000001"},
+		},
+		{
+			Name:       "hourly keeps the earliest backup in each hour",
+			Policy:     `{"hourly": 2}`,
+			Candidates: candidatesEvery(now, 20*time.Minute, 6),
+			Expected:   []string{"2026-03-18T11:10:00Z-000001", "2026-03-18T12:10:00Z-000001"},
+		},
+		{
+			Name:       "daily counts days that have backups",
+			Policy:     `{"daily": 3}`,
+			Candidates: append(candidatesEvery(now.Add(-30*24*time.Hour), 24*time.Hour, 2), candidatesEvery(now, 24*time.Hour, 2)...),
+			Expected:   []string{"2026-02-16T12:30:00Z-000001", "2026-03-17T12:30:00Z-000001", "2026-03-18T12:30:00Z-000001"},
+		},
+		{
+			Name:       "weekly buckets start on monday",
+			Policy:     `{"weekly": 2}`,
+			Candidates: candidatesEvery(now, 24*time.Hour, 10),
+			Expected:   []string{"2026-03-09T12:30:00Z-000001", "2026-03-16T12:30:00Z-000001"},
+		},
+		{
+			Name:       "monthly",
+			Policy:     `{"monthly": 2, "keepLast": 1}`,
+			Candidates: candidatesEvery(now, 10*24*time.Hour, 8),
+			Expected:   []string{"2026-02-06T12:30:00Z-000001", "2026-03-08T12:30:00Z-000001", "2026-03-18T12:30:00Z-000001"},
+		},
+		{
+			Name:       "max age limits a bucket to recent backups",
+			Policy:     `{"hourlyMaxAge": "2h"}`,
+			Candidates: candidatesEvery(now, 20*time.Minute, 12),
+			Expected:   []string{"2026-03-18T10:50:00Z-000001", "2026-03-18T11:10:00Z-000001", "2026-03-18T12:10:00Z-000001"},
+		},
+		{
+			Name:       "size cap removes the oldest but keeps the newest",
+			Policy:     `{"keepLast": 5, "maxTotalSizeBytes": 250}`,
+			Candidates: candidatesEvery(now, time.Hour, 5),
+			Expected:   []string{"2026-03-18T11:30:00Z-000001", "2026-03-18T12:30:00Z-000001"},
+		},
+		{
+			Name:       "size cap never removes the newest backup",
+			Policy:     `{"keepLast": 5, "maxTotalSizeBytes": 50}`,
+			Candidates: candidatesEvery(now, time.Hour, 3),
+			Expected:   []string{"2026-03-18T12:30:00Z-000001"},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			policy, err := ParseRetentionPolicy([]byte(g.Policy))
+			if err != nil {
+				t.Fatalf("ParseRetentionPolicy failed: %v", err)
+			}
+			removals := policy.selectRemovals(g.Candidates, now)
+			if actual := kept(g.Candidates, removals); !reflect.DeepEqual(actual, g.Expected) {
+				t.Errorf("kept %v, expected %v", actual, g.Expected)
+			}
+		})
+	}
+}
+
+func TestDefaultRetentionPolicy(t *testing.T) {
+	now := time.Date(2026, 3, 18, 12, 30, 0, 0, time.UTC)
+	candidates := candidatesEvery(now, 15*time.Minute, 4*24*30)
+
+	removals := DefaultRetentionPolicy().selectRemovals(candidates, now)
+	actual := kept(candidates, removals)
+
+	// The 4 backups in the last hour, the earliest in each of the other 168 hours holding backups younger than a week,
+	// and the earliest on each of the 24 days (of 31 that have backups) that are not already covered by the hourly backups
+	expected := 4 + 168 + 24
+	if len(actual) != expected {
+		t.Errorf("default policy kept %d backups, expected %d", len(actual), expected)
+	}
+
+	// The windows are ages, not counts of periods: after backups stop, older hourly backups are not kept in their place
+	stopped := now.Add(-30 * 24 * time.Hour)
+	candidates = candidatesEvery(stopped, 15*time.Minute, 4*24*2)
+	removals = DefaultRetentionPolicy().selectRemovals(candidates, now)
+	actual = kept(candidates, removals)
+	if len(actual) != 3 {
+		t.Errorf("default policy kept %v for backups that stopped 30 days ago, expected the earliest on each of 3 days", actual)
+	}
+}
+
+func TestParseRetentionPolicy(t *testing.T) {
+	for _, invalid := range []string{
+		`{}`,
+		`{"minAge": "1h"}`,
+		`{"hourly": -1}`,
+		`{"dailyMaxAge": "-1d"}`,
+		`{"keepLast": 1, "weeklyMaxAge": "soon"}`,
+		`{"keepLast": 1, "minAge": "soon"}`,
+		`{"keepLast": 1, "yearly": 2}`,
+		`not json`,
+	} {
+		if _, err := ParseRetentionPolicy([]byte(invalid)); err == nil {
+			t.Errorf("expected error parsing retention policy %s", invalid)
+		}
+	}
+}
+
+func TestBackupCleanupPrune(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	p, err := vfs.Context.BuildVfsPath(dir)
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := backup.NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+
+	now := time.Now()
+	var names []string
+	for i := 4; i >= 0; i-- {
+		info := &protoetcd.BackupInfo{EtcdVersion: "3.5.0", Timestamp: now.Add(-time.Duration(i) * 2 * time.Hour).Unix()}
+		name, err := store.AddBackup("", "000001", info)
+		if err != nil {
+			t.Fatalf("AddBackup failed: %v", err)
+		}
+		names = append(names, name)
+	}
+
+	// The policy in the store applies
+	if err := os.WriteFile(filepath.Join(dir, RetentionPolicyFilename), []byte(`{"keepLast": 2}`), 0600); err != nil {
+		t.Fatalf("failed to write retention policy: %v", err)
+	}
+
+	cleanup := NewBackupCleanup(store)
+	wouldRemove, err := cleanup.Prune(t.Context(), true)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if !reflect.DeepEqual(wouldRemove, names[:3]) {
+		t.Errorf("dry run would remove %v, expected %v", wouldRemove, names[:3])
+	}
+	if backups, _ := store.ListBackups(); len(backups) != 5 {
+		t.Errorf("dry run removed backups: %v", backups)
+	}
+
+	// A local policy file takes precedence
+	policyFile := filepath.Join(t.TempDir(), "retention.json")
+	if err := os.WriteFile(policyFile, []byte(`{"keepLast": 4}`), 0600); err != nil {
+		t.Fatalf("failed to write retention policy: %v", err)
+	}
+	t.Setenv(RetentionPolicyFileEnv, policyFile)
+
+	removed, err := cleanup.Prune(t.Context(), false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if !reflect.DeepEqual(removed, names[:1]) {
+		t.Errorf("removed %v, expected %v", removed, names[:1])
+	}
+	backups, err := store.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if !reflect.DeepEqual(backups, names[1:]) {
+		t.Errorf("after prune, backups were %v", backups)
+	}
+
+	if err := os.WriteFile(policyFile, []byte(`{"hourly": -1}`), 0600); err != nil {
+		t.Fatalf("failed to write retention policy: %v", err)
+	}
+	if _, err := cleanup.Prune(t.Context(), false); err == nil || !strings.Contains(err.Error(), "negative") {
+		t.Errorf("expected an invalid policy to stop the prune, got %v", err)
+	}
+}
+
+func TestBackupCleanupSkipsPinned(t *testing.T) {
+	p, err := vfs.Context.BuildVfsPath(filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := backup.NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+
+	now := time.Now()
+	var names []string
+	for i := 3; i >= 0; i-- {
+		info := &protoetcd.BackupInfo{EtcdVersion: "3.5.0", Timestamp: now.Add(-time.Duration(i) * 2 * time.Hour).Unix()}
+		name, err := store.AddBackup("", "000001", info)
+		if err != nil {
+			t.Fatalf("AddBackup failed: %v", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := store.(backup.PinStore).PinBackup(names[0], &protoetcd.BackupPin{Reason: "compliance"}); err != nil {
+		t.Fatalf("PinBackup failed: %v", err)
+	}
+	if err := store.(backup.PinStore).PinBackup(names[1], &protoetcd.BackupPin{Expiry: now.Add(-time.Minute).Unix()}); err != nil {
+		t.Fatalf("PinBackup failed: %v", err)
+	}
+
+	policyFile := filepath.Join(t.TempDir(), "retention.json")
+	if err := os.WriteFile(policyFile, []byte(`{"keepLast": 1}`), 0600); err != nil {
+		t.Fatalf("failed to write retention policy: %v", err)
+	}
+	t.Setenv(RetentionPolicyFileEnv, policyFile)
+
+	removed, err := NewBackupCleanup(store).Prune(t.Context(), false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	// The expired pin no longer protects its backup
+	if expected := []string{names[1], names[2]}; !reflect.DeepEqual(removed, expected) {
+		t.Errorf("removed %v, expected %v", removed, expected)
+	}
+
+	backups, err := store.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if expected := []string{names[0], names[3]}; !reflect.DeepEqual(backups, expected) {
+		t.Errorf("after prune, backups were %v, expected %v", backups, expected)
+	}
+}
+
+func TestBackupCleanupDryRunChecksPins(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "backups")
+	p, err := vfs.Context.BuildVfsPath(dir)
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := backup.NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+
+	now := time.Now()
+	var names []string
+	for i := 2; i >= 0; i-- {
+		info := &protoetcd.BackupInfo{EtcdVersion: "3.5.0", Timestamp: now.Add(-time.Duration(i) * 2 * time.Hour).Unix()}
+		name, err := store.AddBackup("", "000001", info)
+		if err != nil {
+			t.Fatalf("AddBackup failed: %v", err)
+		}
+		names = append(names, name)
+	}
+	if _, err := store.ListBackups(); err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+
+	// A pin the catalog has not recorded, as if added concurrently by another process
+	if err := os.WriteFile(filepath.Join(dir, names[0], backup.PinFilename), []byte(`{"reason":"keep"}`), 0600); err != nil {
+		t.Fatalf("failed to write pin: %v", err)
+	}
+
+	policyFile := filepath.Join(t.TempDir(), "retention.json")
+	if err := os.WriteFile(policyFile, []byte(`{"keepLast": 1}`), 0600); err != nil {
+		t.Fatalf("failed to write retention policy: %v", err)
+	}
+	t.Setenv(RetentionPolicyFileEnv, policyFile)
+
+	cleanup := NewBackupCleanup(store)
+	wouldRemove, err := cleanup.Prune(t.Context(), true)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	removed, err := cleanup.Prune(t.Context(), false)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if expected := []string{names[1]}; !reflect.DeepEqual(wouldRemove, expected) || !reflect.DeepEqual(removed, expected) {
+		t.Errorf("dry run would remove %v and prune removed %v, expected %v", wouldRemove, removed, expected)
+	}
+}
@@ -0,0 +1,382 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+// cronSearchLimit bounds how far ahead we look for the next time matching a cron schedule
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// CronSchedule is a standard 5-field cron expression (minute hour day-of-month month day-of-week), evaluated in UTC
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar record whether the day fields were unrestricted;
+	// as in cron, when both are restricted a day matches if either matches
+	domStar, dowStar bool
+}
+
+var cronShortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseCronSchedule parses a cron expression, such as "30 2 * * *" or "@daily"
+func ParseCronSchedule(spec string) (*CronSchedule, error) {
+	expr := strings.TrimSpace(spec)
+	if shortcut, found := cronShortcuts[strings.ToLower(expr)]; found {
+		expr = shortcut
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron schedule %q must have 5 fields (minute hour day-of-month month day-of-week)", spec)
+	}
+
+	s := &CronSchedule{}
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute in cron schedule %q: %v", spec, err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour in cron schedule %q: %v", spec, err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day-of-month in cron schedule %q: %v", spec, err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid month in cron schedule %q: %v", spec, err)
+	}
+	// We accept 7 as Sunday, as most crons do
+	if s.dow, err = parseCronField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("invalid day-of-week in cron schedule %q: %v", spec, err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 << 0
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+
+	if s.Next(time.Unix(0, 0)).IsZero() {
+		return nil, fmt.Errorf("cron schedule %q never matches", spec)
+	}
+	return s, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges (a-b) and steps (*/n, a-b/n, a/n) into a bitmask.
+// If names is set, names[i] may be used for min+i.
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeSpec, step := part, 1
+		if i := strings.Index(part, "/"); i != -1 {
+			rangeSpec = part[:i]
+			v, err := strconv.Atoi(part[i+1:])
+			if err != nil || v <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = v
+		}
+
+		var start, end int
+		switch {
+		case rangeSpec == "*":
+			start, end = min, max
+		case strings.Contains(rangeSpec, "-"):
+			i := strings.Index(rangeSpec, "-")
+			var err error
+			if start, err = parseCronValue(rangeSpec[:i], min, max, names); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(rangeSpec[i+1:], min, max, names); err != nil {
+				return 0, err
+			}
+			if end < start {
+				return 0, fmt.Errorf("invalid range %q", rangeSpec)
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(rangeSpec, min, max, names); err != nil {
+				return 0, err
+			}
+			end = start
+			if step != 1 {
+				// "a/n" means every n starting at a
+				end = max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+// dayMatches returns true if the day of t matches the day-of-month and day-of-week fields
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next returns the first time matching the schedule that is strictly after t, or the zero time if there is none
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// BlackoutWindow is a daily period (in UTC) during which scheduled backups are not taken
+type BlackoutWindow struct {
+	// Start and End are minutes since midnight; a window with End before Start spans midnight
+	Start, End int
+}
+
+// ParseBlackoutWindows parses a comma-separated list of windows, such as "08:00-11:00,22:30-01:00"
+func ParseBlackoutWindows(s string) ([]BlackoutWindow, error) {
+	var windows []BlackoutWindow
+	for _, spec := range strings.Split(s, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		tokens := strings.Split(spec, "-")
+		if len(tokens) != 2 {
+			return nil, fmt.Errorf("blackout window %q must be of the form HH:MM-HH:MM", spec)
+		}
+		start, err := parseTimeOfDay(tokens[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start of blackout window %q: %v", spec, err)
+		}
+		end, err := parseTimeOfDay(tokens[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid end of blackout window %q: %v", spec, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("blackout window %q is empty", spec)
+		}
+		windows = append(windows, BlackoutWindow{Start: start, End: end})
+	}
+	return windows, nil
+}
+
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains returns true if t falls within the window
+func (w BlackoutWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	m := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return m >= w.Start && m < w.End
+	}
+	return m >= w.Start || m < w.End
+}
+
+func (w BlackoutWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// BackupSchedule decides when scheduled backups are due.
+// The time of the last scheduled backup is read from the backup store, so that a new leader neither repeats
+// nor skips the slot the previous leader was responsible for.
+type BackupSchedule struct {
+	cron      *CronSchedule
+	blackouts []BlackoutWindow
+	jitter    time.Duration
+
+	// lastBackup caches the time of the last scheduled backup, so we only read the store when a slot may be due
+	lastBackup time.Time
+}
+
+// NewBackupSchedule builds a BackupSchedule from a cron expression, optional blackout windows, and the maximum jitter to add to each slot
+func NewBackupSchedule(cronSpec string, blackoutWindows string, jitter time.Duration) (*BackupSchedule, error) {
+	cron, err := ParseCronSchedule(cronSpec)
+	if err != nil {
+		return nil, err
+	}
+	blackouts, err := ParseBlackoutWindows(blackoutWindows)
+	if err != nil {
+		return nil, err
+	}
+	if jitter < 0 {
+		return nil, fmt.Errorf("backup jitter must not be negative")
+	}
+	return &BackupSchedule{
+		cron:      cron,
+		blackouts: blackouts,
+		jitter:    jitter,
+	}, nil
+}
+
+// jitterFor returns the delay to add to a slot.  It is derived from the slot and the store, rather than random,
+// so that every leader agrees on when a slot is due, while clusters sharing a schedule are spread out.
+func (s *BackupSchedule) jitterFor(slot time.Time, seed string) time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%d", seed, slot.Unix())
+	return time.Duration(h.Sum64() % uint64(s.jitter))
+}
+
+// dueAt returns when the first slot after lastBackup is due, or the zero time if a backup is due immediately
+func (s *BackupSchedule) dueAt(lastBackup time.Time, seed string) time.Time {
+	if lastBackup.IsZero() {
+		return time.Time{}
+	}
+	slot := s.cron.Next(lastBackup)
+	if slot.IsZero() {
+		// Unreachable for schedules we have parsed, which all match within the search limit
+		return lastBackup.Add(cronSearchLimit)
+	}
+	return slot.Add(s.jitterFor(slot, seed))
+}
+
+// inBlackout returns the blackout window containing t, if any
+func (s *BackupSchedule) inBlackout(t time.Time) *BlackoutWindow {
+	for i := range s.blackouts {
+		if s.blackouts[i].Contains(t) {
+			return &s.blackouts[i]
+		}
+	}
+	return nil
+}
+
+// ShouldBackup returns true if a scheduled backup is due at now.
+// A slot that falls in a blackout window is deferred until the window ends.
+func (s *BackupSchedule) ShouldBackup(backupStore backup.Store, now time.Time) (bool, error) {
+	seed := backupStore.Spec()
+	if !s.lastBackup.IsZero() && now.Before(s.dueAt(s.lastBackup, seed)) {
+		return false, nil
+	}
+
+	// We never back up during a blackout, so we needn't check the store
+	if w := s.inBlackout(now); w != nil {
+		klog.V(2).Infof("scheduled backup may be due, but deferring it until the end of blackout window %s", w)
+		return false, nil
+	}
+
+	// Another leader may have taken the backup for this slot; the store is authoritative
+	lastBackup, err := LastScheduledBackup(backupStore)
+	if err != nil {
+		return false, err
+	}
+	if lastBackup.After(s.lastBackup) {
+		s.lastBackup = lastBackup
+	}
+
+	due := s.dueAt(s.lastBackup, seed)
+	if now.Before(due) {
+		klog.V(2).Infof("next scheduled backup is due at %s", due.UTC().Format(time.RFC3339))
+		return false, nil
+	}
+	return true, nil
+}
+
+// BackupTaken records that the named scheduled backup was taken
+func (s *BackupSchedule) BackupTaken(name string) {
+	if i := parseBackupNameInfo(name); i != nil && i.Timestamp.After(s.lastBackup) {
+		s.lastBackup = i.Timestamp
+	}
+}
+
+// LastScheduledBackup returns the time of the newest scheduled backup in the store, or the zero time if there are none.
+// Backups with a label (on-demand and pre-change backups) do not count against the schedule.
+func LastScheduledBackup(backupStore backup.Store) (time.Time, error) {
+	entries, err := backup.ListEntries(backupStore)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var last time.Time
+	for _, entry := range entries {
+		if entry.Info.GetLabel() != "" {
+			continue
+		}
+		i := parseBackupNameInfo(entry.Name)
+		if i == nil {
+			continue
+		}
+		if i.Timestamp.After(last) {
+			last = i.Timestamp
+		}
+	}
+	return last, nil
+}
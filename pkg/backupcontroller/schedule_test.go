@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, 3, 18, 12, 30, 0, 0, time.UTC)
+
+	grid := []struct {
+		Spec     string
+		Expected time.Time
+	}{
+		{Spec: "* * * * *", Expected: time.Date(2026, 3, 18, 12, 31, 0, 0, time.UTC)},
+		{Spec: "*/15 * * * *", Expected: time.Date(2026, 3, 18, 12, 45, 0, 0, time.UTC)},
+		{Spec: "30 2 * * *", Expected: time.Date(2026, 3, 19, 2, 30, 0, 0, time.UTC)},
+		{Spec: "@hourly", Expected: time.Date(2026, 3, 18, 13, 0, 0, 0, time.UTC)},
+		{Spec: "@daily", Expected: time.Date(2026, 3, 19, 0, 0, 0, 0, time.UTC)},
+		{Spec: "0 0 * * sun", Expected: time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC)},
+		{Spec: "0 0 * * 7", Expected: time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC)},
+		{Spec: "0 9-17/4 * * mon-fri", Expected: time.Date(2026, 3, 18, 13, 0, 0, 0, time.UTC)},
+		{Spec: "0 3 1 jan,jul *", Expected: time.Date(2026, 7, 1, 3, 0, 0, 0, time.UTC)},
+		// When both day fields are restricted, either matches
+		{Spec: "0 0 1 * fri", Expected: time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)},
+		{Spec: "0 0 29 2 *", Expected: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Spec, func(t *testing.T) {
+			s, err := ParseCronSchedule(g.Spec)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule failed: %v", err)
+			}
+			if actual := s.Next(from); !actual.Equal(g.Expected) {
+				t.Errorf("Next(%s): expected %s, got %s", from, g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseCronScheduleInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "0 0 30 feb *", "@often"} {
+		if _, err := ParseCronSchedule(spec); err == nil {
+			t.Errorf("expected error parsing %q", spec)
+		}
+	}
+}
+
+func TestParseBlackoutWindows(t *testing.T) {
+	windows, err := ParseBlackoutWindows("08:00-11:00, 22:30-01:00")
+	if err != nil {
+		t.Fatalf("ParseBlackoutWindows failed: %v", err)
+	}
+	if len(windows) != 2 || windows[0].String() != "08:00-11:00" || windows[1].String() != "22:30-01:00" {
+		t.Fatalf("unexpected windows %v", windows)
+	}
+
+	grid := []struct {
+		Time     string
+		Expected bool
+	}{
+		{"07:59", false},
+		{"08:00", true},
+		{"10:59", true},
+		{"11:00", false},
+		{"22:29", false},
+		{"23:59", true},
+		{"00:30", true},
+		{"01:00", false},
+	}
+	for _, g := range grid {
+		tod, err := time.Parse("15:04", g.Time)
+		if err != nil {
+			t.Fatalf("error parsing %q: %v", g.Time, err)
+		}
+		at := time.Date(2026, 3, 18, tod.Hour(), tod.Minute(), 0, 0, time.UTC)
+		actual := windows[0].Contains(at) || windows[1].Contains(at)
+		if actual != g.Expected {
+			t.Errorf("blackout at %s: expected %v, got %v", g.Time, g.Expected, actual)
+		}
+	}
+
+	for _, s := range []string{"08:00", "08:00-08:00", "8am-9am", "08:00-25:00"} {
+		if _, err := ParseBlackoutWindows(s); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
+
+func addTestBackup(t *testing.T, store backup.Store, at time.Time, label string) string {
+	name, err := store.AddBackup("", "000001", &protoetcd.BackupInfo{EtcdVersion: "3.5.0", Timestamp: at.Unix(), Label: label})
+	if err != nil {
+		t.Fatalf("AddBackup failed: %v", err)
+	}
+	return name
+}
+
+func TestBackupScheduleShouldBackup(t *testing.T) {
+	p, err := vfs.Context.BuildVfsPath(filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := backup.NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+
+	day := time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	shouldBackup := func(s *BackupSchedule, now time.Time) bool {
+		due, err := s.ShouldBackup(store, now)
+		if err != nil {
+			t.Fatalf("ShouldBackup failed: %v", err)
+		}
+		return due
+	}
+
+	schedule, err := NewBackupSchedule("0 */6 * * *", "05:00-07:00", 0)
+	if err != nil {
+		t.Fatalf("NewBackupSchedule failed: %v", err)
+	}
+
+	// With no backups, we take one straight away, unless we're in a blackout
+	if !shouldBackup(schedule, at(1, 0)) {
+		t.Errorf("expected a backup to be due with an empty store")
+	}
+	if shouldBackup(schedule, at(5, 30)) {
+		t.Errorf("expected no backup during a blackout")
+	}
+
+	first := addTestBackup(t, store, at(2, 0), "")
+	schedule.BackupTaken(first)
+	if shouldBackup(schedule, at(2, 1)) {
+		t.Errorf("expected no backup before the next slot")
+	}
+
+	// Labelled backups don't count against the schedule
+	addTestBackup(t, store, at(4, 0), "pre-upgrade")
+
+	// The 06:00 slot falls in the blackout, so is deferred until it ends
+	if shouldBackup(schedule, at(6, 0)) {
+		t.Errorf("expected the 06:00 slot to be deferred by the blackout")
+	}
+	if !shouldBackup(schedule, at(7, 0)) {
+		t.Errorf("expected the 06:00 slot to be taken when the blackout ends")
+	}
+
+	// Another leader took the backup; a new leader (with no cached state) must not repeat it
+	addTestBackup(t, store, at(7, 0), "")
+	if shouldBackup(schedule, at(7, 1)) {
+		t.Errorf("expected the backup taken by another leader to be noticed")
+	}
+	newLeader, err := NewBackupSchedule("0 */6 * * *", "05:00-07:00", 0)
+	if err != nil {
+		t.Fatalf("NewBackupSchedule failed: %v", err)
+	}
+	if shouldBackup(newLeader, at(11, 59)) {
+		t.Errorf("expected the new leader not to repeat the 06:00 slot")
+	}
+	// ... nor skip the next one
+	if !shouldBackup(newLeader, at(12, 0)) {
+		t.Errorf("expected the new leader to take the 12:00 slot")
+	}
+}
+
+func TestBackupScheduleJitter(t *testing.T) {
+	s, err := NewBackupSchedule("@hourly", "", 10*time.Minute)
+	if err != nil {
+		t.Fatalf("NewBackupSchedule failed: %v", err)
+	}
+	other, err := NewBackupSchedule("@hourly", "", 10*time.Minute)
+	if err != nil {
+		t.Fatalf("NewBackupSchedule failed: %v", err)
+	}
+
+	slot := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	varied := false
+	for i := 0; i < 24; i++ {
+		jitter := s.jitterFor(slot, "s3://bucket/cluster1")
+		if jitter < 0 || jitter >= 10*time.Minute {
+			t.Errorf("jitter %s for slot %s out of range", jitter, slot)
+		}
+		// Every leader must agree on when a slot is due
+		if otherJitter := other.jitterFor(slot, "s3://bucket/cluster1"); otherJitter != jitter {
+			t.Errorf("jitter for slot %s differs between schedules: %s vs %s", slot, jitter, otherJitter)
+		}
+		if s.jitterFor(slot, "s3://bucket/cluster2") != jitter {
+			varied = true
+		}
+		slot = slot.Add(time.Hour)
+	}
+	if !varied {
+		t.Errorf("expected jitter to differ between stores")
+	}
+
+	if _, err := NewBackupSchedule("@hourly", "", -time.Minute); err == nil {
+		t.Errorf("expected negative jitter to be rejected")
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/klog/v2"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+	"sigs.k8s.io/etcd-manager/pkg/pki"
+)
+
+// CAKeypairNames are the CA keypairs in the pki directory that are archived with the cluster state
+var CAKeypairNames = []string{"etcd-manager-ca", "etcd-peers-ca", "etcd-clients-ca"}
+
+// StateArchiver archives the cluster state (the CA keypairs and expected cluster spec) alongside each backup
+type StateArchiver struct {
+	backupStore backup.Store
+	pkiStore    *pki.FSStore
+	key         *backup.EncryptionKey
+}
+
+// NewStateArchiver builds a StateArchiver, reading the CA keypairs from pkiDir, and encrypting the state with key
+func NewStateArchiver(backupStore backup.Store, pkiDir string, key *backup.EncryptionKey) *StateArchiver {
+	return &StateArchiver{
+		backupStore: backupStore,
+		pkiStore:    pki.NewFSStore(pkiDir),
+		key:         key,
+	}
+}
+
+// ArchiveState saves the current cluster state alongside the named backup
+func (a *StateArchiver) ArchiveState(backupName string, clusterSpec *protoetcd.ClusterSpec) error {
+	state, err := CaptureState(a.pkiStore, clusterSpec)
+	if err != nil {
+		return err
+	}
+	states, ok := a.backupStore.(backup.StateStore)
+	if !ok {
+		return fmt.Errorf("backup store %s does not support archiving the cluster state", a.backupStore.Spec())
+	}
+	if err := states.SaveState(backupName, state, a.key); err != nil {
+		return err
+	}
+	klog.Infof("archived cluster state with %d CA keypairs alongside backup %q", len(state.Keypairs), backupName)
+	return nil
+}
+
+// CaptureState reads the cluster state to archive: the CA keypairs in pkiStore, and clusterSpec.
+// Keypairs that are not present (for example, with insecure configurations) are skipped.
+func CaptureState(pkiStore *pki.FSStore, clusterSpec *protoetcd.ClusterSpec) (*protoetcd.BackupState, error) {
+	state := &protoetcd.BackupState{
+		Timestamp:   time.Now().Unix(),
+		ClusterSpec: clusterSpec,
+	}
+
+	for _, name := range CAKeypairNames {
+		certificates, privateKey, err := pkiStore.ReadKeypairPEM(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				klog.V(2).Infof("CA keypair %q not found, not archiving it", name)
+				continue
+			}
+			return nil, fmt.Errorf("error reading CA keypair %q: %w", name, err)
+		}
+		state.Keypairs = append(state.Keypairs, &protoetcd.BackupStateKeypair{
+			Name:         name,
+			Certificates: certificates,
+			PrivateKey:   privateKey,
+		})
+	}
+
+	return state, nil
+}
+
+// RestoreState writes the CA keypairs from state into pkiStore, returning the names of the keypairs written.
+// Keypairs that are already present and identical are left alone; keypairs that differ are only replaced if overwrite is set,
+// because replacing a CA stops existing clients trusting the cluster.
+func RestoreState(state *protoetcd.BackupState, pkiStore *pki.FSStore, overwrite bool) ([]string, error) {
+	// Check everything before writing anything, so we don't leave a mix of old and restored CAs
+	var pending []*protoetcd.BackupStateKeypair
+	for _, keypair := range state.Keypairs {
+		certificates, privateKey, err := pkiStore.ReadKeypairPEM(keypair.Name)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("error reading CA keypair %q: %w", keypair.Name, err)
+			}
+		} else {
+			if bytes.Equal(certificates, keypair.Certificates) && bytes.Equal(privateKey, keypair.PrivateKey) {
+				klog.Infof("CA keypair %q already matches the archived state", keypair.Name)
+				continue
+			}
+			if !overwrite {
+				return nil, fmt.Errorf("CA keypair %q already exists and differs from the archived state", keypair.Name)
+			}
+		}
+		pending = append(pending, keypair)
+	}
+
+	var restored []string
+	for _, keypair := range pending {
+		if err := pkiStore.WriteKeypairPEM(keypair.Name, keypair.Certificates, keypair.PrivateKey); err != nil {
+			return restored, err
+		}
+		restored = append(restored, keypair.Name)
+	}
+	return restored, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupcontroller
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/pki"
+)
+
+// writeTestCA generates a CA, and writes it to store as name
+func writeTestCA(t *testing.T, store *pki.FSStore, name string) {
+	scratch := pki.NewFSStore(t.TempDir())
+	if _, err := pki.NewCA(scratch); err != nil {
+		t.Fatalf("NewCA failed: %v", err)
+	}
+	certificates, privateKey, err := scratch.ReadKeypairPEM("ca")
+	if err != nil {
+		t.Fatalf("ReadKeypairPEM failed: %v", err)
+	}
+	if err := store.WriteKeypairPEM(name, certificates, privateKey); err != nil {
+		t.Fatalf("WriteKeypairPEM failed: %v", err)
+	}
+}
+
+func TestCaptureAndRestoreState(t *testing.T) {
+	source := pki.NewFSStore(t.TempDir())
+	writeTestCA(t, source, "etcd-peers-ca")
+	writeTestCA(t, source, "etcd-clients-ca")
+
+	clusterSpec := &protoetcd.ClusterSpec{MemberCount: 3, EtcdVersion: "3.5.9"}
+	state, err := CaptureState(source, clusterSpec)
+	if err != nil {
+		t.Fatalf("CaptureState failed: %v", err)
+	}
+	// etcd-manager-ca is missing, as with an insecure etcd-manager
+	var names []string
+	for _, keypair := range state.Keypairs {
+		names = append(names, keypair.Name)
+	}
+	if expected := []string{"etcd-peers-ca", "etcd-clients-ca"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("captured keypairs %v, expected %v", names, expected)
+	}
+
+	dest := pki.NewFSStore(t.TempDir())
+	restored, err := RestoreState(state, dest, false)
+	if err != nil {
+		t.Fatalf("RestoreState failed: %v", err)
+	}
+	if !reflect.DeepEqual(restored, names) {
+		t.Errorf("restored keypairs %v, expected %v", restored, names)
+	}
+	for _, name := range names {
+		if _, err := dest.LoadCA(name); err != nil {
+			t.Errorf("restored CA %q could not be loaded: %v", name, err)
+		}
+		expectedCertificates, expectedKey, _ := source.ReadKeypairPEM(name)
+		certificates, key, err := dest.ReadKeypairPEM(name)
+		if err != nil || !bytes.Equal(certificates, expectedCertificates) || !bytes.Equal(key, expectedKey) {
+			t.Errorf("restored CA %q did not match (err=%v)", name, err)
+		}
+	}
+
+	// Restoring again is a no-op
+	restored, err = RestoreState(state, dest, false)
+	if err != nil || len(restored) != 0 {
+		t.Errorf("expected nothing to be restored, got %v (err=%v)", restored, err)
+	}
+
+	// A different CA is only replaced with overwrite, and nothing is written if any CA conflicts
+	other := pki.NewFSStore(t.TempDir())
+	writeTestCA(t, other, "etcd-clients-ca")
+	if _, err := RestoreState(state, other, false); err == nil {
+		t.Fatalf("expected an error restoring over a different CA")
+	}
+	if _, _, err := other.ReadKeypairPEM("etcd-peers-ca"); err == nil {
+		t.Errorf("expected no keypairs to be written when a CA conflicts")
+	}
+	restored, err = RestoreState(state, other, true)
+	if err != nil {
+		t.Fatalf("RestoreState with overwrite failed: %v", err)
+	}
+	if !reflect.DeepEqual(restored, names) {
+		t.Errorf("restored keypairs %v, expected %v", restored, names)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ReadClusterCreatedMarker returns the contents of the cluster-creation marker in the backup store,
+// or nil if the cluster has not been created.  The marker records when the cluster was created,
+// so two stores with the same marker hold the same cluster.
+// Every location of a replicated store is read, and locations holding different markers are an error.
+func ReadClusterCreatedMarker(storage string) ([]byte, error) {
+	bases, err := storeBases(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	var marker []byte
+	for _, base := range bases {
+		data, err := newVFSStore(base).readClusterCreatedMarker()
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			continue
+		}
+		if marker != nil && !bytes.Equal(marker, data) {
+			return nil, fmt.Errorf("locations of %s hold different cluster-creation markers", storage)
+		}
+		marker = data
+	}
+	return marker, nil
+}
+
+// WriteClusterCreatedMarker writes the cluster-creation marker in every location of the backup store, as read by ReadClusterCreatedMarker.
+// It is used when moving a cluster to a new store; MarkClusterCreated creates the marker for a new cluster.
+func WriteClusterCreatedMarker(storage string, data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("cluster-creation marker must not be empty")
+	}
+
+	bases, err := storeBases(storage)
+	if err != nil {
+		return err
+	}
+
+	for _, base := range bases {
+		if err := newVFSStore(base).writeClusterCreatedMarker(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/klog/v2"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// replicatedStore is a Store that keeps the control state in every location of a replicated backup store.
+// Writes go to every location, so that losing any one location does not lose the control state;
+// reads use the first location that can serve them.
+type replicatedStore struct {
+	stores []*vfsStore
+}
+
+var _ Store = &replicatedStore{}
+
+// replicatedCommand is a command read from one location of a replicatedStore
+type replicatedCommand struct {
+	data *protoetcd.Command
+
+	// name is the name of the directory holding the command
+	name string
+}
+
+var _ Command = &replicatedCommand{}
+
+func (c *replicatedCommand) Data() *protoetcd.Command {
+	return c.data
+}
+
+// writeAll runs fn against every location, returning an error if any of them failed.
+// Every location is attempted, so a location that is briefly unavailable does not stop the others being written.
+func (s *replicatedStore) writeAll(description string, fn func(store *vfsStore) error) error {
+	var errs []error
+	for _, store := range s.stores {
+		if err := fn(store); err != nil {
+			klog.Warningf("error %s in %s: %v", description, store.commandsBase, err)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("error %s in %d of %d locations: %w", description, len(errs), len(s.stores), errors.Join(errs...))
+	}
+	return nil
+}
+
+func (s *replicatedStore) IsNewCluster() (bool, error) {
+	// A location added after the cluster was created has no marker, so the cluster is only new if no location
+	// has the marker; we cannot be sure of that while a location is unreachable.
+	var errs []error
+	for _, store := range s.stores {
+		isNew, err := store.IsNewCluster()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !isNew {
+			return false, nil
+		}
+	}
+	if len(errs) != 0 {
+		return false, fmt.Errorf("unable to check every location for the cluster-creation marker: %w", errors.Join(errs...))
+	}
+	return true, nil
+}
+
+func (s *replicatedStore) MarkClusterCreated() error {
+	// Every location gets the same marker, so they can be recognized as holding the same cluster
+	data, err := newClusterCreatedMarker()
+	if err != nil {
+		return err
+	}
+	return s.writeAll("creating cluster-creation marker", func(store *vfsStore) error {
+		return store.writeClusterCreatedMarker(data)
+	})
+}
+
+func (s *replicatedStore) GetExpectedClusterSpec() (*protoetcd.ClusterSpec, error) {
+	var errs []error
+	for _, store := range s.stores {
+		spec, err := store.GetExpectedClusterSpec()
+		if err != nil {
+			klog.Warningf("error reading cluster spec from %s: %v", store.commandsBase, err)
+			errs = append(errs, err)
+			continue
+		}
+		// A location added later may not have the spec yet
+		if spec != nil {
+			return spec, nil
+		}
+	}
+	if len(errs) == len(s.stores) {
+		return nil, errors.Join(errs...)
+	}
+	return nil, nil
+}
+
+func (s *replicatedStore) SetExpectedClusterSpec(spec *protoetcd.ClusterSpec) error {
+	return s.writeAll("writing cluster spec", func(store *vfsStore) error {
+		return store.SetExpectedClusterSpec(spec)
+	})
+}
+
+func (s *replicatedStore) AddCommand(cmd *protoetcd.Command) error {
+	// The timestamp names the command, so it must be the same in every location
+	cmd.Timestamp = time.Now().UnixNano()
+
+	return s.writeAll("adding command", func(store *vfsStore) error {
+		return store.addCommand(cmd)
+	})
+}
+
+func (s *replicatedStore) ListCommands() ([]Command, error) {
+	var errs []error
+	for _, store := range s.stores {
+		commands, err := store.ListCommands()
+		if err != nil {
+			klog.Warningf("error listing commands in %s: %v", store.commandsBase, err)
+			errs = append(errs, err)
+			continue
+		}
+
+		var replicated []Command
+		for _, command := range commands {
+			replicated = append(replicated, &replicatedCommand{data: command.Data(), name: commandDir(command)})
+		}
+		return replicated, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (s *replicatedStore) RemoveCommand(command Command) error {
+	name := commandDir(command)
+	return s.writeAll("removing command", func(store *vfsStore) error {
+		err := removeCommandFile(store.commandsBase.Join(name, CommandFilename))
+		// A location that was unavailable when the command was added will not have it
+		if err != nil && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	})
+}
+
+func (s *replicatedStore) SetCommandResult(command Command, result *protoetcd.CommandResult) error {
+	return s.writeAll("writing command result", func(store *vfsStore) error {
+		return store.SetCommandResult(command, result)
+	})
+}
+
+func (s *replicatedStore) GetCommandResult(cmd *protoetcd.Command) (*protoetcd.CommandResult, error) {
+	var errs []error
+	for _, store := range s.stores {
+		result, err := store.GetCommandResult(cmd)
+		if err != nil {
+			klog.Warningf("error reading command result from %s: %v", store.commandsBase, err)
+			errs = append(errs, err)
+			continue
+		}
+		if result != nil {
+			return result, nil
+		}
+	}
+	if len(errs) == len(s.stores) {
+		return nil, errors.Join(errs...)
+	}
+	return nil, nil
+}
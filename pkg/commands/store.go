@@ -17,12 +17,22 @@ limitations under the License.
 package commands
 
 import (
+	"fmt"
+	"time"
+
 	"k8s.io/kops/util/pkg/vfs"
 	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
 )
 
 const CommandFilename = "_command.json"
 
+// CommandResultFilename holds the result of a command, and is kept for CommandResultRetention after the command is removed
+const CommandResultFilename = "_result.json"
+
+// CommandResultRetention is how long the results of completed commands are kept
+const CommandResultRetention = 7 * 24 * time.Hour
+
 type Store interface {
 	// IsNewCluster indicates if it is safe to create a new cluster
 	IsNewCluster() (bool, error)
@@ -43,16 +53,50 @@ type Store interface {
 
 	// RemoveCommand marks a command as complete
 	RemoveCommand(command Command) error
+
+	// SetCommandResult records the result of executing a command
+	SetCommandResult(command Command, result *protoetcd.CommandResult) error
+
+	// GetCommandResult returns the result of a command (as passed to AddCommand), or nil if it has not been executed
+	GetCommandResult(cmd *protoetcd.Command) (*protoetcd.CommandResult, error)
 }
 
 type Command interface {
 	Data() *protoetcd.Command
 }
 
+// NewStore builds the command store for a backup store spec.  When backups are replicated to several locations,
+// the control state is written to every location, and read from the first location that can serve it.
 func NewStore(storage string) (Store, error) {
-	p, err := vfs.Context.BuildVfsPath(storage)
+	bases, err := storeBases(storage)
 	if err != nil {
 		return nil, err
 	}
-	return NewVFSStore(p)
+	if len(bases) == 1 {
+		return NewVFSStore(bases[0])
+	}
+
+	var stores []*vfsStore
+	for _, base := range bases {
+		stores = append(stores, newVFSStore(base))
+	}
+	return &replicatedStore{stores: stores}, nil
+}
+
+// storeBases returns the locations of the backup store that hold the control state
+func storeBases(storage string) ([]vfs.Path, error) {
+	locations := backup.SplitStoreSpec(storage)
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("backup store location is required")
+	}
+
+	var bases []vfs.Path
+	for _, location := range locations {
+		p, err := vfs.Context.BuildVfsPath(location)
+		if err != nil {
+			return nil, err
+		}
+		bases = append(bases, p)
+	}
+	return bases, nil
 }
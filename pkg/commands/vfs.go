@@ -22,7 +22,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"sort"
+	"strings"
 	"time"
 
 	"k8s.io/klog/v2"
@@ -34,10 +36,13 @@ const EtcdClusterCreated = "etcd-cluster-created"
 const EtcdClusterSpec = "etcd-cluster-spec"
 
 func NewVFSStore(p vfs.Path) (Store, error) {
-	s := &vfsStore{
+	return newVFSStore(p), nil
+}
+
+func newVFSStore(p vfs.Path) *vfsStore {
+	return &vfsStore{
 		commandsBase: p.Join("control"),
 	}
-	return s, nil
 }
 
 type vfsStore struct {
@@ -49,6 +54,9 @@ var _ Store = &vfsStore{}
 type vfsCommand struct {
 	p    vfs.Path
 	data *protoetcd.Command
+
+	// name is the name of the directory holding the command
+	name string
 }
 
 var _ Command = &vfsCommand{}
@@ -58,14 +66,16 @@ func (c *vfsCommand) Data() *protoetcd.Command {
 }
 
 func (s *vfsStore) AddCommand(cmd *protoetcd.Command) error {
-	ctx := context.TODO()
+	cmd.Timestamp = time.Now().UnixNano()
 
-	sequence := "000000"
-	now := time.Now()
+	return s.addCommand(cmd)
+}
 
-	cmd.Timestamp = now.UnixNano()
+// addCommand writes a command, which must already have its timestamp
+func (s *vfsStore) addCommand(cmd *protoetcd.Command) error {
+	ctx := context.TODO()
 
-	name := now.UTC().Format(time.RFC3339) + "-" + sequence
+	name := commandName(cmd)
 
 	// Save the command file
 	{
@@ -84,6 +94,40 @@ func (s *vfsStore) AddCommand(cmd *protoetcd.Command) error {
 	return nil
 }
 
+// commandName is the name of the directory holding a command, derived from its timestamp.
+// The sequence is the microseconds of the timestamp, so that commands added in the same second do not collide.
+func commandName(cmd *protoetcd.Command) string {
+	t := time.Unix(0, cmd.Timestamp).UTC()
+	sequence := fmt.Sprintf("%06d", t.Nanosecond()/1000)
+	return t.Format(time.RFC3339) + "-" + sequence
+}
+
+// legacyCommandName is the name earlier versions gave the directory holding a command, with a fixed sequence
+func legacyCommandName(cmd *protoetcd.Command) string {
+	return time.Unix(0, cmd.Timestamp).UTC().Format(time.RFC3339) + "-000000"
+}
+
+// commandDir returns the name of the directory holding a command that was listed from a store
+func commandDir(command Command) string {
+	switch c := command.(type) {
+	case *vfsCommand:
+		return c.name
+	case *replicatedCommand:
+		return c.name
+	default:
+		return commandName(command.Data())
+	}
+}
+
+// parseCommandDirTime returns the time at which the command in a directory was added, to the second
+func parseCommandDirTime(name string) (time.Time, error) {
+	i := strings.LastIndex(name, "-")
+	if i == -1 {
+		return time.Time{}, fmt.Errorf("unexpected command directory name %q", name)
+	}
+	return time.Parse(time.RFC3339, name[:i])
+}
+
 func (s *vfsStore) ListCommands() ([]Command, error) {
 	ctx := context.TODO()
 
@@ -113,6 +157,10 @@ func (s *vfsStore) ListCommands() ([]Command, error) {
 			return nil, fmt.Errorf("error parsing command %q: %v", f, err)
 		}
 		command.p = f
+		command.name = f.Path()
+		if tokens := strings.Split(f.Path(), "/"); len(tokens) >= 2 {
+			command.name = tokens[len(tokens)-2]
+		}
 
 		klog.Infof("read command for %q: %v", f, command.data.String())
 
@@ -129,17 +177,99 @@ func (s *vfsStore) ListCommands() ([]Command, error) {
 }
 
 func (s *vfsStore) RemoveCommand(command Command) error {
-	p := command.(*vfsCommand).p
+	return removeCommandFile(command.(*vfsCommand).p)
+}
+
+// removeCommandFile deletes the file of a command
+func removeCommandFile(p vfs.Path) error {
 	ctx := context.TODO()
 	klog.Infof("deleting command %s", p)
 
 	if err := p.Remove(ctx); err != nil {
-		return fmt.Errorf("error removing command %s: %v", p, err)
+		return fmt.Errorf("error removing command %s: %w", p, err)
+	}
+
+	return nil
+}
+
+func (s *vfsStore) SetCommandResult(command Command, result *protoetcd.CommandResult) error {
+	ctx := context.TODO()
+
+	data, err := protoetcd.ToJson(result)
+	if err != nil {
+		return fmt.Errorf("error marshalling command result: %v", err)
+	}
+
+	p := s.commandsBase.Join(commandDir(command), CommandResultFilename)
+	klog.Infof("writing command result at %s: %v", p, result)
+	if err := p.WriteFile(ctx, bytes.NewReader([]byte(data)), nil); err != nil {
+		return fmt.Errorf("error writing file %q: %v", p.Path(), err)
+	}
+
+	if err := s.removeExpiredCommandResults(ctx, time.Now()); err != nil {
+		klog.Warningf("error removing expired command results: %v", err)
+	}
+	return nil
+}
+
+// removeExpiredCommandResults removes the results of completed commands that were added more than CommandResultRetention ago
+func (s *vfsStore) removeExpiredCommandResults(ctx context.Context, now time.Time) error {
+	files, err := s.commandsBase.ReadTree(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", s.commandsBase.Path(), err)
+	}
+
+	pending := make(map[string]bool)
+	for _, f := range files {
+		if f.Base() == CommandFilename {
+			pending[path.Dir(f.Path())] = true
+		}
 	}
 
+	for _, f := range files {
+		if f.Base() != CommandResultFilename || pending[path.Dir(f.Path())] {
+			continue
+		}
+		added, err := parseCommandDirTime(path.Base(path.Dir(f.Path())))
+		if err != nil {
+			klog.Warningf("ignoring command result %s: %v", f, err)
+			continue
+		}
+		if now.Sub(added) < CommandResultRetention {
+			continue
+		}
+		klog.Infof("removing expired command result %s", f)
+		if err := f.Remove(ctx); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing command result %s: %v", f, err)
+		}
+	}
 	return nil
 }
 
+func (s *vfsStore) GetCommandResult(cmd *protoetcd.Command) (*protoetcd.CommandResult, error) {
+	ctx := context.TODO()
+
+	p := s.commandsBase.Join(commandName(cmd), CommandResultFilename)
+	data, err := p.ReadFile(ctx)
+	if err != nil && os.IsNotExist(err) && legacyCommandName(cmd) != commandName(cmd) {
+		// The command may have been completed by an earlier version
+		p = s.commandsBase.Join(legacyCommandName(cmd), CommandResultFilename)
+		data, err = p.ReadFile(ctx)
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading file %q: %v", p.Path(), err)
+	}
+
+	result := &protoetcd.CommandResult{}
+	if err := protoetcd.FromJson(string(data), result); err != nil {
+		return nil, fmt.Errorf("error parsing command result %q: %v", p.Path(), err)
+	}
+	return result, nil
+}
+
 func (s *vfsStore) GetExpectedClusterSpec() (*protoetcd.ClusterSpec, error) {
 	ctx := context.TODO()
 
@@ -202,19 +332,47 @@ type etcdClusterCreated struct {
 }
 
 func (s *vfsStore) MarkClusterCreated() error {
-	ctx := context.TODO()
+	data, err := newClusterCreatedMarker()
+	if err != nil {
+		return err
+	}
+	return s.writeClusterCreatedMarker(data)
+}
 
+// newClusterCreatedMarker returns the contents of a cluster-creation marker for a cluster created now
+func newClusterCreatedMarker() ([]byte, error) {
 	d := &etcdClusterCreated{
 		Timestamp: time.Now().UnixNano(),
 	}
 
 	data, err := json.Marshal(d)
 	if err != nil {
-		return fmt.Errorf("error serializing cluster-creation marker file: %v", err)
+		return nil, fmt.Errorf("error serializing cluster-creation marker file: %v", err)
 	}
+	return data, nil
+}
+
+// readClusterCreatedMarker returns the contents of the cluster-creation marker, or nil if there is none
+func (s *vfsStore) readClusterCreatedMarker() ([]byte, error) {
+	ctx := context.TODO()
 
 	p := s.commandsBase.Join(EtcdClusterCreated)
-	if err := p.WriteFile(ctx, bytes.NewReader([]byte(data)), nil); err != nil {
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading cluster-creation marker file %s: %v", p.Path(), err)
+	}
+	return data, nil
+}
+
+// writeClusterCreatedMarker writes the cluster-creation marker
+func (s *vfsStore) writeClusterCreatedMarker(data []byte) error {
+	ctx := context.TODO()
+
+	p := s.commandsBase.Join(EtcdClusterCreated)
+	if err := p.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
 		return fmt.Errorf("error creating cluster-creation marker file %s: %v", p.Path(), err)
 	}
 	return nil
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/klog/v2"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+)
+
+// clusterOperation names a change to the cluster that must be preceded by a backup
+type clusterOperation string
+
+const (
+	operationAddMember      clusterOperation = "add-member"
+	operationRemoveMember   clusterOperation = "remove-member"
+	operationReplaceMember  clusterOperation = "replace-member"
+	operationQuarantine     clusterOperation = "quarantine"
+	operationLiftQuarantine clusterOperation = "lift-quarantine"
+	operationUpgrade        clusterOperation = "upgrade"
+)
+
+// preOperationBackupLabel is the label recorded on the backup taken before op
+func preOperationBackupLabel(op clusterOperation) string {
+	return "pre-" + string(op)
+}
+
+// backupBeforeChange takes a backup, labelled with op, immediately before the cluster is changed.
+// The caller must not make the change if this returns an error: we always want a recovery point from just before a mutation.
+func (m *EtcdController) backupBeforeChange(ctx context.Context, clusterSpec *protoetcd.ClusterSpec, clusterState *etcdClusterState, op clusterOperation) (*protoetcd.DoBackupResponse, error) {
+	klog.Infof("backing up cluster before %s", op)
+	response, err := m.doClusterBackup(ctx, clusterSpec, clusterState, preOperationBackupLabel(op))
+	if err != nil {
+		return nil, fmt.Errorf("failed to backup before %s, not proceeding: %w", op, err)
+	}
+	klog.Infof("backed up cluster before %s as %q", op, response.Name)
+	m.checkBackupForAnomalies(response.Name)
+	return response, nil
+}
+
+// checkBackupForAnomalies flags the backup if it differs sharply from the preceding backups.
+// An anomalous backup is still kept, so problems are only logged.
+func (m *EtcdController) checkBackupForAnomalies(name string) {
+	if m.anomalyDetector == nil {
+		return
+	}
+	if _, err := m.anomalyDetector.CheckBackup(name); err != nil {
+		klog.Warningf("error checking backup %q for anomalies: %v", name, err)
+	}
+}
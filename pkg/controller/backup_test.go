@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+func TestPreOperationBackupLabel(t *testing.T) {
+	grid := map[clusterOperation]string{
+		operationAddMember:      "pre-add-member",
+		operationRemoveMember:   "pre-remove-member",
+		operationReplaceMember:  "pre-replace-member",
+		operationQuarantine:     "pre-quarantine",
+		operationLiftQuarantine: "pre-lift-quarantine",
+		operationUpgrade:        "pre-upgrade",
+	}
+	for op, expected := range grid {
+		if actual := preOperationBackupLabel(op); actual != expected {
+			t.Errorf("preOperationBackupLabel(%q): expected %q, got %q", op, expected, actual)
+		}
+	}
+}
+
+func TestBackupBeforeChangeFailsWithoutHealthyMembers(t *testing.T) {
+	m := &EtcdController{}
+	clusterState := &etcdClusterState{
+		members: map[EtcdMemberId]*etcdclient.EtcdProcessMember{
+			"1": {ID: "1", Name: "node1"},
+		},
+		healthyMembers: map[EtcdMemberId]*etcdclient.EtcdProcessMember{},
+	}
+
+	response, err := m.backupBeforeChange(context.Background(), &protoetcd.ClusterSpec{MemberCount: 3}, clusterState, operationRemoveMember)
+	if err == nil {
+		t.Fatalf("expected backup to fail, got %v", response)
+	}
+	if !strings.Contains(err.Error(), "failed to backup before remove-member") {
+		t.Errorf("expected error to name the operation, got %v", err)
+	}
+}
@@ -25,6 +25,9 @@ import (
 	"sigs.k8s.io/etcd-manager/pkg/commands"
 )
 
+// backupNowPollInterval is how often the leader checks the control store for backup-now commands
+const backupNowPollInterval = time.Minute
+
 func (m *EtcdController) InvalidateControlStore() error {
 	return m.refreshControlStore(time.Duration(0))
 }
@@ -86,3 +89,74 @@ func (m *EtcdController) removeCommand(ctx context.Context, cmd commands.Command
 
 	return err
 }
+
+// pollBackupNowCommand returns the oldest backup-now command in m.controlCommands.
+// Backup-now commands are refreshed from the control store at most once every backupNowPollInterval;
+// unlike refreshControlStore, this does not pick up other commands or changes to the cluster spec.
+func (m *EtcdController) pollBackupNowCommand(now time.Time) (commands.Command, error) {
+	m.controlMutex.Lock()
+	defer m.controlMutex.Unlock()
+
+	if now.Sub(m.lastBackupNowPoll) >= backupNowPollInterval {
+		m.lastBackupNowPoll = now
+
+		controlCommands, err := m.controlStore.ListCommands()
+		if err != nil {
+			return nil, err
+		}
+
+		var merged []commands.Command
+		for _, c := range m.controlCommands {
+			if c.Data().BackupNow == nil {
+				merged = append(merged, c)
+			}
+		}
+		for _, c := range controlCommands {
+			if c.Data().BackupNow != nil {
+				merged = append(merged, c)
+			}
+		}
+		m.controlCommands = merged
+	}
+
+	for _, c := range m.controlCommands {
+		if c.Data().BackupNow != nil {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+// completeBackupNowCommand records the result of a backup-now command, and removes the command
+func (m *EtcdController) completeBackupNowCommand(cmd commands.Command, backup *protoetcd.DoBackupResponse, backupErr error) {
+	result := &protoetcd.CommandResult{
+		Timestamp: time.Now().UnixNano(),
+	}
+	if backup != nil {
+		result.BackupName = backup.Name
+	}
+	if backupErr != nil {
+		result.Error = backupErr.Error()
+	}
+
+	if err := m.controlStore.SetCommandResult(cmd, result); err != nil {
+		// We don't remove the command, so that the result is not lost; we will take another backup
+		klog.Warningf("error recording result of backup-now command: %v", err)
+		return
+	}
+	m.controlMutex.Lock()
+	defer m.controlMutex.Unlock()
+
+	if err := m.controlStore.RemoveCommand(cmd); err != nil {
+		klog.Warningf("error removing backup-now command: %v", err)
+	}
+	var remaining []commands.Command
+	for _, c := range m.controlCommands {
+		if c != cmd {
+			remaining = append(remaining, c)
+		}
+	}
+	m.controlCommands = remaining
+	// Check again promptly, in case another backup-now command is queued
+	m.lastBackupNowPoll = time.Time{}
+}
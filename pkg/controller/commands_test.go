@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/commands"
+)
+
+func TestBackupNowCommand(t *testing.T) {
+	p, err := vfs.Context.BuildVfsPath(filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	controlStore, err := commands.NewVFSStore(p)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+	m := &EtcdController{controlStore: controlStore}
+
+	now := time.Now()
+	if cmd, err := m.pollBackupNowCommand(now); err != nil || cmd != nil {
+		t.Fatalf("expected no backup-now command, got %v (err=%v)", cmd, err)
+	}
+
+	// Other commands are left for refreshControlStore
+	if err := controlStore.AddCommand(&protoetcd.Command{RestoreBackup: &protoetcd.RestoreBackupCommand{Backup: "unused"}}); err != nil {
+		t.Fatalf("AddCommand failed: %v", err)
+	}
+	added := &protoetcd.Command{BackupNow: &protoetcd.BackupNowCommand{Label: "before upgrade"}}
+	if err := controlStore.AddCommand(added); err != nil {
+		t.Fatalf("AddCommand failed: %v", err)
+	}
+
+	// We don't check again until the poll interval has passed
+	if cmd, err := m.pollBackupNowCommand(now.Add(time.Second)); err != nil || cmd != nil {
+		t.Fatalf("expected the control store not to be polled, got %v (err=%v)", cmd, err)
+	}
+
+	cmd, err := m.pollBackupNowCommand(now.Add(backupNowPollInterval))
+	if err != nil {
+		t.Fatalf("pollBackupNowCommand failed: %v", err)
+	}
+	if cmd == nil || cmd.Data().BackupNow.GetLabel() != "before upgrade" {
+		t.Fatalf("expected the backup-now command, got %v", cmd)
+	}
+
+	// Between polls, the cached command is returned
+	if cmd, err := m.pollBackupNowCommand(now.Add(backupNowPollInterval + time.Second)); err != nil || cmd == nil {
+		t.Fatalf("expected the cached backup-now command, got %v (err=%v)", cmd, err)
+	}
+
+	if result, err := controlStore.GetCommandResult(added); err != nil || result != nil {
+		t.Fatalf("expected no result before the command is complete, got %v (err=%v)", result, err)
+	}
+
+	m.completeBackupNowCommand(cmd, &protoetcd.DoBackupResponse{Name: "2026-10-17T00:00:00Z-000001"}, nil)
+
+	result, err := controlStore.GetCommandResult(added)
+	if err != nil {
+		t.Fatalf("GetCommandResult failed: %v", err)
+	}
+	if result == nil || result.BackupName != "2026-10-17T00:00:00Z-000001" || result.Error != "" {
+		t.Errorf("unexpected command result %v", result)
+	}
+
+	remaining, err := controlStore.ListCommands()
+	if err != nil {
+		t.Fatalf("ListCommands failed: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].Data().RestoreBackup == nil {
+		t.Errorf("expected only the restore-backup command to remain, got %v", remaining)
+	}
+	if cmd, err := m.pollBackupNowCommand(now.Add(backupNowPollInterval)); err != nil || cmd != nil {
+		t.Errorf("expected no backup-now command after completion, got %v (err=%v)", cmd, err)
+	}
+
+	// Failures are recorded too
+	failed := &protoetcd.Command{BackupNow: &protoetcd.BackupNowCommand{}}
+	if err := controlStore.AddCommand(failed); err != nil {
+		t.Fatalf("AddCommand failed: %v", err)
+	}
+	cmd, err = m.pollBackupNowCommand(now.Add(2 * backupNowPollInterval))
+	if err != nil || cmd == nil {
+		t.Fatalf("expected the backup-now command, got %v (err=%v)", cmd, err)
+	}
+	m.completeBackupNowCommand(cmd, nil, fmt.Errorf("no peer was able to perform a backup"))
+	result, err = controlStore.GetCommandResult(failed)
+	if err != nil || result == nil || result.Error != "no peer was able to perform a backup" {
+		t.Errorf("unexpected command result %v (err=%v)", result, err)
+	}
+}
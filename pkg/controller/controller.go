@@ -81,6 +81,18 @@ type EtcdController struct {
 	// backupCleanup manages cleaning up old backups from the backupStore
 	backupCleanup *backupcontroller.BackupCleanup
 
+	// backupHooks are run around each backup
+	backupHooks *backupcontroller.BackupHooks
+
+	// anomalyDetector flags backups that differ sharply from the preceding backups
+	anomalyDetector *backupcontroller.AnomalyDetector
+
+	// BackupSchedule, if set, is used to decide when to take periodic backups, instead of backupInterval
+	BackupSchedule *backupcontroller.BackupSchedule
+
+	// StateArchiver, if set, archives the cluster state (CA keypairs and cluster spec) alongside each backup
+	StateArchiver *backupcontroller.StateArchiver
+
 	// controlStore is the store / source of commands
 	controlStore commands.Store
 
@@ -94,6 +106,9 @@ type EtcdController struct {
 	// controlClusterSpec is the expected cluster spec, as read from the control store
 	controlClusterSpec *protoetcd.ClusterSpec
 
+	// lastBackupNowPoll is when we last checked the control store for backup-now commands
+	lastBackupNowPoll time.Time
+
 	// disableEtcdTLS is set if we should _not_ enable TLS.
 	// We do it this way so we fail secure
 	disableEtcdTLS bool
@@ -118,6 +133,14 @@ func NewEtcdController(leaderLock locking.Lock, backupStore backup.Store, backup
 	if clusterName == "" {
 		return nil, fmt.Errorf("ClusterName is required")
 	}
+	backupHooks, err := backupcontroller.LoadBackupHooksFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	anomalyDetector, err := backupcontroller.NewAnomalyDetector(backupStore)
+	if err != nil {
+		return nil, err
+	}
 	m := &EtcdController{
 		clusterName:            clusterName,
 		dnsSuffix:              dnsSuffix,
@@ -127,6 +150,8 @@ func NewEtcdController(leaderLock locking.Lock, backupStore backup.Store, backup
 		leaderLock:             leaderLock,
 		CycleInterval:          defaultCycleInterval,
 		backupCleanup:          backupcontroller.NewBackupCleanup(backupStore),
+		backupHooks:            backupHooks,
+		anomalyDetector:        anomalyDetector,
 		controlStore:           controlStore,
 		controlRefreshInterval: controlRefreshInterval,
 	}
@@ -151,6 +176,17 @@ func NewEtcdController(leaderLock locking.Lock, backupStore backup.Store, backup
 	return m, nil
 }
 
+// StartRevisionArchiver continuously archives the changes made to etcd into the backup store, writing them every segmentInterval.
+// clientUrls should be the client urls of the local etcd member; the archiver only archives while that member is the leader.
+func (m *EtcdController) StartRevisionArchiver(ctx context.Context, clientUrls []string, segmentInterval time.Duration) error {
+	archiver, err := backupcontroller.NewRevisionArchiver(m.backupStore, clientUrls, m.etcdClientTLSConfig, segmentInterval)
+	if err != nil {
+		return err
+	}
+	go archiver.Run(ctx)
+	return nil
+}
+
 // Run starts an EtcdController.  It runs indefinitely - until ctx is no longer valid.
 func (m *EtcdController) Run(ctx context.Context) {
 	contextutil.Forever(ctx,
@@ -460,6 +496,9 @@ func (m *EtcdController) run(ctx context.Context) (bool, error) {
 		if len(clusterState.healthyMembers) >= desiredQuorumSize && len(versionMismatch) == 0 {
 			if ackedPeerCount >= quorumSize(int(clusterSpec.MemberCount)) {
 				// We're ready - lift quarantine
+				if _, err := m.backupBeforeChange(ctx, clusterSpec, clusterState, operationLiftQuarantine); err != nil {
+					return false, err
+				}
 				return m.updateQuarantine(ctx, clusterState, false)
 			} else {
 				klog.Infof("insufficient peers to lift quarantine")
@@ -470,6 +509,9 @@ func (m *EtcdController) run(ctx context.Context) (bool, error) {
 		// Ensure that if anyone is quarantined (and should be) that everyone is quarantined
 		if nonQuarantinedMembers > 0 {
 			klog.Infof("inconsistent quarantine state, will set all to quarantined")
+			if _, err := m.backupBeforeChange(ctx, clusterSpec, clusterState, operationQuarantine); err != nil {
+				return false, err
+			}
 			return m.updateQuarantine(ctx, clusterState, true)
 		}
 	}
@@ -531,7 +573,6 @@ func (m *EtcdController) run(ctx context.Context) (bool, error) {
 			klog.Infof("etcd has unhealthy members, an idle peer ready to join, and is at full cluster size; removing a member")
 			// TODO: Remove and readd bad member to repair it
 			// TODO: Wait longer in case of a flake
-			// TODO: Still backup before mutating the cluster
 			return m.removeNodeFromCluster(ctx, clusterSpec, clusterState, false)
 		}
 	}
@@ -569,25 +610,77 @@ func (m *EtcdController) run(ctx context.Context) (bool, error) {
 func (m *EtcdController) maybeBackup(ctx context.Context, clusterSpec *protoetcd.ClusterSpec, clusterState *etcdClusterState) error {
 	now := time.Now()
 
-	shouldBackup := now.Sub(m.lastBackup) > m.backupInterval
+	backupNow, err := m.pollBackupNowCommand(now)
+	if err != nil {
+		klog.Warningf("error checking for backup-now commands: %v", err)
+	}
+
+	shouldBackup := backupNow != nil
+	if !shouldBackup {
+		if m.BackupSchedule != nil {
+			shouldBackup, err = m.BackupSchedule.ShouldBackup(m.backupStore, now)
+			if err != nil {
+				return fmt.Errorf("error checking backup schedule: %w", err)
+			}
+		} else {
+			shouldBackup = now.Sub(m.lastBackup) > m.backupInterval
+		}
+		if shouldBackup && now.Before(m.backupHooks.RetryAfter()) {
+			klog.V(2).Infof("deferring backup until %s, after a required pre-backup hook failed", m.backupHooks.RetryAfter().UTC().Format(time.RFC3339))
+			shouldBackup = false
+		}
+	}
 
 	if !shouldBackup {
 		return nil
 	}
 
-	backup, err := m.doClusterBackup(ctx, clusterSpec, clusterState)
-	if err != nil {
+	label := ""
+	if backupNow != nil {
+		// On-demand backups are always labelled, so that they don't count against the backup schedule
+		label = backupNow.Data().BackupNow.Label
+		if label == "" {
+			label = "backup-now"
+		}
+		klog.Infof("taking backup for backup-now command: %v", backupNow.Data())
+	}
+
+	backup, err := m.backupHooks.RunBackup(ctx, m.backupStore, func() (*protoetcd.DoBackupResponse, error) {
+		backup, err := m.doClusterBackup(ctx, clusterSpec, clusterState, label)
+		if err != nil {
+			return nil, err
+		}
+		if m.StateArchiver != nil {
+			// The backup is usable without the state, so we don't fail it
+			if err := m.StateArchiver.ArchiveState(backup.Name, clusterSpec); err != nil {
+				klog.Warningf("error archiving cluster state with backup %q: %v", backup.Name, err)
+			}
+		}
+		return backup, nil
+	})
+	if backupNow != nil {
+		m.completeBackupNowCommand(backupNow, backup, err)
+	}
+	if backup == nil {
 		return err
 	}
+	// err is now from a required post-backup hook; the backup itself was taken
+	hookErr := err
 
 	klog.Infof("took backup: %v", backup)
 	m.lastBackup = now
+	if m.BackupSchedule != nil && label == "" {
+		m.BackupSchedule.BackupTaken(backup.Name)
+	}
+
+	// We check before cleanup, so that cleanup keeps the last known-good backup if this one is flagged
+	m.checkBackupForAnomalies(backup.Name)
 
 	if err := m.backupCleanup.MaybeDoBackupMaintenance(ctx); err != nil {
 		klog.Warningf("error during backup cleanup: %v", err)
 	}
 
-	return nil
+	return hookErr
 }
 
 func randomToken() string {
@@ -797,9 +890,8 @@ func (m *EtcdController) addNodeToCluster(ctx context.Context, clusterSpec *prot
 	// We need to start etcd on a new node
 	if len(idlePeers) != 0 {
 		if len(clusterState.members) != 0 {
-			// Force a backup first
-			if _, err := m.doClusterBackup(ctx, clusterSpec, clusterState); err != nil {
-				return false, fmt.Errorf("failed to backup (before adding peer): %w", err)
+			if _, err := m.backupBeforeChange(ctx, clusterSpec, clusterState, operationAddMember); err != nil {
+				return false, err
 			}
 		} else {
 			klog.Warningf("unable to do backup before adding peer - no members")
@@ -915,8 +1007,13 @@ func (m *EtcdController) addNodeToCluster(ctx context.Context, clusterSpec *prot
 	return false, nil
 }
 
-// doClusterBackup triggers a backup of etcd, on any healthy cluster member
-func (m *EtcdController) doClusterBackup(ctx context.Context, clusterSpec *protoetcd.ClusterSpec, clusterState *etcdClusterState) (*protoetcd.DoBackupResponse, error) {
+// doClusterBackup triggers a backup of etcd, on any healthy cluster member; label is recorded in the backup info
+func (m *EtcdController) doClusterBackup(ctx context.Context, clusterSpec *protoetcd.ClusterSpec, clusterState *etcdClusterState, label string) (*protoetcd.DoBackupResponse, error) {
+	var members []*etcdclient.EtcdProcessMember
+	for _, member := range clusterState.members {
+		members = append(members, member)
+	}
+
 	for _, member := range clusterState.healthyMembers {
 		peer := clusterState.FindPeer(member)
 		if peer == nil {
@@ -926,6 +1023,8 @@ func (m *EtcdController) doClusterBackup(ctx context.Context, clusterSpec *proto
 
 		info := &protoetcd.BackupInfo{
 			ClusterSpec: clusterSpec,
+			Members:     etcd.BackupMembers(members),
+			Label:       label,
 		}
 		doBackupRequest := &protoetcd.DoBackupRequest{
 			Header:  m.buildHeader(),
@@ -996,9 +1095,8 @@ func (m *EtcdController) removeNodeFromCluster(ctx context.Context, clusterSpec
 		return false, fmt.Errorf("unable to pick a member to remove")
 	}
 
-	// Force a backup first
-	if _, err := m.doClusterBackup(ctx, clusterSpec, clusterState); err != nil {
-		return false, fmt.Errorf("failed to backup (before adding peer): %v", err)
+	if _, err := m.backupBeforeChange(ctx, clusterSpec, clusterState, operationRemoveMember); err != nil {
+		return false, err
 	}
 
 	klog.Infof("removing node from etcd cluster: %v", victim)
@@ -95,8 +95,8 @@ func (m *EtcdController) maybeRemoveStaleMemberForEmptyDiskReplacement(ctx conte
 		return false, nil
 	}
 
-	if _, err := m.doClusterBackup(ctx, clusterSpec, clusterState); err != nil {
-		return false, fmt.Errorf("failed to backup before replacing member %q: %v", candidate.member.Name, err)
+	if _, err := m.backupBeforeChange(ctx, clusterSpec, clusterState, operationReplaceMember); err != nil {
+		return false, fmt.Errorf("not replacing member %q: %w", candidate.member.Name, err)
 	}
 
 	klog.Infof("removing stale etcd member for empty disk replacement: %s", candidate)
@@ -49,6 +49,7 @@ func (m *EtcdController) restoreBackupAndLiftQuarantine(parentContext context.Co
 		Header:     m.buildHeader(),
 		Storage:    m.backupStore.Spec(),
 		BackupName: backup,
+		Target:     cmd.Data().RestoreBackup.Target,
 	}
 
 	var peer *etcdClusterPeerInfo
@@ -67,9 +67,8 @@ func (m *EtcdController) stopForUpgrade(parentContext context.Context, clusterSp
 	}
 
 	// Force a backup, even before we start to do anything
-	klog.Infof("backing up cluster before quarantine")
-	if _, err := m.doClusterBackup(ctx, clusterSpec, clusterState); err != nil {
-		return false, fmt.Errorf("error doing backup before upgrade/downgrade: %w", err)
+	if _, err := m.backupBeforeChange(ctx, clusterSpec, clusterState, operationQuarantine); err != nil {
+		return false, err
 	}
 
 	// We quarantine first, so that we don't have to get down to a single node before it is safe to do a backup
@@ -78,13 +77,11 @@ func (m *EtcdController) stopForUpgrade(parentContext context.Context, clusterSp
 		return false, err
 	}
 
-	// We do a backup
-	klog.Infof("backing up cluster after quarantine")
-	backupResponse, err := m.doClusterBackup(ctx, clusterSpec, clusterState)
+	// We do a backup, which we will restore onto the new version
+	backupResponse, err := m.backupBeforeChange(ctx, clusterSpec, clusterState, operationUpgrade)
 	if err != nil {
 		return false, err
 	}
-	klog.Infof("backed up cluster as %v", backupResponse)
 
 	// Schedule a restore of the backup onto the new cluster
 	{
@@ -155,13 +152,10 @@ func (m *EtcdController) upgradeInPlace(parentContext context.Context, clusterSp
 		}
 	}
 
-	// We do a backup
-	klog.Infof("backing up cluster before in-place upgrade")
-	backupResponse, err := m.doClusterBackup(ctx, clusterSpec, clusterState)
-	if err != nil {
+	// We do a backup; we upgrade one node per cycle, so we take one before each node
+	if _, err := m.backupBeforeChange(ctx, clusterSpec, clusterState, operationUpgrade); err != nil {
 		return false, err
 	}
-	klog.Infof("backup done: %v", backupResponse)
 
 	for memberId := range clusterState.members {
 		peer := memberToPeer[memberId]
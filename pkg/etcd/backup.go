@@ -20,8 +20,10 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"k8s.io/klog/v2"
 	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
@@ -41,6 +43,41 @@ func DoBackup(backupStore backup.Store, info *protoetcd.BackupInfo, dataDir stri
 
 // DoBackupV3 performs a backup of etcd v3; using the etcd v3 API
 func DoBackupV3(backupStore backup.Store, info *protoetcd.BackupInfo, clientUrls []string, tlsConfig *tls.Config) (*protoetcd.DoBackupResponse, error) {
+	client, err := etcdclient.NewClient(clientUrls, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error building etcd client to etcd: %w", err)
+	}
+	defer etcdclient.LoggedClose(client)
+
+	recordBackupSource(context.TODO(), client, info)
+
+	// The data is compressed with the store's codec, which we record so restore can pick the matching decoder.
+	// We leave gzip (the default) unset, so that older versions without codec support can still read the info.
+	codec := backupStore.Codec()
+	if codec.Name() != backup.CompressionGzip {
+		info.Compression = codec.Name()
+	}
+
+	writeSnapshot := func(w io.Writer) error {
+		compressor, err := codec.NewWriter(w)
+		if err != nil {
+			return fmt.Errorf("error building %s compressor: %w", codec.Name(), err)
+		}
+		if err := client.SnapshotSaveTo(context.TODO(), compressor); err != nil {
+			return fmt.Errorf("error performing snapshot save: %w", err)
+		}
+		if err := compressor.Close(); err != nil {
+			return fmt.Errorf("error compressing snapshot: %w", err)
+		}
+		return nil
+	}
+
+	// If the store can accept a stream, we avoid writing the (potentially very large) snapshot to local disk
+	if streamingStore, ok := backupStore.(backup.StreamingStore); ok && streamingStore.CanStream() {
+		klog.Infof("performing streaming snapshot save to %s", backupStore.Spec())
+		return uploadBackupStream(streamingStore, info, writeSnapshot)
+	}
+
 	tempDir, err := os.MkdirTemp("", "")
 	if err != nil {
 		return nil, fmt.Errorf("error creating etcd backup temp directory: %w", err)
@@ -53,31 +90,110 @@ func DoBackupV3(backupStore backup.Store, info *protoetcd.BackupInfo, clientUrls
 		}
 	}()
 
-	client, err := etcdclient.NewClient(clientUrls, tlsConfig)
+	snapshotFile := filepath.Join(tempDir, "snapshot.db"+codec.Extension())
+	klog.Infof("performing snapshot save to %s", snapshotFile)
+	if err := writeSnapshotFile(snapshotFile, writeSnapshot); err != nil {
+		return nil, err
+	}
+
+	return uploadBackup(backupStore, info, snapshotFile)
+}
+
+// recordBackupSource records the state of etcd when the backup started in info.
+// This is informational, so failures are logged rather than failing the backup.
+func recordBackupSource(ctx context.Context, client *etcdclient.EtcdClient, info *protoetcd.BackupInfo) {
+	// Record the cluster, so that archived revisions can be matched to the backup for point-in-time recovery
+	if clusterID, err := client.ClusterID(ctx); err != nil {
+		klog.Warningf("unable to get etcd cluster id for backup: %v", err)
+	} else {
+		info.EtcdClusterId = clusterID
+	}
+
+	if status, err := client.Status(ctx); err != nil {
+		klog.Warningf("unable to get etcd status for backup: %v", err)
+	} else {
+		info.Revision = status.Revision
+		info.RaftTerm = status.RaftTerm
+		info.DbSize = status.DBSize
+		info.SourceMemberId = status.MemberID
+	}
+
+	// We count the keys at the recorded revision, so that restore drills can check the restored data against both
+	if info.Revision != 0 {
+		if keyCount, err := client.KeyCountAtRevision(ctx, info.Revision); err != nil {
+			klog.Warningf("unable to count etcd keys for backup: %v", err)
+		} else {
+			info.KeyCount = keyCount
+		}
+	}
+
+	// The caller normally records the members it knows about; etcd's own view is the fallback
+	if len(info.Members) == 0 {
+		members, err := client.ListMembers(ctx)
+		if err != nil {
+			klog.Warningf("unable to list etcd members for backup: %v", err)
+		} else {
+			info.Members = BackupMembers(members)
+		}
+	}
+
+	for _, member := range info.Members {
+		if member.Id == info.SourceMemberId {
+			info.SourceMemberName = member.Name
+		}
+	}
+}
+
+// BackupMembers converts etcd members to the form recorded in BackupInfo, ordered by name
+func BackupMembers(members []*etcdclient.EtcdProcessMember) []*protoetcd.BackupMember {
+	var backupMembers []*protoetcd.BackupMember
+	for _, member := range members {
+		backupMembers = append(backupMembers, &protoetcd.BackupMember{
+			Id:         member.ID,
+			Name:       member.Name,
+			PeerUrls:   member.PeerURLs,
+			ClientUrls: member.ClientURLs,
+		})
+	}
+	sort.Slice(backupMembers, func(i, j int) bool {
+		return backupMembers[i].Name < backupMembers[j].Name
+	})
+	return backupMembers
+}
+
+// writeSnapshotFile creates the file p, with the contents written by writeData
+func writeSnapshotFile(p string, writeData func(w io.Writer) error) error {
+	f, err := os.Create(p)
 	if err != nil {
-		return nil, fmt.Errorf("error building etcd client to etcd: %w", err)
+		return fmt.Errorf("error creating %q: %w", p, err)
 	}
-	defer etcdclient.LoggedClose(client)
+	defer f.Close()
 
-	snapshotFile := filepath.Join(tempDir, "snapshot.db.gz")
-	klog.Infof("performing snapshot save to %s", snapshotFile)
-	if err := client.SnapshotSave(context.TODO(), snapshotFile); err != nil {
-		return nil, fmt.Errorf("error performing snapshot save: %w", err)
+	if err := writeData(f); err != nil {
+		return err
 	}
 
-	return uploadBackup(backupStore, info, snapshotFile)
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing %q: %w", p, err)
+	}
+	return nil
 }
 
 // sequence is used to provide a tie-breaker for backups that happen in less than one second, primarily.
 var sequence = 0
 
-// uploadBackup uploads a backup directory to a backup.Store
-func uploadBackup(backupStore backup.Store, info *protoetcd.BackupInfo, srcFile string) (*protoetcd.DoBackupResponse, error) {
+// nextSequence returns the next value of the backup sequence, formatted for use as a backup name suffix
+func nextSequence() string {
 	sequence++
 	if sequence > 999999 {
 		sequence = 0
 	}
-	backupName, err := backupStore.AddBackup(srcFile, fmt.Sprintf("%.6d", sequence), info)
+	return fmt.Sprintf("%.6d", sequence)
+}
+
+// uploadBackup uploads a backup directory to a backup.Store
+func uploadBackup(backupStore backup.Store, info *protoetcd.BackupInfo, srcFile string) (*protoetcd.DoBackupResponse, error) {
+	backupName, err := backupStore.AddBackup(srcFile, nextSequence(), info)
 	if err != nil {
 		return nil, fmt.Errorf("error copying backup to storage: %w", err)
 	}
@@ -88,3 +204,17 @@ func uploadBackup(backupStore backup.Store, info *protoetcd.BackupInfo, srcFile
 	klog.Infof("backup complete: %v", response)
 	return response, nil
 }
+
+// uploadBackupStream streams a backup to a backup.StreamingStore, with the data written by writeData
+func uploadBackupStream(backupStore backup.StreamingStore, info *protoetcd.BackupInfo, writeData func(w io.Writer) error) (*protoetcd.DoBackupResponse, error) {
+	backupName, err := backupStore.AddBackupFromStream(writeData, nextSequence(), info)
+	if err != nil {
+		return nil, fmt.Errorf("error streaming backup to storage: %w", err)
+	}
+
+	response := &protoetcd.DoBackupResponse{
+		Name: backupName,
+	}
+	klog.Infof("backup complete: %v", response)
+	return response, nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+func TestBackupMembers(t *testing.T) {
+	members := []*etcdclient.EtcdProcessMember{
+		{
+			ID:         "2",
+			Name:       "etcd-b",
+			PeerURLs:   []string{"https://etcd-b:2380"},
+			ClientURLs: []string{"https://etcd-b:4001"},
+		},
+		{
+			ID:       "1",
+			Name:     "etcd-a",
+			PeerURLs: []string{"https://etcd-a:2380"},
+		},
+	}
+
+	actual := BackupMembers(members)
+	expected := []*protoetcd.BackupMember{
+		{Id: "1", Name: "etcd-a", PeerUrls: []string{"https://etcd-a:2380"}},
+		{Id: "2", Name: "etcd-b", PeerUrls: []string{"https://etcd-b:2380"}, ClientUrls: []string{"https://etcd-b:4001"}},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("BackupMembers returned %v, expected %v", actual, expected)
+	}
+	for i := range expected {
+		if !proto.Equal(actual[i], expected[i]) {
+			t.Errorf("member %d was %v, expected %v", i, actual[i], expected[i])
+		}
+	}
+}
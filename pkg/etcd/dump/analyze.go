@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"context"
+	"sort"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+// PrefixUsage is the keyspace used by the keys under a prefix
+type PrefixUsage struct {
+	Prefix string `json:"prefix"`
+	Keys   int64  `json:"keys"`
+	// Bytes is the total size of the keys and their values
+	Bytes int64 `json:"bytes"`
+	// Versions is the total number of writes to the keys since each was created
+	Versions int64 `json:"versions"`
+}
+
+// KeyUsage is the keyspace used by a single key
+type KeyUsage struct {
+	Key string `json:"key"`
+	// Bytes is the size of the key and its value
+	Bytes          int64 `json:"bytes"`
+	Version        int64 `json:"version"`
+	CreateRevision int64 `json:"createRevision"`
+	ModRevision    int64 `json:"modRevision"`
+}
+
+// Analysis is the keyspace usage of a backup
+type Analysis struct {
+	Keys     int64 `json:"keys"`
+	Bytes    int64 `json:"bytes"`
+	Versions int64 `json:"versions"`
+	// Revision is the newest revision of any key
+	Revision int64 `json:"revision"`
+
+	// Prefixes is the usage under each prefix, largest first
+	Prefixes []*PrefixUsage `json:"prefixes"`
+	// LargestKeys are the largest keys, largest first
+	LargestKeys []*KeyUsage `json:"largestKeys"`
+	// MostModifiedKeys are the keys with the most writes since they were created, most first
+	MostModifiedKeys []*KeyUsage `json:"mostModifiedKeys"`
+}
+
+// AnalyzeSink is a NodeSink that totals the keyspace used under each prefix, and finds the largest and most modified keys
+type AnalyzeSink struct {
+	depth int
+	top   int
+
+	analysis     Analysis
+	prefixes     map[string]*PrefixUsage
+	largest      []*KeyUsage
+	mostModified []*KeyUsage
+}
+
+var _ etcdclient.KeyValueSink = &AnalyzeSink{}
+
+// NewAnalyzeSink builds an AnalyzeSink grouping keys by their first depth path segments (see KeyPrefix),
+// and keeping the top largest and most modified keys
+func NewAnalyzeSink(depth int, top int) *AnalyzeSink {
+	return &AnalyzeSink{
+		depth:    depth,
+		top:      top,
+		prefixes: make(map[string]*PrefixUsage),
+	}
+}
+
+func (s *AnalyzeSink) Put(ctx context.Context, key string, value []byte) error {
+	return s.PutKeyValue(ctx, &etcdclient.KeyValue{Key: key, Value: value})
+}
+
+func (s *AnalyzeSink) PutKeyValue(ctx context.Context, kv *etcdclient.KeyValue) error {
+	usage := &KeyUsage{
+		Key:            kv.Key,
+		Bytes:          int64(len(kv.Key) + len(kv.Value)),
+		Version:        kv.Version,
+		CreateRevision: kv.CreateRevision,
+		ModRevision:    kv.ModRevision,
+	}
+
+	s.analysis.Keys++
+	s.analysis.Bytes += usage.Bytes
+	s.analysis.Versions += usage.Version
+	if usage.ModRevision > s.analysis.Revision {
+		s.analysis.Revision = usage.ModRevision
+	}
+
+	prefix := KeyPrefix(kv.Key, s.depth)
+	p := s.prefixes[prefix]
+	if p == nil {
+		p = &PrefixUsage{Prefix: prefix}
+		s.prefixes[prefix] = p
+	}
+	p.Keys++
+	p.Bytes += usage.Bytes
+	p.Versions += usage.Version
+
+	s.largest = keepTop(append(s.largest, usage), s.top, false, byBytes)
+	s.mostModified = keepTop(append(s.mostModified, usage), s.top, false, byVersion)
+	return nil
+}
+
+func (s *AnalyzeSink) Close() error {
+	return nil
+}
+
+// Analysis returns the usage of the keys seen so far
+func (s *AnalyzeSink) Analysis() *Analysis {
+	analysis := s.analysis
+	analysis.Prefixes = nil
+	for _, p := range s.prefixes {
+		analysis.Prefixes = append(analysis.Prefixes, p)
+	}
+	sort.Slice(analysis.Prefixes, func(i, j int) bool {
+		a, b := analysis.Prefixes[i], analysis.Prefixes[j]
+		if a.Bytes != b.Bytes {
+			return a.Bytes > b.Bytes
+		}
+		return a.Prefix < b.Prefix
+	})
+	analysis.LargestKeys = keepTop(append([]*KeyUsage(nil), s.largest...), s.top, true, byBytes)
+	analysis.MostModifiedKeys = keepTop(append([]*KeyUsage(nil), s.mostModified...), s.top, true, byVersion)
+	return &analysis
+}
+
+func byBytes(a, b *KeyUsage) bool {
+	if a.Bytes != b.Bytes {
+		return a.Bytes > b.Bytes
+	}
+	return a.Key < b.Key
+}
+
+func byVersion(a, b *KeyUsage) bool {
+	if a.Version != b.Version {
+		return a.Version > b.Version
+	}
+	return a.Key < b.Key
+}
+
+// keepTop returns the first n keys ordered by less.  Unless final is set, it only trims once keys reaches 2n,
+// so that we sort occasionally rather than on every key.
+func keepTop(keys []*KeyUsage, n int, final bool, less func(a, b *KeyUsage) bool) []*KeyUsage {
+	if !final && len(keys) < 2*n {
+		return keys
+	}
+	sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
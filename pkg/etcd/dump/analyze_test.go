@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+func TestAnalyzeSink(t *testing.T) {
+	ctx := context.TODO()
+	sink := NewAnalyzeSink(2, 2)
+
+	kvs := []*etcdclient.KeyValue{
+		{Key: "/registry/events/default/e1", Value: []byte(strings.Repeat("e", 100)), Version: 1, CreateRevision: 10, ModRevision: 10},
+		{Key: "/registry/events/default/e2", Value: []byte(strings.Repeat("e", 200)), Version: 1, CreateRevision: 11, ModRevision: 11},
+		{Key: "/registry/leases/kube-node-lease/n1", Value: []byte("lease"), Version: 500, CreateRevision: 2, ModRevision: 900},
+		{Key: "/registry/pods/default/p1", Value: []byte(strings.Repeat("p", 50)), Version: 7, CreateRevision: 5, ModRevision: 40},
+		{Key: "compact_rev_key", Value: []byte("x"), Version: 20, CreateRevision: 1, ModRevision: 800},
+	}
+	for _, kv := range kvs {
+		if err := sink.PutKeyValue(ctx, kv); err != nil {
+			t.Fatalf("PutKeyValue failed: %v", err)
+		}
+	}
+
+	analysis := sink.Analysis()
+	if analysis.Keys != 5 || analysis.Versions != 529 || analysis.Revision != 900 {
+		t.Errorf("unexpected totals: keys=%d versions=%d revision=%d", analysis.Keys, analysis.Versions, analysis.Revision)
+	}
+	var expectedBytes int64
+	for _, kv := range kvs {
+		expectedBytes += int64(len(kv.Key) + len(kv.Value))
+	}
+	if analysis.Bytes != expectedBytes {
+		t.Errorf("total bytes was %d, expected %d", analysis.Bytes, expectedBytes)
+	}
+
+	var prefixes []string
+	for _, p := range analysis.Prefixes {
+		prefixes = append(prefixes, fmt.Sprintf("%s:%d:%d", p.Prefix, p.Keys, p.Versions))
+	}
+	expectedPrefixes := []string{"/registry/events:2:2", "/registry/pods:1:7", "/registry/leases:1:500", "compact_rev_key:1:20"}
+	if !reflect.DeepEqual(prefixes, expectedPrefixes) {
+		t.Errorf("prefixes were %v, expected %v", prefixes, expectedPrefixes)
+	}
+
+	var largest []string
+	for _, k := range analysis.LargestKeys {
+		largest = append(largest, k.Key)
+	}
+	if expected := []string{"/registry/events/default/e2", "/registry/events/default/e1"}; !reflect.DeepEqual(largest, expected) {
+		t.Errorf("largest keys were %v, expected %v", largest, expected)
+	}
+
+	var mostModified []string
+	for _, k := range analysis.MostModifiedKeys {
+		mostModified = append(mostModified, k.Key)
+	}
+	if expected := []string{"/registry/leases/kube-node-lease/n1", "compact_rev_key"}; !reflect.DeepEqual(mostModified, expected) {
+		t.Errorf("most modified keys were %v, expected %v", mostModified, expected)
+	}
+}
+
+func TestKeepTop(t *testing.T) {
+	var keys []*KeyUsage
+	for i := 0; i < 100; i++ {
+		keys = keepTop(append(keys, &KeyUsage{Key: fmt.Sprintf("/k%03d", i), Bytes: int64(i)}), 3, false, byBytes)
+		if len(keys) >= 6 {
+			t.Fatalf("keepTop kept %d keys, expected fewer than 6", len(keys))
+		}
+	}
+	keys = keepTop(keys, 3, true, byBytes)
+	var names []string
+	for _, k := range keys {
+		names = append(names, k.Key)
+	}
+	if expected := []string{"/k099", "/k098", "/k097"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("keepTop returned %v, expected %v", names, expected)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"context"
+	"crypto/sha256"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+// keyDigest summarizes a value, so that we can compare backups without holding every value in memory
+type keyDigest struct {
+	size int
+	sha  [sha256.Size]byte
+}
+
+// DigestSink is a NodeSink that records the size and checksum of the value of each key
+type DigestSink struct {
+	digests map[string]keyDigest
+}
+
+var _ etcdclient.NodeSink = &DigestSink{}
+
+func NewDigestSink() *DigestSink {
+	return &DigestSink{
+		digests: make(map[string]keyDigest),
+	}
+}
+
+func (s *DigestSink) Put(ctx context.Context, key string, value []byte) error {
+	s.digests[key] = keyDigest{size: len(value), sha: sha256.Sum256(value)}
+	return nil
+}
+
+func (s *DigestSink) Close() error {
+	return nil
+}
+
+// KeyChange is how a key differs between two backups
+type KeyChange string
+
+const (
+	KeyAdded    KeyChange = "added"
+	KeyRemoved  KeyChange = "removed"
+	KeyModified KeyChange = "modified"
+)
+
+// KeyDiff is a key that differs between two backups
+type KeyDiff struct {
+	Key    string
+	Change KeyChange
+
+	// SizeBefore is the size of the value in the first backup, or 0 if the key was added
+	SizeBefore int
+	// SizeAfter is the size of the value in the second backup, or 0 if the key was removed
+	SizeAfter int
+}
+
+// Diff returns the keys that differ between the backups recorded in before and after, ordered by key
+func Diff(before, after *DigestSink) []KeyDiff {
+	var diffs []KeyDiff
+	for key, a := range after.digests {
+		b, found := before.digests[key]
+		if !found {
+			diffs = append(diffs, KeyDiff{Key: key, Change: KeyAdded, SizeAfter: a.size})
+		} else if a != b {
+			diffs = append(diffs, KeyDiff{Key: key, Change: KeyModified, SizeBefore: b.size, SizeAfter: a.size})
+		}
+	}
+	for key, b := range before.digests {
+		if _, found := after.digests[key]; !found {
+			diffs = append(diffs, KeyDiff{Key: key, Change: KeyRemoved, SizeBefore: b.size})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs
+}
+
+// DiffSummary counts the differing keys under a prefix
+type DiffSummary struct {
+	Prefix   string
+	Added    int
+	Removed  int
+	Modified int
+}
+
+// SummarizeDiff counts the differences under each top-level prefix (see KeyGroup), ordered by prefix
+func SummarizeDiff(diffs []KeyDiff) []*DiffSummary {
+	byPrefix := make(map[string]*DiffSummary)
+	var summaries []*DiffSummary
+	for _, diff := range diffs {
+		prefix := KeyGroup(diff.Key)
+		summary := byPrefix[prefix]
+		if summary == nil {
+			summary = &DiffSummary{Prefix: prefix}
+			byPrefix[prefix] = summary
+			summaries = append(summaries, summary)
+		}
+		switch diff.Change {
+		case KeyAdded:
+			summary.Added++
+		case KeyRemoved:
+			summary.Removed++
+		case KeyModified:
+			summary.Modified++
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Prefix < summaries[j].Prefix })
+	return summaries
+}
+
+// KeyGroup returns the top-level prefix of a key: /registry/<resource> for kubernetes objects, otherwise the first path segment
+func KeyGroup(key string) string {
+	if strings.HasPrefix(key, "/registry/") {
+		return KeyPrefix(key, 2)
+	}
+	return KeyPrefix(key, 1)
+}
+
+// KeyPrefix returns the first depth path segments of a key, e.g. /registry/pods for /registry/pods/default/foo at depth 2.
+// Keys with no more than depth segments are returned unchanged.
+func KeyPrefix(key string, depth int) string {
+	rooted := strings.HasPrefix(key, "/")
+	segments := strings.Split(strings.TrimPrefix(key, "/"), "/")
+	if len(segments) > depth {
+		segments = segments[:depth]
+	}
+	prefix := strings.Join(segments, "/")
+	if rooted {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func newTestDigestSink(t *testing.T, keys map[string]string) *DigestSink {
+	sink := NewDigestSink()
+	for k, v := range keys {
+		if err := sink.Put(context.TODO(), k, []byte(v)); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	return sink
+}
+
+func TestDiff(t *testing.T) {
+	before := newTestDigestSink(t, map[string]string{
+		"/registry/pods/default/a":       "pod a",
+		"/registry/pods/default/b":       "pod b",
+		"/registry/configmaps/default/c": "cm c",
+		"/registry/secrets/default/d":    "secret",
+	})
+	after := newTestDigestSink(t, map[string]string{
+		"/registry/pods/default/a":       "pod a",
+		"/registry/pods/default/b":       "pod b, edited",
+		"/registry/configmaps/default/e": "cm e",
+		"/registry/secrets/default/d":    "secret",
+		"/other":                         "x",
+	})
+
+	diffs := Diff(before, after)
+	expected := []KeyDiff{
+		{Key: "/other", Change: KeyAdded, SizeAfter: 1},
+		{Key: "/registry/configmaps/default/c", Change: KeyRemoved, SizeBefore: 4},
+		{Key: "/registry/configmaps/default/e", Change: KeyAdded, SizeAfter: 4},
+		{Key: "/registry/pods/default/b", Change: KeyModified, SizeBefore: 5, SizeAfter: 13},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("Diff returned %v, expected %v", diffs, expected)
+	}
+
+	summaries := SummarizeDiff(diffs)
+	expectedSummaries := []*DiffSummary{
+		{Prefix: "/other", Added: 1},
+		{Prefix: "/registry/configmaps", Added: 1, Removed: 1},
+		{Prefix: "/registry/pods", Modified: 1},
+	}
+	if !reflect.DeepEqual(summaries, expectedSummaries) {
+		t.Errorf("SummarizeDiff returned %v, expected %v", summaries, expectedSummaries)
+	}
+
+	if diffs := Diff(before, before); len(diffs) != 0 {
+		t.Errorf("expected no differences between a backup and itself, got %v", diffs)
+	}
+}
+
+func TestKeyPrefix(t *testing.T) {
+	grid := []struct {
+		key      string
+		depth    int
+		expected string
+	}{
+		{"/registry/pods/default/foo", 1, "/registry"},
+		{"/registry/pods/default/foo", 2, "/registry/pods"},
+		{"/registry/pods/default/foo", 3, "/registry/pods/default"},
+		{"/registry/pods/default/foo", 10, "/registry/pods/default/foo"},
+		{"compact_rev_key", 2, "compact_rev_key"},
+	}
+	for _, g := range grid {
+		if actual := KeyPrefix(g.key, g.depth); actual != g.expected {
+			t.Errorf("KeyPrefix(%q, %d) = %q, expected %q", g.key, g.depth, actual, g.expected)
+		}
+	}
+
+	if actual := KeyGroup("/registry/pods/default/foo"); actual != "/registry/pods" {
+		t.Errorf("KeyGroup returned %q for a kubernetes key", actual)
+	}
+	if actual := KeyGroup("/calico/ipam/v2/foo"); actual != "/calico" {
+		t.Errorf("KeyGroup returned %q for a non-kubernetes key", actual)
+	}
+}
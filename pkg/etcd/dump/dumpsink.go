@@ -20,6 +20,7 @@ import (
 	"archive/tar"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
@@ -35,6 +36,15 @@ type TarDumpSink struct {
 
 	// doneDirs helps us synthesize directories
 	doneDirs map[string]bool
+
+	// wroteManifest is set once the manifest has been written
+	wroteManifest bool
+
+	// KeysOnly writes an empty file for each key, without its value
+	KeysOnly bool
+
+	// Decode records in the manifest that values were decoded to this format, by a KubernetesDecodingSink in front of this sink
+	Decode string
 }
 
 var _ etcdclient.NodeSink = &TarDumpSink{}
@@ -56,9 +66,38 @@ func NewTarDumpSink(p string) (*TarDumpSink, error) {
 	return s, nil
 }
 
+// writeManifest writes the manifest, as a record in a global header, if it has not already been written
+func (s *TarDumpSink) writeManifest() error {
+	if s.wroteManifest {
+		return nil
+	}
+	s.wroteManifest = true
+
+	manifest, err := json.Marshal(&DumpManifest{Format: FormatTar, KeysOnly: s.KeysOnly, Decode: s.Decode})
+	if err != nil {
+		return fmt.Errorf("error serializing manifest: %v", err)
+	}
+	hdr := &tar.Header{
+		Typeflag:   tar.TypeXGlobalHeader,
+		PAXRecords: map[string]string{tarManifestRecord: string(manifest)},
+	}
+	if err := s.tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("error writing tar header: %v", err)
+	}
+	return nil
+}
+
 func (s *TarDumpSink) Put(ctx context.Context, key string, value []byte) error {
+	if err := s.writeManifest(); err != nil {
+		return err
+	}
+
 	name := key
 
+	if s.KeysOnly {
+		value = nil
+	}
+
 	if err := s.ensureDirs(path.Dir(name)); err != nil {
 		return err
 	}
@@ -122,7 +161,7 @@ func (s *TarDumpSink) ensureDirs(p string) error {
 }
 
 func (s *TarDumpSink) Close() error {
-	var err error
+	err := s.writeManifest()
 
 	errC := s.tw.Close()
 	if errC != nil {
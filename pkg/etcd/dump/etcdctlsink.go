@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+// EtcdctlSink writes keys as `etcdctl get` does by default: each key on a line, followed by its value on the next line
+type EtcdctlSink struct {
+	out *bufio.Writer
+
+	// KeysOnly writes an empty line in place of each value, as `etcdctl get --keys-only` does
+	KeysOnly bool
+}
+
+var _ etcdclient.NodeSink = &EtcdctlSink{}
+
+func NewEtcdctlSink(out io.Writer) *EtcdctlSink {
+	return &EtcdctlSink{
+		out: bufio.NewWriter(out),
+	}
+}
+
+func (s *EtcdctlSink) Put(ctx context.Context, key string, value []byte) error {
+	if s.KeysOnly {
+		value = nil
+	}
+	if _, err := fmt.Fprintf(s.out, "%s\n%s\n", key, value); err != nil {
+		return fmt.Errorf("error writing to output: %v", err)
+	}
+	return nil
+}
+
+func (s *EtcdctlSink) Close() error {
+	if err := s.out.Flush(); err != nil {
+		return fmt.Errorf("error writing to output: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"context"
+	"strings"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+// FilterSink passes keys on to another sink, dropping keys that start with any of the excluded prefixes
+type FilterSink struct {
+	dest            etcdclient.NodeSink
+	excludePrefixes []string
+}
+
+var _ etcdclient.KeyValueSink = &FilterSink{}
+
+func NewFilterSink(dest etcdclient.NodeSink, excludePrefixes []string) *FilterSink {
+	return &FilterSink{
+		dest:            dest,
+		excludePrefixes: excludePrefixes,
+	}
+}
+
+func (s *FilterSink) excluded(key string) bool {
+	for _, prefix := range s.excludePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *FilterSink) Put(ctx context.Context, key string, value []byte) error {
+	if s.excluded(key) {
+		return nil
+	}
+	return s.dest.Put(ctx, key, value)
+}
+
+func (s *FilterSink) PutKeyValue(ctx context.Context, kv *etcdclient.KeyValue) error {
+	if s.excluded(kv.Key) {
+		return nil
+	}
+	if kvSink, ok := s.dest.(etcdclient.KeyValueSink); ok {
+		return kvSink.PutKeyValue(ctx, kv)
+	}
+	return s.dest.Put(ctx, kv.Key, kv.Value)
+}
+
+func (s *FilterSink) Close() error {
+	return s.dest.Close()
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+const (
+	// ValueEncodingText is a value written as a JSON string
+	ValueEncodingText = "text"
+	// ValueEncodingBase64 is a value written as base64
+	ValueEncodingBase64 = "base64"
+)
+
+// JSONLine is a key as written by JSONLinesSink, one JSON object per line
+type JSONLine struct {
+	Key string `json:"key"`
+
+	// Value is the value, encoded as described by ValueEncoding; both are omitted in keys-only dumps
+	Value         string `json:"value,omitempty"`
+	ValueEncoding string `json:"valueEncoding,omitempty"`
+
+	CreateRevision int64 `json:"createRevision"`
+	ModRevision    int64 `json:"modRevision"`
+	Version        int64 `json:"version"`
+	Lease          int64 `json:"lease"`
+}
+
+// JSONLinesSink writes each key as a JSONLine, after a first line holding the DumpManifest
+type JSONLinesSink struct {
+	out *bufio.Writer
+
+	// wroteManifest is set once the manifest has been written
+	wroteManifest bool
+
+	// KeysOnly omits the values
+	KeysOnly bool
+
+	// Decode records in the manifest that values were decoded to this format, by a KubernetesDecodingSink in front of this sink
+	Decode string
+
+	// Base64 writes every value as base64; otherwise values are written as text if they are valid UTF-8
+	Base64 bool
+}
+
+var _ etcdclient.KeyValueSink = &JSONLinesSink{}
+
+func NewJSONLinesSink(out io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{
+		out: bufio.NewWriter(out),
+	}
+}
+
+func (s *JSONLinesSink) Put(ctx context.Context, key string, value []byte) error {
+	return s.PutKeyValue(ctx, &etcdclient.KeyValue{Key: key, Value: value})
+}
+
+// writeManifest writes the manifest, if it has not already been written
+func (s *JSONLinesSink) writeManifest() error {
+	if s.wroteManifest {
+		return nil
+	}
+	s.wroteManifest = true
+
+	manifest := &jsonLinesManifest{
+		Manifest: &DumpManifest{Format: FormatJSONLines, KeysOnly: s.KeysOnly, Decode: s.Decode},
+	}
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("error serializing manifest: %v", err)
+	}
+	b = append(b, '\n')
+	if _, err := s.out.Write(b); err != nil {
+		return fmt.Errorf("error writing to output: %v", err)
+	}
+	return nil
+}
+
+func (s *JSONLinesSink) PutKeyValue(ctx context.Context, kv *etcdclient.KeyValue) error {
+	if err := s.writeManifest(); err != nil {
+		return err
+	}
+
+	line := &JSONLine{
+		Key:            kv.Key,
+		CreateRevision: kv.CreateRevision,
+		ModRevision:    kv.ModRevision,
+		Version:        kv.Version,
+		Lease:          kv.Lease,
+	}
+	if !s.KeysOnly {
+		if !s.Base64 && utf8.Valid(kv.Value) {
+			line.Value = string(kv.Value)
+			line.ValueEncoding = ValueEncodingText
+		} else {
+			line.Value = base64.StdEncoding.EncodeToString(kv.Value)
+			line.ValueEncoding = ValueEncodingBase64
+		}
+	}
+
+	b, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("error serializing key %q: %v", kv.Key, err)
+	}
+	b = append(b, '\n')
+	if _, err := s.out.Write(b); err != nil {
+		return fmt.Errorf("error writing to output: %v", err)
+	}
+	return nil
+}
+
+func (s *JSONLinesSink) Close() error {
+	if err := s.writeManifest(); err != nil {
+		return err
+	}
+	if err := s.out.Flush(); err != nil {
+		return fmt.Errorf("error writing to output: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"gopkg.in/yaml.v2"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+const (
+	// DecodeJSON writes decoded kubernetes objects as indented JSON
+	DecodeJSON = "json"
+	// DecodeYAML writes decoded kubernetes objects as YAML
+	DecodeYAML = "yaml"
+)
+
+// RedactedValue replaces the data of Secrets, unless ShowSecrets is set
+const RedactedValue = "<redacted>"
+
+// kubernetesRegistryPrefix is where the kubernetes apiserver stores objects
+const kubernetesRegistryPrefix = "/registry/"
+
+// kubernetesSecretsPrefix is where the kubernetes apiserver stores Secrets
+const kubernetesSecretsPrefix = "/registry/secrets/"
+
+// kubernetesProtobufPrefix starts the envelope of objects the kubernetes apiserver stores as protobuf
+var kubernetesProtobufPrefix = []byte("k8s\x00")
+
+// KubernetesDecodingSink decodes the kubernetes objects stored under /registry/, passing them on to another sink
+// as JSON or YAML.  Objects stored as JSON (such as custom resources) are decoded in full.  Objects stored as protobuf
+// are only unwrapped from their envelope, giving their apiVersion and kind; their body is passed on as base64,
+// because decoding it would need the Go types of every kubernetes API.  Values that cannot be decoded, such as
+// objects encrypted at rest, are passed on unchanged.
+type KubernetesDecodingSink struct {
+	dest   etcdclient.NodeSink
+	format string
+
+	// ShowSecrets passes on the data of Secrets; by default it is replaced with RedactedValue
+	ShowSecrets bool
+}
+
+var _ etcdclient.KeyValueSink = &KubernetesDecodingSink{}
+
+func NewKubernetesDecodingSink(dest etcdclient.NodeSink, format string) (*KubernetesDecodingSink, error) {
+	if format != DecodeJSON && format != DecodeYAML {
+		return nil, fmt.Errorf("unknown decoding format %q, expected %s or %s", format, DecodeJSON, DecodeYAML)
+	}
+	return &KubernetesDecodingSink{
+		dest:   dest,
+		format: format,
+	}, nil
+}
+
+func (s *KubernetesDecodingSink) Put(ctx context.Context, key string, value []byte) error {
+	return s.dest.Put(ctx, key, s.decode(key, value))
+}
+
+func (s *KubernetesDecodingSink) PutKeyValue(ctx context.Context, kv *etcdclient.KeyValue) error {
+	decoded := *kv
+	decoded.Value = s.decode(kv.Key, kv.Value)
+	if kvSink, ok := s.dest.(etcdclient.KeyValueSink); ok {
+		return kvSink.PutKeyValue(ctx, &decoded)
+	}
+	return s.dest.Put(ctx, decoded.Key, decoded.Value)
+}
+
+func (s *KubernetesDecodingSink) Close() error {
+	return s.dest.Close()
+}
+
+// decode returns the value as JSON or YAML if it is a kubernetes object, and otherwise returns the value unchanged.
+// Unless ShowSecrets is set, values under /registry/secrets/ are redacted whatever they decode to.
+func (s *KubernetesDecodingSink) decode(key string, value []byte) []byte {
+	if !strings.HasPrefix(key, kubernetesRegistryPrefix) {
+		return value
+	}
+	redact := !s.ShowSecrets && strings.HasPrefix(key, kubernetesSecretsPrefix)
+
+	obj, err := decodeKubernetesObject(value)
+	if err != nil {
+		klog.V(2).Infof("not decoding %q: %v", key, err)
+		if redact {
+			return []byte(RedactedValue)
+		}
+		return value
+	}
+
+	if redact {
+		redactSecret(obj)
+	}
+
+	var b []byte
+	switch s.format {
+	case DecodeJSON:
+		b, err = json.MarshalIndent(obj, "", "  ")
+	case DecodeYAML:
+		b, err = yaml.Marshal(obj)
+	}
+	if err != nil {
+		klog.Warningf("error serializing %q: %v", key, err)
+		if redact {
+			return []byte(RedactedValue)
+		}
+		return value
+	}
+	return b
+}
+
+// protobufField holds the (base64 encoded) body of an object stored as protobuf
+const protobufField = "protobuf"
+
+// decodeKubernetesObject decodes an object as stored by the kubernetes apiserver
+func decodeKubernetesObject(value []byte) (map[string]interface{}, error) {
+	if bytes.HasPrefix(value, kubernetesProtobufPrefix) {
+		envelope, err := decodeKubernetesEnvelope(value[len(kubernetesProtobufPrefix):])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding kubernetes protobuf envelope: %w", err)
+		}
+		if envelope.Kind == "" {
+			return nil, fmt.Errorf("kubernetes protobuf envelope has no kind")
+		}
+		return map[string]interface{}{
+			"apiVersion":  envelope.APIVersion,
+			"kind":        envelope.Kind,
+			protobufField: base64.StdEncoding.EncodeToString(envelope.Raw),
+		}, nil
+	}
+
+	// Custom resources (and objects written with the JSON storage media type) are stored as JSON
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil || obj == nil {
+		return nil, fmt.Errorf("value is neither kubernetes protobuf nor a JSON object")
+	}
+	return convertNumbers(obj).(map[string]interface{}), nil
+}
+
+// kubernetesEnvelope is the envelope the kubernetes apiserver stores protobuf objects in.
+// It is runtime.Unknown from k8s.io/apimachinery, which we decode directly so as not to depend on its runtime package.
+type kubernetesEnvelope struct {
+	APIVersion string
+	Kind       string
+	Raw        []byte
+}
+
+// Field numbers of runtime.Unknown, and of the runtime.TypeMeta it holds
+const (
+	unknownTypeMetaField    = 1
+	unknownRawField         = 2
+	typeMetaAPIVersionField = 1
+	typeMetaKindField       = 2
+)
+
+// decodeKubernetesEnvelope decodes the envelope of an object stored as protobuf, without its kubernetesProtobufPrefix
+func decodeKubernetesEnvelope(b []byte) (*kubernetesEnvelope, error) {
+	envelope := &kubernetesEnvelope{}
+	err := forEachBytesField(b, func(num protowire.Number, value []byte) error {
+		switch num {
+		case unknownTypeMetaField:
+			return forEachBytesField(value, func(num protowire.Number, value []byte) error {
+				switch num {
+				case typeMetaAPIVersionField:
+					envelope.APIVersion = string(value)
+				case typeMetaKindField:
+					envelope.Kind = string(value)
+				}
+				return nil
+			})
+		case unknownRawField:
+			envelope.Raw = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// forEachBytesField calls fn for each length-delimited field of a protobuf message, skipping other fields
+func forEachBytesField(b []byte, fn func(num protowire.Number, value []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertNumbers replaces the json.Numbers in a decoded JSON value with int64 or float64,
+// so that they are written as numbers in YAML
+func convertNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = convertNumbers(child)
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = convertNumbers(child)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return v
+	}
+}
+
+// secretFields are the fields of a Secret that are shown when it is redacted
+var secretFields = map[string]bool{"apiVersion": true, "kind": true, "metadata": true, "type": true, "immutable": true}
+
+// redactSecret replaces the values in a Secret with RedactedValue, keeping the names of the keys.
+// Any other field that could hold secret data, including the body of an object stored as protobuf, is redacted too.
+func redactSecret(obj map[string]interface{}) {
+	for field, value := range obj {
+		if secretFields[field] {
+			continue
+		}
+		if field == "data" || field == "stringData" {
+			if values, ok := value.(map[string]interface{}); ok {
+				for k := range values {
+					values[k] = RedactedValue
+				}
+				continue
+			}
+		}
+		obj[field] = RedactedValue
+	}
+}
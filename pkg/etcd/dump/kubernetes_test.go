@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// encodeKubernetesProtobuf wraps body in the envelope the kubernetes apiserver stores protobuf objects in
+func encodeKubernetesProtobuf(apiVersion string, kind string, body []byte) []byte {
+	var typeMeta []byte
+	typeMeta = protowire.AppendTag(typeMeta, typeMetaAPIVersionField, protowire.BytesType)
+	typeMeta = protowire.AppendString(typeMeta, apiVersion)
+	typeMeta = protowire.AppendTag(typeMeta, typeMetaKindField, protowire.BytesType)
+	typeMeta = protowire.AppendString(typeMeta, kind)
+
+	b := append([]byte{}, kubernetesProtobufPrefix...)
+	b = protowire.AppendTag(b, unknownTypeMetaField, protowire.BytesType)
+	b = protowire.AppendBytes(b, typeMeta)
+	b = protowire.AppendTag(b, unknownRawField, protowire.BytesType)
+	b = protowire.AppendBytes(b, body)
+	// contentEncoding and contentType are empty for objects in etcd, but we check we skip unknown fields
+	b = protowire.AppendTag(b, 5, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	return b
+}
+
+// mapSink is a NodeSink that records values in a map
+type mapSink struct {
+	values map[string]string
+}
+
+func (s *mapSink) Put(ctx context.Context, key string, value []byte) error {
+	s.values[key] = string(value)
+	return nil
+}
+
+func (s *mapSink) Close() error {
+	return nil
+}
+
+func TestKubernetesDecodingSink(t *testing.T) {
+	ctx := context.TODO()
+
+	configMapBody := []byte("\n\x0a\x08settings")
+	values := map[string][]byte{
+		"/registry/configmaps/default/settings":    encodeKubernetesProtobuf("v1", "ConfigMap", configMapBody),
+		"/registry/secrets/default/credentials":    encodeKubernetesProtobuf("v1", "Secret", []byte("hunter2")),
+		"/registry/secrets/default/json":           []byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"json"},"data":{"password":"aHVudGVyMg=="}}`),
+		"/registry/secrets/default/untyped":        []byte(`{"password":"hunter2"}`),
+		"/registry/secrets/default/encrypted":      []byte("k8s:enc:aescbc:v1:key1:ciphertext"),
+		"/registry/example.com/widgets/default/w1": []byte(`{"apiVersion":"example.com/v1","kind":"Widget","spec":{"size":3,"ratio":0.5}}`),
+		"/registry/masterleases/10.0.0.1":          []byte("k8s\x00garbage"),
+		"/other/key":                               []byte(`{"not":"decoded"}`),
+	}
+
+	for _, showSecrets := range []bool{false, true} {
+		dest := &mapSink{values: make(map[string]string)}
+		sink, err := NewKubernetesDecodingSink(dest, DecodeYAML)
+		if err != nil {
+			t.Fatalf("NewKubernetesDecodingSink failed: %v", err)
+		}
+		sink.ShowSecrets = showSecrets
+		for k, v := range values {
+			if err := sink.Put(ctx, k, v); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+		}
+
+		decoded := dest.values["/registry/configmaps/default/settings"]
+		for _, expected := range []string{"apiVersion: v1\n", "kind: ConfigMap\n", "protobuf: " + base64.StdEncoding.EncodeToString(configMapBody) + "\n"} {
+			if !strings.Contains(decoded, expected) {
+				t.Errorf("decoded ConfigMap does not contain %q:\n%s", expected, decoded)
+			}
+		}
+
+		// Secrets are redacted by key, whatever they decode to
+		for _, key := range []string{"/registry/secrets/default/credentials", "/registry/secrets/default/json", "/registry/secrets/default/untyped"} {
+			decoded = dest.values[key]
+			// hunter2, and hunter2 base64 encoded (as is the protobuf body)
+			showsPassword := strings.Contains(decoded, "hunter2") || strings.Contains(decoded, "aHVudGVyMg==")
+			if showsPassword != showSecrets {
+				t.Errorf("with showSecrets=%v, decoded %s was:\n%s", showSecrets, key, decoded)
+			}
+		}
+		if decoded := dest.values["/registry/secrets/default/json"]; !strings.Contains(decoded, "kind: Secret\n") || !strings.Contains(decoded, "  name: json\n") {
+			t.Errorf("Secret was not decoded:\n%s", decoded)
+		}
+
+		encrypted := dest.values["/registry/secrets/default/encrypted"]
+		if showSecrets && encrypted != string(values["/registry/secrets/default/encrypted"]) {
+			t.Errorf("undecodable Secret was not passed on unchanged: %q", encrypted)
+		}
+		if !showSecrets && encrypted != RedactedValue {
+			t.Errorf("undecodable Secret was not redacted: %q", encrypted)
+		}
+
+		decoded = dest.values["/registry/example.com/widgets/default/w1"]
+		for _, expected := range []string{"kind: Widget\n", "  size: 3\n", "  ratio: 0.5\n"} {
+			if !strings.Contains(decoded, expected) {
+				t.Errorf("decoded custom resource does not contain %q:\n%s", expected, decoded)
+			}
+		}
+
+		if v := dest.values["/registry/masterleases/10.0.0.1"]; v != "k8s\x00garbage" {
+			t.Errorf("undecodable value was not passed on unchanged: %q", v)
+		}
+		if v := dest.values["/other/key"]; v != `{"not":"decoded"}` {
+			t.Errorf("value outside /registry/ was not passed on unchanged: %q", v)
+		}
+	}
+}
+
+func TestKubernetesDecodingSinkJSON(t *testing.T) {
+	dest := &mapSink{values: make(map[string]string)}
+	sink, err := NewKubernetesDecodingSink(dest, DecodeJSON)
+	if err != nil {
+		t.Fatalf("NewKubernetesDecodingSink failed: %v", err)
+	}
+	key := "/registry/configmaps/default/settings"
+	if err := sink.Put(context.TODO(), key, encodeKubernetesProtobuf("v1", "ConfigMap", []byte("body"))); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if !strings.Contains(dest.values[key], `"kind": "ConfigMap"`) {
+		t.Errorf("ConfigMap was not decoded to JSON:\n%s", dest.values[key])
+	}
+
+	if _, err := NewKubernetesDecodingSink(dest, "xml"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// FormatJSONLines is the format written by JSONLinesSink
+	FormatJSONLines = "jsonl"
+	// FormatTar is the format written by TarDumpSink
+	FormatTar = "tar"
+)
+
+// DumpManifest records how a dump was written, so that a reader can refuse a dump that does not hold the original values
+type DumpManifest struct {
+	// Format is the format the dump was written in
+	Format string `json:"format"`
+
+	// KeysOnly records that the values were left out
+	KeysOnly bool `json:"keysOnly,omitempty"`
+
+	// Decode records the format kubernetes objects were decoded to, if they were
+	Decode string `json:"decode,omitempty"`
+}
+
+// jsonLinesManifest is the first line of a dump written by JSONLinesSink
+type jsonLinesManifest struct {
+	Manifest *DumpManifest `json:"manifest"`
+}
+
+// tarManifestRecord is the PAX record holding the manifest, in the global header at the start of a dump written by TarDumpSink.
+// A key can have any name, so the manifest is not stored as a file.
+const tarManifestRecord = "ETCDMANAGER.manifest"
+
+// parseManifest parses a manifest serialized as JSON
+func parseManifest(data []byte) (*DumpManifest, error) {
+	manifest := &DumpManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("error parsing dump manifest: %v", err)
+	}
+	return manifest, nil
+}
+
+// checkLoadable returns an error unless the manifest describes a dump in format that holds the original values
+func checkLoadable(manifest *DumpManifest, format string) error {
+	if manifest == nil {
+		return fmt.Errorf("the dump has no manifest; only dumps written by etcd-dump -format=%s can be loaded", format)
+	}
+	if manifest.Format != format {
+		return fmt.Errorf("the dump was written in format %q, not %q", manifest.Format, format)
+	}
+	if manifest.KeysOnly {
+		return fmt.Errorf("the dump was written with -keys-only, and does not hold the values")
+	}
+	if manifest.Decode != "" {
+		return fmt.Errorf("the dump was written with -decode=%s, and does not hold the original values", manifest.Decode)
+	}
+	return nil
+}
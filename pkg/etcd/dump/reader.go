@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+// DumpReader reads the keys in a dump, so they can be loaded back into etcd
+type DumpReader interface {
+	// ReadTo passes each key in the dump to dest, in the order they were dumped, returning the number of keys read
+	ReadTo(ctx context.Context, dest etcdclient.NodeSink) (int, error)
+}
+
+// TarDumpReader reads a dump written by TarDumpSink, refusing dumps whose manifest shows they do not hold the original values.
+// TarDumpSink drops the leading slash from keys, so TarDumpReader adds a leading slash to every key;
+// keys that did not start with a slash cannot be told apart.
+type TarDumpReader struct {
+	in io.Reader
+}
+
+var _ DumpReader = &TarDumpReader{}
+
+func NewTarDumpReader(in io.Reader) *TarDumpReader {
+	return &TarDumpReader{
+		in: in,
+	}
+}
+
+func (r *TarDumpReader) ReadTo(ctx context.Context, dest etcdclient.NodeSink) (int, error) {
+	gzr, err := gzip.NewReader(r.in)
+	if err != nil {
+		return 0, fmt.Errorf("error opening gzip: %v", err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	count := 0
+	var manifest *DumpManifest
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			if manifest == nil {
+				return count, checkLoadable(manifest, FormatTar)
+			}
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("error reading tar header: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeXGlobalHeader && manifest == nil && count == 0 {
+			if data, found := hdr.PAXRecords[tarManifestRecord]; found {
+				manifest, err = parseManifest([]byte(data))
+				if err != nil {
+					return count, err
+				}
+				if err := checkLoadable(manifest, FormatTar); err != nil {
+					return count, err
+				}
+			}
+			continue
+		}
+		// TarDumpSink synthesizes directories for the paths of keys; only files are keys
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		// The manifest comes before the first key
+		if manifest == nil {
+			return count, checkLoadable(manifest, FormatTar)
+		}
+
+		value, err := io.ReadAll(tr)
+		if err != nil {
+			return count, fmt.Errorf("error reading tar data for %q: %v", hdr.Name, err)
+		}
+		key := "/" + strings.TrimPrefix(hdr.Name, "/")
+		if err := dest.Put(ctx, key, value); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// JSONLinesReader reads a dump written by JSONLinesSink, refusing dumps whose manifest shows they do not hold the original values.
+// The revisions and version of each key are passed on to destinations that implement etcdclient.KeyValueSink.
+type JSONLinesReader struct {
+	in io.Reader
+}
+
+var _ DumpReader = &JSONLinesReader{}
+
+func NewJSONLinesReader(in io.Reader) *JSONLinesReader {
+	return &JSONLinesReader{
+		in: in,
+	}
+}
+
+func (r *JSONLinesReader) ReadTo(ctx context.Context, dest etcdclient.NodeSink) (int, error) {
+	kvSink, _ := dest.(etcdclient.KeyValueSink)
+
+	decoder := json.NewDecoder(r.in)
+
+	header := &jsonLinesManifest{}
+	if err := decoder.Decode(header); err != nil && err != io.EOF {
+		return 0, fmt.Errorf("error parsing manifest: %v", err)
+	}
+	if err := checkLoadable(header.Manifest, FormatJSONLines); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for {
+		line := &JSONLine{}
+		if err := decoder.Decode(line); err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return count, fmt.Errorf("error parsing line %d: %v", count+1, err)
+		}
+
+		kv := &etcdclient.KeyValue{
+			Key:            line.Key,
+			CreateRevision: line.CreateRevision,
+			ModRevision:    line.ModRevision,
+			Version:        line.Version,
+			Lease:          line.Lease,
+		}
+		switch line.ValueEncoding {
+		case ValueEncodingText:
+			kv.Value = []byte(line.Value)
+		case ValueEncodingBase64:
+			value, err := base64.StdEncoding.DecodeString(line.Value)
+			if err != nil {
+				return count, fmt.Errorf("error decoding value of %q: %v", line.Key, err)
+			}
+			kv.Value = value
+		case "":
+			return count, fmt.Errorf("key %q has no value; the dump was written with -keys-only", line.Key)
+		default:
+			return count, fmt.Errorf("key %q has unknown value encoding %q", line.Key, line.ValueEncoding)
+		}
+
+		var err error
+		if kvSink != nil {
+			err = kvSink.PutKeyValue(ctx, kv)
+		} else {
+			err = dest.Put(ctx, kv.Key, kv.Value)
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+}
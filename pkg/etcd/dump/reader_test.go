@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+// kvSliceSink is a KeyValueSink that records the keys it is passed, in order
+type kvSliceSink struct {
+	kvs []etcdclient.KeyValue
+}
+
+func (s *kvSliceSink) Put(ctx context.Context, key string, value []byte) error {
+	s.kvs = append(s.kvs, etcdclient.KeyValue{Key: key, Value: value})
+	return nil
+}
+
+func (s *kvSliceSink) PutKeyValue(ctx context.Context, kv *etcdclient.KeyValue) error {
+	s.kvs = append(s.kvs, *kv)
+	return nil
+}
+
+func (s *kvSliceSink) Close() error {
+	return nil
+}
+
+var testDumpKeys = []etcdclient.KeyValue{
+	{Key: "/registry/namespaces/default", Value: []byte("k8s\x00\n\x0fbinary\xff"), CreateRevision: 3, ModRevision: 3, Version: 1},
+	{Key: "/registry/pods/default/web", Value: []byte("line one\nline two"), CreateRevision: 4, ModRevision: 9, Version: 2},
+	{Key: "/registry/pods/default/empty", Value: []byte{}, CreateRevision: 5, ModRevision: 5, Version: 1},
+}
+
+func TestTarDumpReader(t *testing.T) {
+	ctx := context.TODO()
+
+	p := filepath.Join(t.TempDir(), "dump.tar.gz")
+	sink, err := NewTarDumpSink(p)
+	if err != nil {
+		t.Fatalf("NewTarDumpSink failed: %v", err)
+	}
+	for _, kv := range testDumpKeys {
+		if err := sink.Put(ctx, kv.Key, kv.Value); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatalf("error opening dump: %v", err)
+	}
+	defer f.Close()
+
+	dest := &kvSliceSink{}
+	n, err := NewTarDumpReader(f).ReadTo(ctx, dest)
+	if err != nil {
+		t.Fatalf("ReadTo failed: %v", err)
+	}
+	if n != len(testDumpKeys) {
+		t.Errorf("ReadTo returned %d keys, expected %d", n, len(testDumpKeys))
+	}
+
+	// Tar dumps do not record revisions
+	var expected []etcdclient.KeyValue
+	for _, kv := range testDumpKeys {
+		expected = append(expected, etcdclient.KeyValue{Key: kv.Key, Value: kv.Value})
+	}
+	if !reflect.DeepEqual(dest.kvs, expected) {
+		t.Errorf("ReadTo read %v, expected %v", dest.kvs, expected)
+	}
+}
+
+func TestTarDumpReaderRefusesModifiedDumps(t *testing.T) {
+	ctx := context.TODO()
+
+	grid := []struct {
+		keysOnly bool
+		decode   string
+		expected string
+	}{
+		{keysOnly: true, expected: "written with -keys-only"},
+		{decode: "json", expected: "written with -decode=json"},
+	}
+	for _, g := range grid {
+		p := filepath.Join(t.TempDir(), "dump.tar.gz")
+		sink, err := NewTarDumpSink(p)
+		if err != nil {
+			t.Fatalf("NewTarDumpSink failed: %v", err)
+		}
+		sink.KeysOnly = g.keysOnly
+		sink.Decode = g.decode
+		if err := sink.Put(ctx, "/a", []byte("{}")); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			t.Fatalf("error opening dump: %v", err)
+		}
+		dest := &kvSliceSink{}
+		_, err = NewTarDumpReader(f).ReadTo(ctx, dest)
+		f.Close()
+		if err == nil || !strings.Contains(err.Error(), g.expected) {
+			t.Errorf("reading dump with keysOnly=%v decode=%q returned error %v, expected %q", g.keysOnly, g.decode, err, g.expected)
+		}
+		if len(dest.kvs) != 0 {
+			t.Errorf("reading dump with keysOnly=%v decode=%q read %d keys, expected none", g.keysOnly, g.decode, len(dest.kvs))
+		}
+	}
+}
+
+func TestTarDumpReaderWithoutManifest(t *testing.T) {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "a", Mode: 0644, Size: 1}); err != nil {
+		t.Fatalf("WriteHeader failed: %v", err)
+	}
+	if _, err := tw.Write([]byte("x")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	dest := &kvSliceSink{}
+	_, err := NewTarDumpReader(&buf).ReadTo(context.TODO(), dest)
+	if err == nil || !strings.Contains(err.Error(), "has no manifest") {
+		t.Errorf("reading dump without manifest returned error %v, expected %q", err, "has no manifest")
+	}
+	if len(dest.kvs) != 0 {
+		t.Errorf("reading dump without manifest read %d keys, expected none", len(dest.kvs))
+	}
+}
+
+func TestJSONLinesReader(t *testing.T) {
+	ctx := context.TODO()
+
+	for _, base64 := range []bool{false, true} {
+		var out bytes.Buffer
+		sink := NewJSONLinesSink(&out)
+		sink.Base64 = base64
+		for i := range testDumpKeys {
+			if err := sink.PutKeyValue(ctx, &testDumpKeys[i]); err != nil {
+				t.Fatalf("PutKeyValue failed: %v", err)
+			}
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		dest := &kvSliceSink{}
+		n, err := NewJSONLinesReader(&out).ReadTo(ctx, dest)
+		if err != nil {
+			t.Fatalf("ReadTo failed: %v", err)
+		}
+		if n != len(testDumpKeys) {
+			t.Errorf("ReadTo returned %d keys, expected %d", n, len(testDumpKeys))
+		}
+		if !reflect.DeepEqual(dest.kvs, testDumpKeys) {
+			t.Errorf("with base64=%v, ReadTo read %v, expected %v", base64, dest.kvs, testDumpKeys)
+		}
+	}
+}
+
+func TestJSONLinesReaderErrors(t *testing.T) {
+	manifest := `{"manifest":{"format":"jsonl"}}` + "\n"
+	grid := []struct {
+		in       string
+		expected string
+	}{
+		{``, "has no manifest"},
+		{`{"key":"/a","value":"x","valueEncoding":"text"}`, "has no manifest"},
+		{`{"manifest":{"format":"tar"}}`, `written in format "tar"`},
+		{`{"manifest":{"format":"jsonl","keysOnly":true}}`, "written with -keys-only"},
+		{`{"manifest":{"format":"jsonl","decode":"yaml"}}`, "written with -decode=yaml"},
+		{manifest + `{"key":"/a","createRevision":0,"modRevision":5,"version":0,"lease":0}`, "written with -keys-only"},
+		{manifest + `{"key":"/a","value":"x","valueEncoding":"hex"}`, "unknown value encoding"},
+		{manifest + `{"key":"/a","value":"!!","valueEncoding":"base64"}`, "error decoding value"},
+		{manifest + `{"key":"/a","value":"x","valueEncoding":"text"}` + "\n" + `a => b`, "error parsing line 2"},
+	}
+	for _, g := range grid {
+		_, err := NewJSONLinesReader(strings.NewReader(g.in)).ReadTo(context.TODO(), &kvSliceSink{})
+		if err == nil || !strings.Contains(err.Error(), g.expected) {
+			t.Errorf("reading %q returned error %v, expected %q", g.in, err, g.expected)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+func TestJSONLinesSink(t *testing.T) {
+	ctx := context.TODO()
+
+	var out bytes.Buffer
+	sink := NewJSONLinesSink(&out)
+	if err := sink.PutKeyValue(ctx, &etcdclient.KeyValue{Key: "/a", Value: []byte("text"), CreateRevision: 2, ModRevision: 5, Version: 3, Lease: 7}); err != nil {
+		t.Fatalf("PutKeyValue failed: %v", err)
+	}
+	if err := sink.Put(ctx, "/registry/pods/default/b", []byte("k8s\x00\xff\xfe")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	expected := `{"manifest":{"format":"jsonl"}}
+{"key":"/a","value":"text","valueEncoding":"text","createRevision":2,"modRevision":5,"version":3,"lease":7}
+{"key":"/registry/pods/default/b","value":"azhzAP/+","valueEncoding":"base64","createRevision":0,"modRevision":0,"version":0,"lease":0}
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+
+	out.Reset()
+	sink = NewJSONLinesSink(&out)
+	sink.KeysOnly = true
+	if err := sink.PutKeyValue(ctx, &etcdclient.KeyValue{Key: "/a", Value: []byte("text"), ModRevision: 5}); err != nil {
+		t.Fatalf("PutKeyValue failed: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	expected = `{"manifest":{"format":"jsonl","keysOnly":true}}
+{"key":"/a","createRevision":0,"modRevision":5,"version":0,"lease":0}
+`
+	if out.String() != expected {
+		t.Errorf("unexpected keys-only output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestEtcdctlSink(t *testing.T) {
+	ctx := context.TODO()
+
+	for _, keysOnly := range []bool{false, true} {
+		var out bytes.Buffer
+		sink := NewEtcdctlSink(&out)
+		sink.KeysOnly = keysOnly
+		for _, kv := range [][2]string{{"/a", "1"}, {"/b", "2"}} {
+			if err := sink.Put(ctx, kv[0], []byte(kv[1])); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		expected := "/a\n1\n/b\n2\n"
+		if keysOnly {
+			expected = "/a\n\n/b\n\n"
+		}
+		if out.String() != expected {
+			t.Errorf("unexpected output (keysOnly=%v): %q, expected %q", keysOnly, out.String(), expected)
+		}
+	}
+}
+
+func TestFilterSink(t *testing.T) {
+	ctx := context.TODO()
+
+	var out bytes.Buffer
+	sink := NewFilterSink(NewJSONLinesSink(&out), []string{"/registry/events/", "/registry/leases/"})
+	for _, key := range []string{"/registry/events/default/e", "/registry/pods/default/p", "/registry/leases/l"} {
+		if err := sink.PutKeyValue(ctx, &etcdclient.KeyValue{Key: key, Value: []byte("v"), ModRevision: 9}); err != nil {
+			t.Fatalf("PutKeyValue failed: %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	expected := `{"manifest":{"format":"jsonl"}}
+{"key":"/registry/pods/default/p","value":"v","valueEncoding":"text","createRevision":0,"modRevision":9,"version":0,"lease":0}
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
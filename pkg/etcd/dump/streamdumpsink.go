@@ -28,6 +28,9 @@ import (
 
 type StreamDumpSink struct {
 	out io.WriteCloser
+
+	// KeysOnly prints only the keys, without their values
+	KeysOnly bool
 }
 
 var _ etcdclient.NodeSink = &StreamDumpSink{}
@@ -49,7 +52,11 @@ func (s *StreamDumpSink) Put(ctx context.Context, key string, value []byte) erro
 		name = strings.TrimPrefix(name, "/")
 
 		var b bytes.Buffer
-		b.WriteString(fmt.Sprintf("%s => %s\n", name, string(value)))
+		if s.KeysOnly {
+			b.WriteString(fmt.Sprintf("%s\n", name))
+		} else {
+			b.WriteString(fmt.Sprintf("%s => %s\n", name, string(value)))
+		}
 
 		if _, err := b.WriteTo(s.out); err != nil {
 			return fmt.Errorf("error writing to output: %v", err)
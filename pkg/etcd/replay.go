@@ -0,0 +1,176 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/klog/v2"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+// errReplayTargetReached stops decoding a segment once we pass the recovery target
+var errReplayTargetReached = errors.New("recovery target reached")
+
+// expiredLeaseTTL is the TTL we grant for keys whose lease had expired before they were archived; etcd raises it to its minimum TTL
+const expiredLeaseTTL = 1
+
+// revisionWriter applies replayed changes; it is implemented by etcdclient.EtcdClient
+type revisionWriter interface {
+	Put(ctx context.Context, key string, value []byte) error
+	PutWithLease(ctx context.Context, key string, value []byte, lease int64) error
+	GrantLease(ctx context.Context, ttl int64) (int64, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// ValidateRecoveryTarget checks that a recovery target names a point to recover to
+func ValidateRecoveryTarget(target *protoetcd.RecoveryTarget) error {
+	if target.Revision < 0 || target.Timestamp < 0 {
+		return fmt.Errorf("recovery target revision and timestamp must not be negative")
+	}
+	if target.Revision == 0 && target.Timestamp == 0 && !target.Latest {
+		return fmt.Errorf("recovery target must specify a revision, a timestamp or latest")
+	}
+	return nil
+}
+
+// replayRevisions applies the archived revisions after baseRevision (the revision of the restored backup) to dest,
+// stopping at the recovery target.  It returns the last revision applied.
+// Keys that were attached to a lease are attached to a new lease with the same TTL, starting from when they are replayed;
+// keys that shared a lease share the new lease.
+func replayRevisions(ctx context.Context, archive backup.RevisionArchive, clusterID string, baseRevision int64, target *protoetcd.RecoveryTarget, dest revisionWriter) (int64, error) {
+	if err := ValidateRecoveryTarget(target); err != nil {
+		return 0, err
+	}
+	if target.Revision != 0 && target.Revision < baseRevision {
+		return 0, fmt.Errorf("backup is at revision %d, which is after the recovery target revision %d", baseRevision, target.Revision)
+	}
+
+	segments, err := archive.ListRevisionSegments(clusterID)
+	if err != nil {
+		return 0, fmt.Errorf("error listing archived revisions: %w", err)
+	}
+
+	applied := baseRevision
+	var appliedTimestamp int64
+
+	// leases maps the leases of the archived cluster to the leases we granted in their place
+	leases := make(map[int64]int64)
+	reachedTarget := false
+
+	for _, name := range segments {
+		startRevision, endRevision, err := backup.ParseRevisionSegmentName(name)
+		if err != nil {
+			return applied, err
+		}
+		if endRevision <= applied {
+			continue
+		}
+		if startRevision > applied+1 {
+			return applied, fmt.Errorf("archived revisions have a gap: revisions %d to %d are missing", applied+1, startRevision-1)
+		}
+
+		info, data, err := archive.ReadRevisionSegment(clusterID, name)
+		if err != nil {
+			return applied, fmt.Errorf("error reading revision segment %q: %w", name, err)
+		}
+		codec, err := backup.CodecFor(info.Compression)
+		if err != nil {
+			return applied, fmt.Errorf("unable to read revision segment %q: %w", name, err)
+		}
+
+		err = backup.DecodeRevisionSegment(codec, data, func(revision *protoetcd.ArchivedRevision) error {
+			if revision.Revision <= applied {
+				return nil
+			}
+			if target.Revision != 0 && revision.Revision > target.Revision {
+				return errReplayTargetReached
+			}
+			if target.Timestamp != 0 && revision.Timestamp > target.Timestamp {
+				return errReplayTargetReached
+			}
+
+			for _, event := range revision.Events {
+				key := string(event.Key)
+				if event.Delete {
+					if err := dest.Delete(ctx, key); err != nil {
+						return fmt.Errorf("error deleting key %q at revision %d: %w", key, revision.Revision, err)
+					}
+				} else if event.Lease != 0 {
+					lease, found := leases[event.Lease]
+					if !found {
+						ttl := event.LeaseTtl
+						if ttl <= 0 {
+							ttl = expiredLeaseTTL
+						}
+						granted, err := dest.GrantLease(ctx, ttl)
+						if err != nil {
+							return fmt.Errorf("error granting lease for key %q at revision %d: %w", key, revision.Revision, err)
+						}
+						lease = granted
+						leases[event.Lease] = lease
+					}
+					if err := dest.PutWithLease(ctx, key, event.Value, lease); err != nil {
+						return fmt.Errorf("error putting key %q at revision %d: %w", key, revision.Revision, err)
+					}
+				} else {
+					if err := dest.Put(ctx, key, event.Value); err != nil {
+						return fmt.Errorf("error putting key %q at revision %d: %w", key, revision.Revision, err)
+					}
+				}
+			}
+			applied = revision.Revision
+			appliedTimestamp = revision.Timestamp
+			return nil
+		})
+		if errors.Is(err, errReplayTargetReached) {
+			reachedTarget = true
+			break
+		}
+		if err != nil {
+			return applied, fmt.Errorf("error replaying revision segment %q: %w", name, err)
+		}
+
+		// The segment covers every revision up to its end
+		applied = endRevision
+	}
+
+	if target.Revision != 0 && applied >= target.Revision {
+		reachedTarget = true
+	}
+
+	if !reachedTarget {
+		if target.Revision != 0 {
+			return applied, fmt.Errorf("archived revisions end at revision %d, before the recovery target revision %d", applied, target.Revision)
+		}
+		if target.Timestamp != 0 {
+			klog.Warningf("archived revisions end at revision %d, before the recovery target time %s; later changes cannot be recovered", applied, time.Unix(target.Timestamp, 0).UTC().Format(time.RFC3339))
+		}
+	}
+
+	if appliedTimestamp != 0 {
+		klog.Infof("replayed archived revisions %d to %d (archived at %s)", baseRevision+1, applied, time.Unix(appliedTimestamp, 0).UTC().Format(time.RFC3339))
+	} else {
+		klog.Infof("no archived revisions to replay after revision %d", baseRevision)
+	}
+
+	return applied, nil
+}
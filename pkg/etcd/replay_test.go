@@ -0,0 +1,267 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+	protoetcd "sigs.k8s.io/etcd-manager/pkg/apis/etcd"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+)
+
+const testClusterID = "cdf818194e3a8c32"
+
+// mapWriter is a revisionWriter that records keys in a map
+type mapWriter struct {
+	keys map[string]string
+
+	// leaseTTLs holds the TTL of each lease granted, and keyLeases the lease each key is attached to
+	leaseTTLs map[int64]int64
+	keyLeases map[string]int64
+}
+
+func (w *mapWriter) Put(ctx context.Context, key string, value []byte) error {
+	w.keys[key] = string(value)
+	delete(w.keyLeases, key)
+	return nil
+}
+
+func (w *mapWriter) PutWithLease(ctx context.Context, key string, value []byte, lease int64) error {
+	if _, found := w.leaseTTLs[lease]; !found {
+		return fmt.Errorf("lease %d not found", lease)
+	}
+	w.keys[key] = string(value)
+	w.keyLeases[key] = lease
+	return nil
+}
+
+func (w *mapWriter) GrantLease(ctx context.Context, ttl int64) (int64, error) {
+	if w.leaseTTLs == nil {
+		w.leaseTTLs = make(map[int64]int64)
+		w.keyLeases = make(map[string]int64)
+	}
+	lease := int64(len(w.leaseTTLs) + 1)
+	w.leaseTTLs[lease] = ttl
+	return lease, nil
+}
+
+func (w *mapWriter) Delete(ctx context.Context, key string) error {
+	delete(w.keys, key)
+	return nil
+}
+
+// newTestArchive builds a revision archive holding a put of /k<rev> at each revision in the segments,
+// with revision n archived at timestamp 1000+n
+func newTestArchive(t *testing.T, segments ...[2]int64) backup.RevisionArchive {
+	p, err := vfs.Context.BuildVfsPath(filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := backup.NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+	archive := store.(backup.RevisionArchive)
+
+	for _, segment := range segments {
+		var revisions []*protoetcd.ArchivedRevision
+		for rev := segment[0]; rev <= segment[1]; rev++ {
+			revision := &protoetcd.ArchivedRevision{
+				Revision:  rev,
+				Timestamp: 1000 + rev,
+				Events: []*protoetcd.ArchivedEvent{
+					{Key: []byte(fmt.Sprintf("/k%d", rev)), Value: []byte("v")},
+				},
+			}
+			// Every fifth revision also deletes the key from the previous revision
+			if rev%5 == 0 {
+				revision.Events = append(revision.Events, &protoetcd.ArchivedEvent{Key: []byte(fmt.Sprintf("/k%d", rev-1)), Delete: true})
+			}
+			revisions = append(revisions, revision)
+		}
+		data, err := backup.EncodeRevisionSegment(store.Codec(), revisions)
+		if err != nil {
+			t.Fatalf("EncodeRevisionSegment failed: %v", err)
+		}
+		info := &protoetcd.RevisionSegmentInfo{
+			EtcdClusterId: testClusterID,
+			StartRevision: segment[0],
+			EndRevision:   segment[1],
+			Compression:   store.Codec().Name(),
+		}
+		if _, err := archive.AddRevisionSegment(info, data); err != nil {
+			t.Fatalf("AddRevisionSegment failed: %v", err)
+		}
+	}
+	return archive
+}
+
+func TestReplayRevisions(t *testing.T) {
+	grid := []struct {
+		Name         string
+		Segments     [][2]int64
+		Base         int64
+		Target       *protoetcd.RecoveryTarget
+		ExpectedLast int64
+		ExpectedKeys []string
+		ExpectedErr  string
+	}{
+		{
+			Name:         "to revision",
+			Segments:     [][2]int64{{11, 13}, {14, 20}},
+			Base:         10,
+			Target:       &protoetcd.RecoveryTarget{Revision: 16},
+			ExpectedLast: 16,
+			ExpectedKeys: []string{"/k11", "/k12", "/k13", "/k15", "/k16"},
+		},
+		{
+			Name:         "to time",
+			Segments:     [][2]int64{{11, 13}, {14, 20}},
+			Base:         10,
+			Target:       &protoetcd.RecoveryTarget{Timestamp: 1012},
+			ExpectedLast: 12,
+			ExpectedKeys: []string{"/k11", "/k12"},
+		},
+		{
+			Name:         "latest, with overlapping segments and a base part-way through a segment",
+			Segments:     [][2]int64{{5, 13}, {11, 15}, {16, 17}},
+			Base:         12,
+			Target:       &protoetcd.RecoveryTarget{Latest: true},
+			ExpectedLast: 17,
+			ExpectedKeys: []string{"/k13", "/k15", "/k16", "/k17"},
+		},
+		{
+			Name:        "gap",
+			Segments:    [][2]int64{{11, 13}, {15, 20}},
+			Base:        10,
+			Target:      &protoetcd.RecoveryTarget{Latest: true},
+			ExpectedErr: "revisions 14 to 14 are missing",
+		},
+		{
+			Name:        "archive ends before target",
+			Segments:    [][2]int64{{11, 13}},
+			Base:        10,
+			Target:      &protoetcd.RecoveryTarget{Revision: 30},
+			ExpectedErr: "before the recovery target revision 30",
+		},
+		{
+			Name:        "target before backup",
+			Segments:    [][2]int64{{11, 13}},
+			Base:        10,
+			Target:      &protoetcd.RecoveryTarget{Revision: 5},
+			ExpectedErr: "after the recovery target revision 5",
+		},
+		{
+			Name:        "empty target",
+			Base:        10,
+			Target:      &protoetcd.RecoveryTarget{},
+			ExpectedErr: "must specify a revision, a timestamp or latest",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			archive := newTestArchive(t, g.Segments...)
+			dest := &mapWriter{keys: make(map[string]string)}
+
+			last, err := replayRevisions(context.Background(), archive, testClusterID, g.Base, g.Target, dest)
+			if g.ExpectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), g.ExpectedErr) {
+					t.Fatalf("expected error containing %q, got %v", g.ExpectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("replayRevisions failed: %v", err)
+			}
+
+			if last != g.ExpectedLast {
+				t.Errorf("last replayed revision was %d, expected %d", last, g.ExpectedLast)
+			}
+
+			var keys []string
+			for k := range dest.keys {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			if !reflect.DeepEqual(keys, g.ExpectedKeys) {
+				t.Errorf("keys after replay were %v, expected %v", keys, g.ExpectedKeys)
+			}
+		})
+	}
+}
+
+func TestReplayRevisionsLeases(t *testing.T) {
+	p, err := vfs.Context.BuildVfsPath(filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("BuildVfsPath failed: %v", err)
+	}
+	store, err := backup.NewVFSStore(p, nil, nil)
+	if err != nil {
+		t.Fatalf("NewVFSStore failed: %v", err)
+	}
+	archive := store.(backup.RevisionArchive)
+
+	revisions := []*protoetcd.ArchivedRevision{
+		{Revision: 11, Events: []*protoetcd.ArchivedEvent{
+			{Key: []byte("/registry/events/a"), Value: []byte("1"), Lease: 0x100, LeaseTtl: 3600},
+			{Key: []byte("/registry/pods/p"), Value: []byte("2")},
+		}},
+		{Revision: 12, Events: []*protoetcd.ArchivedEvent{
+			{Key: []byte("/registry/events/b"), Value: []byte("3"), Lease: 0x100, LeaseTtl: 3600},
+			{Key: []byte("/registry/leases/l"), Value: []byte("4"), Lease: 0x200},
+		}},
+	}
+	data, err := backup.EncodeRevisionSegment(store.Codec(), revisions)
+	if err != nil {
+		t.Fatalf("EncodeRevisionSegment failed: %v", err)
+	}
+	info := &protoetcd.RevisionSegmentInfo{EtcdClusterId: testClusterID, StartRevision: 11, EndRevision: 12, Compression: store.Codec().Name()}
+	if _, err := archive.AddRevisionSegment(info, data); err != nil {
+		t.Fatalf("AddRevisionSegment failed: %v", err)
+	}
+
+	dest := &mapWriter{keys: make(map[string]string)}
+	if _, err := replayRevisions(context.TODO(), archive, testClusterID, 10, &protoetcd.RecoveryTarget{Latest: true}, dest); err != nil {
+		t.Fatalf("replayRevisions failed: %v", err)
+	}
+
+	if len(dest.keys) != 4 {
+		t.Errorf("expected 4 keys, got %v", dest.keys)
+	}
+	if _, found := dest.keyLeases["/registry/pods/p"]; found {
+		t.Errorf("key without a lease was attached to one")
+	}
+	eventLease := dest.keyLeases["/registry/events/a"]
+	if eventLease == 0 || dest.keyLeases["/registry/events/b"] != eventLease {
+		t.Errorf("keys that shared a lease should share a new lease, got %v", dest.keyLeases)
+	}
+	if dest.leaseTTLs[eventLease] != 3600 {
+		t.Errorf("expected the new lease to have the archived TTL, got %d", dest.leaseTTLs[eventLease])
+	}
+	// The lease of /registry/leases/l had expired when it was archived
+	if ttl := dest.leaseTTLs[dest.keyLeases["/registry/leases/l"]]; ttl != expiredLeaseTTL {
+		t.Errorf("expected a key whose lease had expired to get a lease with TTL %d, got %d", expiredLeaseTTL, ttl)
+	}
+}
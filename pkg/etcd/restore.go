@@ -56,6 +56,11 @@ func (s *EtcdServer) DoRestore(ctx context.Context, request *protoetcd.DoRestore
 	if request.BackupName == "" {
 		return nil, fmt.Errorf("request BackupName is required")
 	}
+	if request.Target != nil {
+		if err := ValidateRecoveryTarget(request.Target); err != nil {
+			return nil, err
+		}
+	}
 
 	backupStore, err := backup.NewStore(request.Storage)
 	if err != nil {
@@ -74,7 +79,7 @@ func (s *EtcdServer) DoRestore(ctx context.Context, request *protoetcd.DoRestore
 		}
 	}()
 
-	p, err := RunEtcdFromBackup(backupStore, request.BackupName, tempDir)
+	p, err := RunEtcdFromBackup(backupStore, request.BackupName, tempDir, request.Target)
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +105,9 @@ func (s *EtcdServer) DoRestore(ctx context.Context, request *protoetcd.DoRestore
 	return response, nil
 }
 
-func RunEtcdFromBackup(backupStore backup.Store, backupName string, basedir string) (*etcdProcess, error) {
+// RunEtcdFromBackup starts a temporary etcd process holding the data from a backup.
+// If target is non-nil, revisions archived after the backup was taken are replayed on top of it, up to the target.
+func RunEtcdFromBackup(backupStore backup.Store, backupName string, basedir string, target *protoetcd.RecoveryTarget) (*etcdProcess, error) {
 	dataDir := filepath.Join(basedir, DataDirName)
 	pkiDir := filepath.Join(basedir, PkiDirName)
 	clusterToken := filepath.Base(dataDir)
@@ -114,8 +121,13 @@ func RunEtcdFromBackup(backupStore backup.Store, backupName string, basedir stri
 		return nil, fmt.Errorf("this version of etcd-manager does not support etcd v2")
 	}
 
+	codec, err := backup.CodecFor(backupInfo.Compression)
+	if err != nil {
+		return nil, fmt.Errorf("unable to restore backup %q: %w", backupName, err)
+	}
+
 	// V3 requires that data dir not exist
-	downloadFile := filepath.Join(basedir, "download", "snapshot.db.gz")
+	downloadFile := filepath.Join(basedir, "download", "snapshot.db"+codec.Extension())
 
 	klog.Infof("Downloading backup %q to %s", backupName, downloadFile)
 	if err := backupStore.DownloadBackup(backupName, downloadFile); err != nil {
@@ -189,7 +201,7 @@ func RunEtcdFromBackup(backupStore backup.Store, backupName string, basedir stri
 	klog.Infof("restoring snapshot")
 
 	snapshotFile := filepath.Join(basedir, "download", "snapshot.db")
-	archive := &gzFile{File: downloadFile}
+	archive := &compressedFile{File: downloadFile, Codec: codec}
 	if err := archive.expand(snapshotFile); err != nil {
 		return nil, fmt.Errorf("error expanding snapshot: %w", err)
 	}
@@ -203,6 +215,15 @@ func RunEtcdFromBackup(backupStore backup.Store, backupName string, basedir stri
 		return nil, fmt.Errorf("error starting etcd: %w", err)
 	}
 
+	if target != nil {
+		if err := replayArchivedRevisions(context.TODO(), p, backupStore, backupName, backupInfo, target); err != nil {
+			if stopErr := p.Stop(); stopErr != nil {
+				klog.Warningf("unable to stop etcd process that was started for restore: %v", stopErr)
+			}
+			return nil, err
+		}
+	}
+
 	return p, nil
 }
 
@@ -213,26 +234,67 @@ func copyEtcd(ctx context.Context, source *etcdProcess, dest etcdclient.NodeSink
 	}
 	defer sourceClient.Close()
 
+	if err := waitForEtcd(ctx, source, sourceClient); err != nil {
+		return err
+	}
+
+	klog.Infof("copying etcd keys from backup-restore process to new cluster")
+	if n, err := sourceClient.CopyTo(ctx, dest); err != nil {
+		return fmt.Errorf("error copying keys: %w", err)
+	} else {
+		klog.Infof("restored %d keys", n)
+	}
+
+	return nil
+}
+
+// waitForEtcd waits for the etcd process to start serving requests
+func waitForEtcd(ctx context.Context, p *etcdProcess, client *etcdclient.EtcdClient) error {
 	for range 60 {
-		_, err := sourceClient.Get(ctx, "/", true, 2*time.Second)
+		_, err := client.Get(ctx, "/", true, 2*time.Second)
 		if err == nil {
 			break
 		}
 
-		exitState, exitError := source.ExitState()
+		exitState, exitError := p.ExitState()
 		if exitError != nil || exitState != nil {
 			return fmt.Errorf("source etcd process exited (state=%v): %w", exitState, exitError)
 		}
 		klog.Infof("Waiting for etcd to start (%v)", err)
 		time.Sleep(time.Second)
 	}
+	return nil
+}
 
-	klog.Infof("copying etcd keys from backup-restore process to new cluster")
-	if n, err := sourceClient.CopyTo(ctx, dest); err != nil {
-		return fmt.Errorf("error copying keys: %w", err)
-	} else {
-		klog.Infof("restored %d keys", n)
+// replayArchivedRevisions rolls the etcd process restored from a backup forward to the recovery target,
+// by replaying the revisions archived after the backup was taken
+func replayArchivedRevisions(ctx context.Context, p *etcdProcess, backupStore backup.Store, backupName string, backupInfo *protoetcd.BackupInfo, target *protoetcd.RecoveryTarget) error {
+	archive, ok := backupStore.(backup.RevisionArchive)
+	if !ok {
+		return fmt.Errorf("backup store %s does not support archived revisions", backupStore.Spec())
+	}
+	if backupInfo.EtcdClusterId == "" {
+		return fmt.Errorf("backup %q does not record its etcd cluster id, so archived revisions cannot be matched to it", backupName)
 	}
 
+	client, err := p.NewClient()
+	if err != nil {
+		return fmt.Errorf("error building etcd client: %w", err)
+	}
+	defer etcdclient.LoggedClose(client)
+
+	if err := waitForEtcd(ctx, p, client); err != nil {
+		return err
+	}
+
+	baseRevision, err := client.CurrentRevision(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading revision of restored backup: %w", err)
+	}
+
+	klog.Infof("replaying archived revisions after revision %d onto backup %q", baseRevision, backupName)
+	if _, err := replayRevisions(ctx, archive, backupInfo.EtcdClusterId, baseRevision, target, client); err != nil {
+		return fmt.Errorf("error replaying archived revisions: %w", err)
+	}
 	return nil
 }
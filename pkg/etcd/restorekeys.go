@@ -0,0 +1,230 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
+	"sigs.k8s.io/etcd-manager/pkg/etcd/dump"
+	"sigs.k8s.io/etcd-manager/pkg/etcdclient"
+)
+
+// KeyChange describes what restoring a key from a backup does to the live cluster
+type KeyChange string
+
+const (
+	// KeyCreated is a key that is in the backup but not in the live cluster
+	KeyCreated KeyChange = "create"
+	// KeyOverwritten is a key whose live value differs from the backup, and is replaced with the value from the backup
+	KeyOverwritten KeyChange = "overwrite"
+	// KeySkipped is a key whose live value differs from the backup, and is left alone because we are only creating keys
+	KeySkipped KeyChange = "skip"
+	// KeyUnchanged is a key whose live value is the same as in the backup
+	KeyUnchanged KeyChange = "unchanged"
+)
+
+// RestoredKey records the change made (or, in a dry run, that would be made) to a key
+type RestoredKey struct {
+	Key    string
+	Change KeyChange
+
+	// BackupSize is the size of the value in the backup
+	BackupSize int
+	// LiveSize is the size of the value in the live cluster before the restore, or -1 if the key did not exist
+	LiveSize int
+}
+
+// KeyChangeCounts counts restored keys by the change made to them
+type KeyChangeCounts map[KeyChange]int
+
+// Written returns the number of keys that were created or overwritten
+func (c KeyChangeCounts) Written() int {
+	return c[KeyCreated] + c[KeyOverwritten]
+}
+
+func (c KeyChangeCounts) String() string {
+	return fmt.Sprintf("%d created, %d overwritten, %d skipped, %d unchanged", c[KeyCreated], c[KeyOverwritten], c[KeySkipped], c[KeyUnchanged])
+}
+
+// PrintKeyChanges prints a line to w for each key that was created, overwritten or skipped, and counts the changes.
+// action is what was done to the keys, such as "restored", and is printed for skipped keys.
+func PrintKeyChanges(w io.Writer, changes []RestoredKey, action string) KeyChangeCounts {
+	counts := make(KeyChangeCounts)
+	for _, change := range changes {
+		counts[change.Change]++
+		switch change.Change {
+		case KeyCreated:
+			fmt.Fprintf(w, "+ %s (%d bytes)\n", change.Key, change.BackupSize)
+		case KeyOverwritten:
+			fmt.Fprintf(w, "~ %s (%d bytes -> %d bytes)\n", change.Key, change.LiveSize, change.BackupSize)
+		case KeySkipped:
+			fmt.Fprintf(w, "! %s (exists with a different value; not %s)\n", change.Key, action)
+		}
+	}
+	return counts
+}
+
+// RestoreKeysOptions controls RestoreKeys
+type RestoreKeysOptions struct {
+	// Prefix selects the keys to restore: the key equal to the prefix, and the keys below it in the key hierarchy,
+	// so /registry/namespaces/foo does not select /registry/namespaces/foobar.
+	// It is required by RestoreKeys, and LoadKeys loads every key if it is not set.
+	Prefix string
+
+	// CreateOnly restores only keys that do not exist in the live cluster, leaving existing keys alone
+	CreateOnly bool
+
+	// DryRun reports the changes without making them
+	DryRun bool
+}
+
+// keyRestoreTarget is the live cluster keys are restored into; it is implemented by etcdclient.EtcdClient
+type keyRestoreTarget interface {
+	GetWithModRevision(ctx context.Context, key string, timeout time.Duration) ([]byte, int64, error)
+	PutIfUnchanged(ctx context.Context, key string, value []byte, modRevision int64) error
+	Create(ctx context.Context, key string, value []byte) error
+}
+
+// keyUnderPrefix returns true if key is prefix, or is below prefix in the key hierarchy.
+// An empty prefix, or one ending with a /, matches any key starting with it.
+func keyUnderPrefix(key string, prefix string) bool {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(key, prefix)
+	}
+	return key == prefix || strings.HasPrefix(key, prefix+"/")
+}
+
+// keyRestoreSink is a NodeSink that compares each key from the backup with the live cluster, and restores it
+type keyRestoreSink struct {
+	dest    keyRestoreTarget
+	options RestoreKeysOptions
+
+	changes []RestoredKey
+}
+
+var _ etcdclient.NodeSink = &keyRestoreSink{}
+
+func (s *keyRestoreSink) Put(ctx context.Context, key string, value []byte) error {
+	if !keyUnderPrefix(key, s.options.Prefix) {
+		return nil
+	}
+
+	live, liveModRevision, err := s.dest.GetWithModRevision(ctx, key, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("error reading live value of %q: %w", key, err)
+	}
+
+	change := RestoredKey{Key: key, BackupSize: len(value), LiveSize: -1}
+	switch {
+	case live == nil:
+		change.Change = KeyCreated
+	case bytes.Equal(live, value):
+		change.LiveSize = len(live)
+		change.Change = KeyUnchanged
+	case s.options.CreateOnly:
+		change.LiveSize = len(live)
+		change.Change = KeySkipped
+	default:
+		change.LiveSize = len(live)
+		change.Change = KeyOverwritten
+	}
+	s.changes = append(s.changes, change)
+
+	if s.options.DryRun {
+		return nil
+	}
+	switch change.Change {
+	case KeyCreated:
+		// Create fails if the key has been written since we read it, rather than overwriting it
+		return s.dest.Create(ctx, key, value)
+	case KeyOverwritten:
+		// Similarly, we don't overwrite a value written since we compared it
+		return s.dest.PutIfUnchanged(ctx, key, value, liveModRevision)
+	}
+	return nil
+}
+
+func (s *keyRestoreSink) Close() error {
+	return nil
+}
+
+// RestoreKeys copies the keys under a prefix from a backup into a live cluster, returning the change made to each key.
+// Keys under the prefix that are not in the backup are left alone.
+func RestoreKeys(ctx context.Context, backupStore backup.Store, backupName string, dest *etcdclient.EtcdClient, options RestoreKeysOptions) ([]RestoredKey, error) {
+	if options.Prefix == "" {
+		return nil, fmt.Errorf("a key prefix is required; use restore-backup to restore the whole cluster")
+	}
+
+	clusterToken := "restore-keys-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	tempDir := filepath.Join(os.TempDir(), clusterToken)
+	if err := os.MkdirAll(tempDir, 0700); err != nil {
+		return nil, fmt.Errorf("error creating tempdir %q: %w", tempDir, err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			klog.Warningf("error cleaning up tempdir %q: %v", tempDir, err)
+		}
+	}()
+
+	p, err := RunEtcdFromBackup(backupStore, backupName, tempDir, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		klog.Infof("stopping etcd that was reading backup")
+		if err := p.Stop(); err != nil {
+			klog.Warningf("unable to stop etcd process that was started for restore: %v", err)
+		}
+	}()
+
+	sourceClient, err := p.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("error building etcd client: %w", err)
+	}
+	defer etcdclient.LoggedClose(sourceClient)
+
+	if err := waitForEtcd(ctx, p, sourceClient); err != nil {
+		return nil, err
+	}
+
+	sink := &keyRestoreSink{dest: dest, options: options}
+	klog.Infof("restoring keys under %q from backup %q", options.Prefix, backupName)
+	if _, err := sourceClient.CopyPrefixTo(ctx, options.Prefix, sink); err != nil {
+		return sink.changes, fmt.Errorf("error restoring keys: %w", err)
+	}
+	return sink.changes, nil
+}
+
+// LoadKeys writes the keys read from a dump into a live cluster, returning the change made to each key.
+// Unlike a restore from a backup, this does not depend on the etcd version that wrote the dump.
+func LoadKeys(ctx context.Context, reader dump.DumpReader, dest *etcdclient.EtcdClient, options RestoreKeysOptions) ([]RestoredKey, error) {
+	sink := &keyRestoreSink{dest: dest, options: options}
+	if _, err := reader.ReadTo(ctx, sink); err != nil {
+		return sink.changes, fmt.Errorf("error loading keys: %w", err)
+	}
+	return sink.changes, nil
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/etcd-manager/pkg/etcd/dump"
+)
+
+// mapTarget is a keyRestoreTarget that holds keys in a map, with the revision each was last modified at
+type mapTarget struct {
+	keys map[string]string
+
+	revision     int64
+	modRevisions map[string]int64
+}
+
+func (m *mapTarget) GetWithModRevision(ctx context.Context, key string, timeout time.Duration) ([]byte, int64, error) {
+	v, found := m.keys[key]
+	if !found {
+		return nil, 0, nil
+	}
+	return []byte(v), m.modRevisions[key], nil
+}
+
+func (m *mapTarget) put(key string, value []byte) {
+	if m.modRevisions == nil {
+		m.modRevisions = make(map[string]int64)
+	}
+	m.revision++
+	m.keys[key] = string(value)
+	m.modRevisions[key] = m.revision
+}
+
+func (m *mapTarget) PutIfUnchanged(ctx context.Context, key string, value []byte, modRevision int64) error {
+	if m.modRevisions[key] != modRevision {
+		return fmt.Errorf("key %q has been modified since revision %d", key, modRevision)
+	}
+	m.put(key, value)
+	return nil
+}
+
+func (m *mapTarget) Create(ctx context.Context, key string, value []byte) error {
+	if _, found := m.keys[key]; found {
+		return fmt.Errorf("key %q already exists", key)
+	}
+	m.put(key, value)
+	return nil
+}
+
+func TestKeyRestoreSink(t *testing.T) {
+	backupKeys := [][2]string{
+		{"/registry/namespaces/foo", "ns"},
+		{"/registry/namespaces/foo/cm", "restored"},
+		{"/registry/namespaces/foo/secret", "same"},
+	}
+	live := map[string]string{
+		"/registry/namespaces/foo/cm":     "edited",
+		"/registry/namespaces/foo/secret": "same",
+		"/registry/namespaces/foo/new":    "written since",
+	}
+
+	grid := []struct {
+		name     string
+		options  RestoreKeysOptions
+		expected []KeyChange
+		after    map[string]string
+	}{
+		{
+			name:     "overwrite",
+			options:  RestoreKeysOptions{},
+			expected: []KeyChange{KeyCreated, KeyOverwritten, KeyUnchanged},
+			after: map[string]string{
+				"/registry/namespaces/foo":        "ns",
+				"/registry/namespaces/foo/cm":     "restored",
+				"/registry/namespaces/foo/secret": "same",
+				"/registry/namespaces/foo/new":    "written since",
+			},
+		},
+		{
+			name:     "create-only",
+			options:  RestoreKeysOptions{CreateOnly: true},
+			expected: []KeyChange{KeyCreated, KeySkipped, KeyUnchanged},
+			after: map[string]string{
+				"/registry/namespaces/foo":        "ns",
+				"/registry/namespaces/foo/cm":     "edited",
+				"/registry/namespaces/foo/secret": "same",
+				"/registry/namespaces/foo/new":    "written since",
+			},
+		},
+		{
+			name:     "dry-run",
+			options:  RestoreKeysOptions{DryRun: true},
+			expected: []KeyChange{KeyCreated, KeyOverwritten, KeyUnchanged},
+			after:    live,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			target := &mapTarget{keys: make(map[string]string)}
+			for k, v := range live {
+				target.keys[k] = v
+			}
+			sink := &keyRestoreSink{dest: target, options: g.options}
+
+			ctx := context.TODO()
+			for _, kv := range backupKeys {
+				if err := sink.Put(ctx, kv[0], []byte(kv[1])); err != nil {
+					t.Fatalf("Put(%q) failed: %v", kv[0], err)
+				}
+			}
+
+			var changes []KeyChange
+			for _, change := range sink.changes {
+				changes = append(changes, change.Change)
+			}
+			if !reflect.DeepEqual(changes, g.expected) {
+				t.Errorf("changes were %v, expected %v", changes, g.expected)
+			}
+			if !reflect.DeepEqual(target.keys, g.after) {
+				t.Errorf("live keys were %v, expected %v", target.keys, g.after)
+			}
+		})
+	}
+}
+
+func TestKeyRestoreSinkSizes(t *testing.T) {
+	target := &mapTarget{keys: map[string]string{"/a": "live"}}
+	sink := &keyRestoreSink{dest: target, options: RestoreKeysOptions{DryRun: true}}
+
+	ctx := context.TODO()
+	if err := sink.Put(ctx, "/a", []byte("backup value")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if err := sink.Put(ctx, "/b", []byte("new")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	expected := []RestoredKey{
+		{Key: "/a", Change: KeyOverwritten, BackupSize: 12, LiveSize: 4},
+		{Key: "/b", Change: KeyCreated, BackupSize: 3, LiveSize: -1},
+	}
+	if !reflect.DeepEqual(sink.changes, expected) {
+		t.Errorf("changes were %v, expected %v", sink.changes, expected)
+	}
+}
+
+func TestKeyRestoreSinkFromDump(t *testing.T) {
+	in := `{"manifest":{"format":"jsonl"}}
+{"key":"/registry/namespaces/foo","value":"ns","valueEncoding":"text","createRevision":2,"modRevision":2,"version":1,"lease":0}
+{"key":"/registry/namespaces/foo/cm","value":"AAE=","valueEncoding":"base64","createRevision":3,"modRevision":4,"version":2,"lease":0}
+{"key":"/registry/pods/default/web","value":"pod","valueEncoding":"text","createRevision":5,"modRevision":5,"version":1,"lease":0}
+`
+	target := &mapTarget{keys: map[string]string{"/registry/namespaces/foo": "ns"}}
+	sink := &keyRestoreSink{dest: target, options: RestoreKeysOptions{Prefix: "/registry/namespaces/"}}
+
+	if _, err := dump.NewJSONLinesReader(strings.NewReader(in)).ReadTo(context.TODO(), sink); err != nil {
+		t.Fatalf("ReadTo failed: %v", err)
+	}
+
+	expected := []RestoredKey{
+		{Key: "/registry/namespaces/foo", Change: KeyUnchanged, BackupSize: 2, LiveSize: 2},
+		{Key: "/registry/namespaces/foo/cm", Change: KeyCreated, BackupSize: 2, LiveSize: -1},
+	}
+	if !reflect.DeepEqual(sink.changes, expected) {
+		t.Errorf("changes were %v, expected %v", sink.changes, expected)
+	}
+	if v := target.keys["/registry/namespaces/foo/cm"]; v != "\x00\x01" {
+		t.Errorf("loaded value was %q, expected the decoded base64 value", v)
+	}
+	if _, found := target.keys["/registry/pods/default/web"]; found {
+		t.Errorf("key outside the prefix was loaded")
+	}
+}
+
+func TestKeyRestoreSinkPrefix(t *testing.T) {
+	target := &mapTarget{keys: map[string]string{"/registry/namespaces/foobar": "live", "/registry/namespaces/foo-prod": "live"}}
+	sink := &keyRestoreSink{dest: target, options: RestoreKeysOptions{Prefix: "/registry/namespaces/foo"}}
+
+	ctx := context.TODO()
+	for _, key := range []string{"/registry/namespaces/foo", "/registry/namespaces/foo-prod", "/registry/namespaces/foo/cm", "/registry/namespaces/foobar"} {
+		if err := sink.Put(ctx, key, []byte("backup")); err != nil {
+			t.Fatalf("Put(%q) failed: %v", key, err)
+		}
+	}
+
+	var keys []string
+	for _, change := range sink.changes {
+		keys = append(keys, change.Key)
+	}
+	expected := []string{"/registry/namespaces/foo", "/registry/namespaces/foo/cm"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("restored keys were %v, expected %v", keys, expected)
+	}
+	if target.keys["/registry/namespaces/foobar"] != "live" || target.keys["/registry/namespaces/foo-prod"] != "live" {
+		t.Errorf("keys that only share a string prefix were changed: %v", target.keys)
+	}
+}
+
+func TestKeyRestoreSinkConcurrentWrite(t *testing.T) {
+	target := &mapTarget{keys: make(map[string]string)}
+	target.put("/a", []byte("live"))
+
+	// A write between the comparison and the overwrite must not be lost
+	racing := &racingTarget{mapTarget: target}
+	sink := &keyRestoreSink{dest: racing}
+	if err := sink.Put(context.TODO(), "/a", []byte("backup")); err == nil {
+		t.Errorf("expected the overwrite to fail after a concurrent write")
+	}
+	if target.keys["/a"] != "written since" {
+		t.Errorf("concurrent write was overwritten: %q", target.keys["/a"])
+	}
+}
+
+// racingTarget writes to each key just after it is read
+type racingTarget struct {
+	*mapTarget
+}
+
+func (r *racingTarget) GetWithModRevision(ctx context.Context, key string, timeout time.Duration) ([]byte, int64, error) {
+	value, modRevision, err := r.mapTarget.GetWithModRevision(ctx, key, timeout)
+	r.put(key, []byte("written since"))
+	return value, modRevision, err
+}
+
+func TestRestoreKeysRequiresPrefix(t *testing.T) {
+	if _, err := RestoreKeys(context.TODO(), nil, "backup", nil, RestoreKeysOptions{}); err == nil {
+		t.Errorf("expected an error restoring keys without a prefix")
+	}
+}
+
+func TestPrintKeyChanges(t *testing.T) {
+	changes := []RestoredKey{
+		{Key: "/a", Change: KeyCreated, BackupSize: 3, LiveSize: -1},
+		{Key: "/b", Change: KeyOverwritten, BackupSize: 5, LiveSize: 2},
+		{Key: "/c", Change: KeySkipped, BackupSize: 1, LiveSize: 1},
+		{Key: "/d", Change: KeyUnchanged, BackupSize: 4, LiveSize: 4},
+		{Key: "/e", Change: KeyCreated, BackupSize: 0, LiveSize: -1},
+	}
+
+	var out bytes.Buffer
+	counts := PrintKeyChanges(&out, changes, "loaded")
+
+	expected := `+ /a (3 bytes)
+~ /b (2 bytes -> 5 bytes)
+! /c (exists with a different value; not loaded)
++ /e (0 bytes)
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+	if counts.Written() != 3 {
+		t.Errorf("Written returned %d, expected 3", counts.Written())
+	}
+	if s := counts.String(); s != "2 created, 1 overwritten, 1 skipped, 1 unchanged" {
+		t.Errorf("unexpected summary %q", s)
+	}
+}
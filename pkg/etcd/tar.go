@@ -17,46 +17,53 @@ limitations under the License.
 package etcd
 
 import (
-	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
 
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/etcd-manager/pkg/backup"
 )
 
-type gzFile struct {
-	File string
+// compressedFile is a file compressed with one of the backup codecs
+type compressedFile struct {
+	File  string
+	Codec backup.Codec
 }
 
-// gunzip will uncompress the file srcFile, writing it to destFile
-func (g *gzFile) expand(destFile string) error {
-	src, err := os.Open(g.File)
+// expand will uncompress the file, writing it to destFile
+func (c *compressedFile) expand(destFile string) error {
+	src, err := os.Open(c.File)
 	if err != nil {
-		return fmt.Errorf("error opening %q: %w", g.File, err)
+		return fmt.Errorf("error opening %q: %w", c.File, err)
 	}
 	defer src.Close()
 
 	dest, err := os.Create(destFile)
 	if err != nil {
-		return fmt.Errorf("error opening %q: %w", g.File, err)
+		return fmt.Errorf("error opening %q: %w", destFile, err)
 	}
 	defer dest.Close()
 
-	gz, err := gzip.NewReader(src)
+	r, err := c.Codec.NewReader(src)
 	if err != nil {
-		return fmt.Errorf("error reading gzip %q (source corrupted?): %w", g.File, err)
+		return fmt.Errorf("error reading %s %q (source corrupted?): %w", c.Codec.Name(), c.File, err)
 	}
+	defer r.Close()
 
-	n, err := io.Copy(dest, gz)
+	n, err := io.Copy(dest, r)
 	if err != nil {
 		return fmt.Errorf("error expanding file: %w", err)
 	}
 	klog.V(2).Infof("expanded snapshot file, size=%d bytes", n)
 
-	if err := gz.Close(); err != nil {
+	if err := r.Close(); err != nil {
 		return fmt.Errorf("error completing file expansion: %w", err)
 	}
 
+	if err := dest.Close(); err != nil {
+		return fmt.Errorf("error writing %q: %w", destFile, err)
+	}
+
 	return nil
 }
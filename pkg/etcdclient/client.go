@@ -17,7 +17,6 @@ limitations under the License.
 package etcdclient
 
 import (
-	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"encoding/json"
@@ -25,7 +24,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -52,6 +50,25 @@ type NodeSink interface {
 	Put(ctx context.Context, key string, value []byte) error
 }
 
+// KeyValue is a key read from etcd, with its etcd metadata
+type KeyValue struct {
+	Key   string
+	Value []byte
+
+	CreateRevision int64
+	ModRevision    int64
+	Version        int64
+	Lease          int64
+}
+
+// KeyValueSink is implemented by targets for CopyTo that record the etcd metadata of each key;
+// PutKeyValue is called instead of Put.
+type KeyValueSink interface {
+	NodeSink
+
+	PutKeyValue(ctx context.Context, kv *KeyValue) error
+}
+
 func NewClient(endpoints []string, tlsConfig *tls.Config) (*EtcdClient, error) {
 	if len(endpoints) == 0 {
 		return nil, fmt.Errorf("no endpoints provided")
@@ -158,6 +175,36 @@ func (c *EtcdClient) Get(ctx context.Context, key string, quorum bool, timeout t
 	return r.Kvs[0].Value, nil
 }
 
+// GetWithModRevision returns the value of a key and the revision at which it was last modified, or nil and 0 if the key does not exist
+func (c *EtcdClient) GetWithModRevision(ctx context.Context, key string, timeout time.Duration) ([]byte, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	r, err := c.kv.Get(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(r.Kvs) == 0 {
+		return nil, 0, nil
+	}
+	return r.Kvs[0].Value, r.Kvs[0].ModRevision, nil
+}
+
+// PutIfUnchanged sets the value of a key, failing if the key has been modified since modRevision
+func (c *EtcdClient) PutIfUnchanged(ctx context.Context, key string, value []byte, modRevision int64) error {
+	txn := c.kv.Txn(ctx)
+	txn.If(etcd_client_v3.Compare(etcd_client_v3.ModRevision(key), "=", modRevision))
+	txn.Then(etcd_client_v3.OpPut(key, string(value)))
+	response, err := txn.Commit()
+	if err != nil {
+		return err
+	}
+	if !response.Succeeded {
+		return fmt.Errorf("key %q has been modified since revision %d", key, modRevision)
+	}
+	return nil
+}
+
 func (c *EtcdClient) Create(ctx context.Context, key string, value []byte) error {
 	txn := c.kv.Txn(ctx)
 	txn.If(etcd_client_v3.Compare(etcd_client_v3.CreateRevision(key), "=", 0))
@@ -181,19 +228,143 @@ func (c *EtcdClient) Put(ctx context.Context, key string, value []byte) error {
 	return nil
 }
 
+// PutWithLease writes a key attached to a lease, so that it is deleted when the lease expires
+func (c *EtcdClient) PutWithLease(ctx context.Context, key string, value []byte, lease int64) error {
+	response, err := c.kv.Put(ctx, key, string(value), etcd_client_v3.WithLease(etcd_client_v3.LeaseID(lease)))
+	if err != nil {
+		return err
+	}
+	klog.V(4).Infof("put %s with lease %x response %v", key, lease, response)
+	return nil
+}
+
+// GrantLease creates a lease that expires ttl seconds from now, returning its ID
+func (c *EtcdClient) GrantLease(ctx context.Context, ttl int64) (int64, error) {
+	response, err := c.client.Grant(ctx, ttl)
+	if err != nil {
+		return 0, err
+	}
+	return int64(response.ID), nil
+}
+
+// LeaseTTL returns the TTL (in seconds) a lease was granted with, or 0 if the lease has expired
+func (c *EtcdClient) LeaseTTL(ctx context.Context, lease int64) (int64, error) {
+	response, err := c.client.TimeToLive(ctx, etcd_client_v3.LeaseID(lease))
+	if err != nil {
+		if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if response.TTL < 0 {
+		return 0, nil
+	}
+	return response.GrantedTTL, nil
+}
+
+func (c *EtcdClient) Delete(ctx context.Context, key string) error {
+	response, err := c.kv.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+	klog.V(4).Infof("delete %s response %v", key, response)
+	return nil
+}
+
+// CurrentRevision returns the current revision of the etcd key-value store
+func (c *EtcdClient) CurrentRevision(ctx context.Context) (int64, error) {
+	response, err := c.kv.Get(ctx, "\x00", etcd_client_v3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return response.Header.Revision, nil
+}
+
+// KeyCount returns the number of keys in the etcd key-value store
+func (c *EtcdClient) KeyCount(ctx context.Context) (int64, error) {
+	response, err := c.kv.Get(ctx, "\x00", etcd_client_v3.WithFromKey(), etcd_client_v3.WithCountOnly())
+	if err != nil {
+		return 0, err
+	}
+	return response.Count, nil
+}
+
+// KeyCountAtRevision returns the number of keys in etcd as of revision, which fails (with ErrCompacted) if the revision has been compacted
+func (c *EtcdClient) KeyCountAtRevision(ctx context.Context, revision int64) (int64, error) {
+	response, err := c.kv.Get(ctx, "\x00", etcd_client_v3.WithFromKey(), etcd_client_v3.WithCountOnly(), etcd_client_v3.WithRev(revision))
+	if err != nil {
+		return 0, err
+	}
+	return response.Count, nil
+}
+
+// HashKV returns the hash of the key-value store at revision (0 for the current revision) on the first endpoint,
+// along with the revision that was hashed.
+func (c *EtcdClient) HashKV(ctx context.Context, revision int64) (uint32, int64, error) {
+	response, err := c.maintenance.HashKV(ctx, c.endpoints[0], revision)
+	if err != nil {
+		return 0, 0, err
+	}
+	return response.Hash, response.Header.Revision, nil
+}
+
+// EndpointStatus is the status of an etcd member
+type EndpointStatus struct {
+	// MemberID is the (decimal) ID of the member, as in EtcdProcessMember
+	MemberID string
+	Revision int64
+	RaftTerm uint64
+	DBSize   int64
+}
+
+// Status returns the status of the member serving the first endpoint
+func (c *EtcdClient) Status(ctx context.Context) (*EndpointStatus, error) {
+	response, err := c.maintenance.Status(ctx, c.endpoints[0])
+	if err != nil {
+		return nil, err
+	}
+	return &EndpointStatus{
+		MemberID: strconv.FormatUint(response.Header.MemberId, 10),
+		Revision: response.Header.Revision,
+		RaftTerm: response.RaftTerm,
+		DBSize:   response.DbSize,
+	}, nil
+}
+
+// ClusterID returns the ID of the etcd cluster, hex encoded as etcd reports it
+func (c *EtcdClient) ClusterID(ctx context.Context) (string, error) {
+	response, err := c.cluster.MemberList(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(response.Header.ClusterId, 16), nil
+}
+
 func (c *EtcdClient) CopyTo(ctx context.Context, dest NodeSink) (int, error) {
+	return c.CopyPrefixTo(ctx, "", dest)
+}
+
+// CopyPrefixTo copies the keys starting with prefix to dest, in key order; an empty prefix copies every key
+func (c *EtcdClient) CopyPrefixTo(ctx context.Context, prefix string, dest NodeSink) (int, error) {
 	count := 0
 
 	limit := etcd_client_v3.WithLimit(1000)
 	sort := etcd_client_v3.WithSort(etcd_client_v3.SortByKey, etcd_client_v3.SortAscend)
+	keyRange := etcd_client_v3.WithFromKey()
+	if prefix != "" {
+		keyRange = etcd_client_v3.WithRange(etcd_client_v3.GetPrefixRangeEnd(prefix))
+	}
 
 	var lastKey string
 	for {
 		etcdFrom := lastKey
+		if etcdFrom == "" {
+			etcdFrom = prefix
+		}
 		if etcdFrom == "" {
 			etcdFrom = "\x00"
 		}
-		response, err := c.kv.Get(ctx, etcdFrom, etcd_client_v3.WithFromKey(), sort, limit)
+		response, err := c.kv.Get(ctx, etcdFrom, keyRange, sort, limit)
 		if err != nil {
 			return count, err
 		}
@@ -205,7 +376,19 @@ func (c *EtcdClient) CopyTo(ctx context.Context, dest NodeSink) (int, error) {
 			}
 			gotMore = true
 			klog.Infof("copying key %q", key)
-			if err := dest.Put(ctx, key, kv.Value); err != nil {
+			if kvSink, ok := dest.(KeyValueSink); ok {
+				err = kvSink.PutKeyValue(ctx, &KeyValue{
+					Key:            key,
+					Value:          kv.Value,
+					CreateRevision: kv.CreateRevision,
+					ModRevision:    kv.ModRevision,
+					Version:        kv.Version,
+					Lease:          kv.Lease,
+				})
+			} else {
+				err = dest.Put(ctx, key, kv.Value)
+			}
+			if err != nil {
 				return count, fmt.Errorf("error writing key %q to destination: %v", key, err)
 			}
 			count++
@@ -275,25 +458,15 @@ func (c *EtcdClient) RemoveMember(ctx context.Context, member *EtcdProcessMember
 	return err
 }
 
-func (c *EtcdClient) SnapshotSave(ctx context.Context, path string) error {
-	out, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("error creating snapshot file: %v", err)
-	}
-	defer out.Close()
-
+// SnapshotSaveTo streams a snapshot of etcd to out
+func (c *EtcdClient) SnapshotSaveTo(ctx context.Context, out io.Writer) error {
 	in, err := c.client.Snapshot(ctx)
 	if err != nil {
 		return fmt.Errorf("error making snapshot: %v", err)
 	}
 	defer in.Close()
 
-	gz := gzip.NewWriter(out)
-	if _, err := io.Copy(gz, in); err != nil {
-		return fmt.Errorf("error copying snapshot: %v", err)
-	}
-
-	if err := gz.Close(); err != nil {
+	if _, err := io.Copy(out, in); err != nil {
 		return fmt.Errorf("error copying snapshot: %v", err)
 	}
 
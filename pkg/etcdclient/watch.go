@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcdclient
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcd_client_v3 "go.etcd.io/etcd/client/v3"
+)
+
+// ErrCompacted is returned (wrapped) when a watch starts from a revision that etcd has already compacted
+var ErrCompacted = rpctypes.ErrCompacted
+
+// KeyChange is a change to a single key
+type KeyChange struct {
+	Revision int64
+	Key      string
+	Value    []byte
+	Deleted  bool
+
+	// Lease is the ID of the lease the key is attached to, or 0 if it has none
+	Lease int64
+}
+
+// WatchBatch is a batch of changes delivered by WatchAll.
+// All the changes from a revision are delivered in the same batch.
+type WatchBatch struct {
+	Changes []KeyChange
+
+	// Err is set on the final batch if the watch failed
+	Err error
+}
+
+// WatchAll watches every key, delivering changes from startRevision onwards.
+// The channel is closed when ctx is cancelled or the watch fails.
+func (c *EtcdClient) WatchAll(ctx context.Context, startRevision int64) <-chan WatchBatch {
+	out := make(chan WatchBatch)
+
+	go func() {
+		defer close(out)
+
+		ctx, cancel := context.WithCancel(etcd_client_v3.WithRequireLeader(ctx))
+		defer cancel()
+
+		watchChan := c.client.Watch(ctx, "\x00", etcd_client_v3.WithFromKey(), etcd_client_v3.WithRev(startRevision))
+		for response := range watchChan {
+			batch := WatchBatch{}
+			if err := response.Err(); err != nil {
+				batch.Err = fmt.Errorf("error watching from revision %d: %w", startRevision, err)
+			}
+			for _, event := range response.Events {
+				batch.Changes = append(batch.Changes, KeyChange{
+					Revision: event.Kv.ModRevision,
+					Key:      string(event.Kv.Key),
+					Value:    event.Kv.Value,
+					Deleted:  event.Type == mvccpb.DELETE,
+					Lease:    event.Kv.Lease,
+				})
+			}
+
+			select {
+			case out <- batch:
+			case <-ctx.Done():
+				return
+			}
+
+			if batch.Err != nil {
+				return
+			}
+		}
+
+		if ctx.Err() == nil {
+			select {
+			case out <- WatchBatch{Err: fmt.Errorf("watch from revision %d closed unexpectedly", startRevision)}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return out
+}
@@ -182,6 +182,45 @@ func (s *FSStore) LoadCA(name string) (*CA, error) {
 	return ca, nil
 }
 
+// ReadKeypairPEM returns the PEM encoded certificate bundle and private key for a keypair, exactly as stored.
+// If either file does not exist, the error satisfies os.IsNotExist.
+func (s *FSStore) ReadKeypairPEM(name string) ([]byte, []byte, error) {
+	certificates, err := os.ReadFile(filepath.Join(s.basedir, name+".crt"))
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err := os.ReadFile(filepath.Join(s.basedir, name+".key"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return certificates, privateKey, nil
+}
+
+// WriteKeypairPEM writes the PEM encoded certificate bundle and private key for a keypair, after checking that they parse
+func (s *FSStore) WriteKeypairPEM(name string, certificates []byte, privateKey []byte) error {
+	if _, err := certutil.ParseCertsPEM(certificates); err != nil {
+		return fmt.Errorf("error parsing certificates for %q: %w", name, err)
+	}
+	if _, err := keyutil.ParsePrivateKeyPEM(privateKey); err != nil {
+		return fmt.Errorf("error parsing private key for %q: %w", name, err)
+	}
+
+	if err := os.MkdirAll(s.basedir, 0700); err != nil {
+		return fmt.Errorf("creating directory %q: %w", s.basedir, err)
+	}
+
+	// We write the key first, because LoadCA treats a certificate without a key as an error
+	keyPath := filepath.Join(s.basedir, name+".key")
+	if err := os.WriteFile(keyPath, privateKey, 0600); err != nil {
+		return fmt.Errorf("writing private key file %q: %w", keyPath, err)
+	}
+	certPath := filepath.Join(s.basedir, name+".crt")
+	if err := os.WriteFile(certPath, certificates, 0644); err != nil {
+		return fmt.Errorf("writing certificate file %q: %w", certPath, err)
+	}
+	return nil
+}
+
 func loadPrivateKey(privateKeyPath string) (*rsa.PrivateKey, error) {
 	privateKeyBytes, err := os.ReadFile(privateKeyPath)
 	if err != nil {
@@ -60,6 +60,14 @@ func (s *StaticStore) RemoveCommand(command commands.Command) error {
 	return fmt.Errorf("StaticStore::RemoveCommand not supported")
 }
 
+func (s *StaticStore) SetCommandResult(command commands.Command, result *protoetcd.CommandResult) error {
+	return fmt.Errorf("StaticStore::SetCommandResult not supported")
+}
+
+func (s *StaticStore) GetCommandResult(cmd *protoetcd.Command) (*protoetcd.CommandResult, error) {
+	return nil, fmt.Errorf("StaticStore::GetCommandResult not supported")
+}
+
 func (s *StaticStore) GetExpectedClusterSpec() (*protoetcd.ClusterSpec, error) {
 	spec := &protoetcd.ClusterSpec{
 		MemberCount: int32(len(s.config.Nodes)),
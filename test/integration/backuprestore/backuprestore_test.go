@@ -113,6 +113,17 @@ func TestBackupRestore(t *testing.T) {
 
 			// We also have to send a restore backup command for a full DR (no common data) scenario
 			backupName := backups[len(backups)-1]
+
+			info, err := backupStore.LoadInfo(backupName)
+			if err != nil {
+				t.Fatalf("error loading info for backup %q: %v", backupName, err)
+			}
+			if info.Revision == 0 || info.RaftTerm == 0 || info.DbSize == 0 || info.KeyCount == 0 {
+				t.Errorf("backup %q did not record the etcd state: %v", backupName, info)
+			}
+			if len(info.Members) != 1 || info.SourceMemberName != info.Members[0].Name {
+				t.Errorf("backup %q did not record the etcd members: %v", backupName, info)
+			}
 			klog.Infof("Adding command to restore backup %q", backupName)
 			h2.AddCommand(&protoetcd.Command{
 				Timestamp: time.Now().UnixNano(),